@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+
+	"snakeai/internal/config"
+	"snakeai/internal/eval"
+	"snakeai/internal/ga"
+	"snakeai/internal/nn"
+)
+
+// ablation is a GA setting -ablate can vary, with the values it tries
+type ablation struct {
+	variants []string
+	set      func(cfg *config.Config, variant string)
+}
+
+var ablations = map[string]ablation{
+	"crossover": {ga.CrossoverOperators, func(cfg *config.Config, v string) { cfg.GA.Crossover = v }},
+	// nsga2 is left out: it ranks by objectives the plain loop does not set
+	"selection": {ga.SelectionSchemes[:len(ga.SelectionSchemes)-1], func(cfg *config.Config, v string) { cfg.GA.Selection = v }},
+}
+
+// ablationNames lists the keys of ablations in sorted order
+func ablationNames() []string {
+	names := make([]string, 0, len(ablations))
+	for name := range ablations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ablationResult is the outcome of one short GA run
+type ablationResult struct {
+	bestFitness, meanFitness float64
+	benchFruits, benchTicks  float64
+	seconds                  float64
+}
+
+// runAblation trains every variant of a GA setting for the same number of
+// generations from the same seeds and prints how each fared. Runs use a
+// plain elitist loop (single-seed evaluation, then breeding) without
+// multi-seed evaluation, resets or NSGA-II, so only the setting differs.
+func runAblation(cfg *config.Config, evaluator *eval.Evaluator, name string, variants []string, generations, runs int) error {
+	a, ok := ablations[name]
+	if !ok {
+		return fmt.Errorf("unknown ablation %q (want %s)", name, strings.Join(ablationNames(), " or "))
+	}
+	if len(variants) == 0 {
+		variants = a.variants
+	}
+	if runs < 1 || generations < 1 {
+		return fmt.Errorf("ablation needs at least one run and one generation")
+	}
+
+	fmt.Printf("Ablation: %s, %d generations x %d runs per variant (population %d, seeds %d..%d)\n",
+		name, generations, runs, cfg.GA.Population, cfg.Seed, cfg.Seed+int64(runs)-1)
+	fmt.Println("Final population best and mean fitness; benchmark of each run's best agent")
+	fmt.Println()
+	fmt.Printf("%-20s %10s %8s %10s %8s %8s %8s %8s\n",
+		"Variant", "Best", "±Std", "Mean", "Fruits", "±Std", "Ticks", "s/run")
+	fmt.Println(strings.Repeat("-", 88))
+
+	for _, variant := range variants {
+		vcfg := *cfg
+		a.set(&vcfg, strings.TrimSpace(variant))
+		var results []ablationResult
+		for r := 0; r < runs; r++ {
+			res, err := ablationRun(&vcfg, evaluator, vcfg.Seed+int64(r), generations)
+			if err != nil {
+				return err
+			}
+			results = append(results, res)
+		}
+		printAblationRow(variant, results)
+	}
+	return nil
+}
+
+// ablationRun evolves a population for the given number of generations and
+// benchmarks its best agent. Variants share seeds, so run r of every
+// variant starts from the same population and plays the same episodes.
+func ablationRun(cfg *config.Config, evaluator *eval.Evaluator, seed int64, generations int) (ablationResult, error) {
+	start := time.Now()
+	breeder, err := ga.NewBreeder(cfg, generations)
+	if err != nil {
+		return ablationResult{}, err
+	}
+	rng := rand.New(rand.NewSource(seed))
+	genomeSize := nn.NewMLP(cfg.ObsDim(), cfg.NN.Hidden1, cfg.NN.Hidden2, 3).GenomeSize()
+	pop := ga.NewPopulation(cfg.GA.Population, genomeSize, rng)
+
+	for gen := 1; gen <= generations; gen++ {
+		evaluator.EvaluatePopulationSingleSeed(pop, uint32(seed+int64(gen)))
+		if cfg.GA.MutationControl == "one_fifth" {
+			evaluator.ScoreParents(pop.Agents, []uint32{uint32(seed + int64(gen))})
+		}
+		step := breeder.Mutation.Next(gen, pop.Agents)
+		pop.Agents = breeder.NextGeneration(pop, step, rng)
+	}
+	evaluator.EvaluatePopulationSingleSeed(pop, uint32(seed+int64(generations)+1))
+
+	var res ablationResult
+	for _, a := range pop.Agents {
+		res.meanFitness += a.Fitness
+	}
+	res.meanFitness /= float64(len(pop.Agents))
+	best := pop.Best()
+	res.bestFitness = best.Fitness
+	bench := evaluator.RunBenchmark([]*ga.Agent{best})[0]
+	res.benchFruits, res.benchTicks = bench.FruitsMean, bench.TicksMean
+	res.seconds = time.Since(start).Seconds()
+	return res, nil
+}
+
+// printAblationRow prints one variant's results averaged over its runs
+func printAblationRow(name string, results []ablationResult) {
+	field := func(get func(ablationResult) float64) (mean, std float64) {
+		for _, r := range results {
+			mean += get(r)
+		}
+		mean /= float64(len(results))
+		for _, r := range results {
+			d := get(r) - mean
+			std += d * d
+		}
+		return mean, math.Sqrt(std / float64(len(results)))
+	}
+	best, bestStd := field(func(r ablationResult) float64 { return r.bestFitness })
+	mean, _ := field(func(r ablationResult) float64 { return r.meanFitness })
+	fruits, fruitsStd := field(func(r ablationResult) float64 { return r.benchFruits })
+	ticks, _ := field(func(r ablationResult) float64 { return r.benchTicks })
+	seconds, _ := field(func(r ablationResult) float64 { return r.seconds })
+
+	fmt.Printf("%-20s %10.1f %8.1f %10.1f %8.2f %8.2f %8.1f %8.2f\n",
+		name, best, bestStd, mean, fruits, fruitsStd, ticks, seconds)
+}
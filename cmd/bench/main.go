@@ -0,0 +1,123 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"snakeai/internal/config"
+	"snakeai/internal/env"
+	"snakeai/internal/eval"
+	"snakeai/internal/logging"
+	"snakeai/internal/policy"
+)
+
+func main() {
+	// Parse flags
+	configPath := flag.String("config", "configs/fruit.yaml", "path to config file")
+	policies := flag.String("policies", "random,wall,greedy,bfs,hamiltonian",
+		"comma-separated policies: scripted names or champion files (path.json)")
+	episodes := flag.Int("episodes", 0, "episodes per policy on consecutive seeds from the first benchmark seed (0 = the configured benchmark seeds)")
+	randomize := flag.Bool("randomize", false, "use eval.benchmark_randomize instead of env.randomize")
+	ablate := flag.String("ablate", "", "instead of policies, compare short GA runs varying one setting: "+strings.Join(ablationNames(), "|"))
+	variants := flag.String("variants", "", "comma-separated values for -ablate (default: all)")
+	generations := flag.Int("generations", 30, "generations per -ablate run")
+	runs := flag.Int("runs", 3, "runs per -ablate variant, on consecutive seeds from the config seed")
+	pressure := flag.Int("pressure", 0, "instead of policies, print the selection pressure of every scheme over N rounds on synthetic fitness")
+	flag.Parse()
+
+	// Load config
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+
+	evaluator := eval.NewEvaluator(cfg)
+	defer evaluator.Close()
+
+	if *pressure > 0 {
+		if err := runPressure(cfg, *pressure); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if *ablate != "" {
+		var names []string
+		if *variants != "" {
+			names = strings.Split(*variants, ",")
+		}
+		if err := runAblation(cfg, evaluator, *ablate, names, *generations, *runs); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	seeds := evaluator.BenchmarkSeeds()
+	if *episodes > 0 {
+		first := seeds[0]
+		seeds = make([]uint32, *episodes)
+		for i := range seeds {
+			seeds[i] = first + uint32(i)
+		}
+	}
+	rnd := eval.Randomization(cfg.Env.Randomize)
+	if *randomize {
+		rnd = eval.Randomization(cfg.Eval.BenchmarkRandomize)
+	}
+
+	fmt.Printf("Benchmark: %s, %d episodes per policy (seeds %d..%d)\n",
+		*configPath, len(seeds), seeds[0], seeds[len(seeds)-1])
+	fmt.Println()
+	fmt.Printf("%-20s %8s %8s %6s %8s %10s %8s   %s\n",
+		"Policy", "Fruits", "±Std", "Max", "Ticks", "Score", "Return", "Deaths W/S/St/T")
+	fmt.Println(strings.Repeat("-", 96))
+
+	for _, name := range strings.Split(*policies, ",") {
+		p, err := loadPolicy(strings.TrimSpace(name), cfg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		printRow(p.Name(), evaluator.EvaluatePolicy(p, seeds, rnd))
+	}
+}
+
+// loadPolicy resolves a scripted policy name or a champion file
+func loadPolicy(name string, cfg *config.Config) (policy.Policy, error) {
+	if filepath.Ext(name) != ".json" {
+		return policy.New(name)
+	}
+	genome, err := logging.LoadChampion(name)
+	if err != nil {
+		return nil, fmt.Errorf("loading champion %s: %w", name, err)
+	}
+	return policy.NewNetwork(filepath.Base(name), genome, cfg.Track.Obs, cfg.NN.Hidden1, cfg.NN.Hidden2), nil
+}
+
+// printRow prints one policy's aggregate results
+func printRow(name string, episodes []env.EpisodeStats) {
+	agg := env.Aggregate(episodes)
+
+	var fruitVar, returnSum float64
+	maxFruits := 0
+	for _, ep := range episodes {
+		d := float64(ep.Fruits) - agg.FruitsMean
+		fruitVar += d * d
+		returnSum += ep.Return
+		if ep.Fruits > maxFruits {
+			maxFruits = ep.Fruits
+		}
+	}
+	n := float64(len(episodes))
+
+	fmt.Printf("%-20s %8.2f %8.2f %6d %8.1f %10.1f %8.2f   %d/%d/%d/%d\n",
+		name, agg.FruitsMean, math.Sqrt(fruitVar/n), maxFruits, agg.TicksMean, agg.ScoreMean, returnSum/n,
+		agg.DeathCounts[env.DeathWall], agg.DeathCounts[env.DeathSelf],
+		agg.DeathCounts[env.DeathStall], agg.DeathCounts[env.DeathTimeout])
+}
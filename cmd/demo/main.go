@@ -0,0 +1,69 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"snakeai/internal/config"
+	"snakeai/internal/demo"
+	"snakeai/internal/env"
+	"snakeai/internal/policy"
+)
+
+func main() {
+	// Parse flags
+	configPath := flag.String("config", "configs/fruit.yaml", "path to config file")
+	policyName := flag.String("policy", "greedy", "scripted policy: random|wall|greedy|bfs|hamiltonian")
+	episodes := flag.Int("episodes", 100, "number of episodes to record")
+	baseSeed := flag.Int("seed", 5000, "seed of the first episode (episodes use consecutive seeds)")
+	outDir := flag.String("out", "", "directory to write trajectories to (empty = only print stats)")
+	format := flag.String("format", "bin", "trajectory format: bin|jsonl")
+	flag.Parse()
+
+	// Load config
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+
+	p, err := policy.New(*policyName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if *format != "bin" && *format != "jsonl" {
+		fmt.Fprintf(os.Stderr, "Error: unknown format %q (want bin or jsonl)\n", *format)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Recording %d %s episodes on %s (obs %s)\n", *episodes, p.Name(), *configPath, cfg.Track.Obs)
+
+	results := make([]env.EpisodeStats, 0, *episodes)
+	steps := 0
+	for i := 0; i < *episodes; i++ {
+		seed := uint32(*baseSeed + i)
+		traj, stats := demo.Record(cfg, p, seed)
+		results = append(results, stats)
+		steps += len(traj.Steps)
+
+		if *outDir != "" {
+			path := filepath.Join(*outDir, fmt.Sprintf("%s_%d.%s", p.Name(), seed, *format))
+			if err := traj.Save(path); err != nil {
+				fmt.Fprintf(os.Stderr, "Error saving trajectory: %v\n", err)
+				os.Exit(1)
+			}
+		}
+	}
+
+	agg := env.Aggregate(results)
+	fmt.Printf("Recorded %d decisions | Mean ticks: %.1f | Mean fruits: %.2f | Deaths: W=%d S=%d St=%d T=%d\n",
+		steps, agg.TicksMean, agg.FruitsMean,
+		agg.DeathCounts[env.DeathWall], agg.DeathCounts[env.DeathSelf],
+		agg.DeathCounts[env.DeathStall], agg.DeathCounts[env.DeathTimeout])
+	if *outDir != "" {
+		fmt.Printf("Trajectories saved to %s\n", *outDir)
+	}
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"snakeai/internal/env"
+	"snakeai/internal/nn"
+)
+
+// terminal switches the controlling terminal into raw mode via stty and
+// restores the previous settings
+type terminal struct {
+	saved string
+}
+
+// rawTerminal puts stdin's terminal into raw mode with echo off. Output
+// post-processing stays on so newlines still return the carriage.
+func rawTerminal() (*terminal, error) {
+	saved, err := stty("-g")
+	if err != nil {
+		return nil, fmt.Errorf("stdin is not a terminal: %w", err)
+	}
+	if _, err := stty("raw", "-echo", "opost"); err != nil {
+		return nil, err
+	}
+	return &terminal{saved: strings.TrimSpace(saved)}, nil
+}
+
+// restore returns the terminal to its original settings
+func (t *terminal) restore() {
+	stty(t.saved)
+}
+
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	return string(out), err
+}
+
+// readKeys decodes arrow keys, WASD and hjkl from r into absolute directions
+// until q, Ctrl+C, Esc or end of input, then closes quit
+func readKeys(r io.Reader, dirs chan<- env.Direction, quit chan<- struct{}) {
+	defer close(quit)
+	buf := make([]byte, 16)
+	for {
+		n, err := r.Read(buf)
+		if err != nil {
+			return
+		}
+		for i := 0; i < n; i++ {
+			var dir env.Direction
+			switch buf[i] {
+			case 'w', 'W', 'k':
+				dir = env.DirUp
+			case 'd', 'D', 'l':
+				dir = env.DirRight
+			case 's', 'S', 'j':
+				dir = env.DirDown
+			case 'a', 'A', 'h':
+				dir = env.DirLeft
+			case 'q', 'Q', 3: // 3 = Ctrl+C, not a signal in raw mode
+				return
+			case 27: // Esc, or the start of an arrow key sequence ESC [ A-D
+				if i+2 >= n || buf[i+1] != '[' {
+					return
+				}
+				switch buf[i+2] {
+				case 'A':
+					dir = env.DirUp
+				case 'C':
+					dir = env.DirRight
+				case 'B':
+					dir = env.DirDown
+				case 'D':
+					dir = env.DirLeft
+				default:
+					i += 2
+					continue
+				}
+				i += 2
+			default:
+				continue
+			}
+			select {
+			case dirs <- dir:
+			default: // drop keys pressed faster than ticks
+			}
+		}
+	}
+}
+
+// humanResult summarises how a human game compared with the ghost
+type humanResult struct {
+	quit      bool // the player quit before the game ended
+	ghostSame int  // ticks where the human chose the ghost's action
+	ghostSeen int  // ticks with a ghost suggestion
+}
+
+// playHuman runs the game at one tick per frameDelay, steering from key
+// presses. Each tick consumes at most one queued key, so quick double turns
+// land on consecutive ticks; with no key the snake goes straight. If ghost
+// is not nil, its choice for every tick is shown beside the board. record
+// is called with each action after it is applied.
+func playHuman(game *env.Game, frameDelay time.Duration, ghost *nn.MLP, features *env.FeatureExtractor,
+	display *Display, record func(env.Action)) (humanResult, error) {
+	term, err := rawTerminal()
+	if err != nil {
+		return humanResult{}, err
+	}
+	defer term.restore()
+
+	dirs := make(chan env.Direction, 4)
+	quit := make(chan struct{})
+	go readKeys(os.Stdin, dirs, quit)
+
+	var res humanResult
+	ghostAction := -1
+	if ghost != nil {
+		ghostAction = ghost.Forward(features.Extract(game))
+	}
+
+	// Wait for the first key so the player is ready
+	display.RenderHuman(game, -1, ghostAction, res.ghostSame, res.ghostSeen,
+		"  Steer with arrow keys, WASD or hjkl; q quits. Press a direction to start.")
+	var first env.Direction
+	select {
+	case first = <-dirs:
+	case <-quit:
+		res.quit = true
+		return res, nil
+	}
+	pending := []env.Direction{first}
+
+	ticker := time.NewTicker(frameDelay)
+	defer ticker.Stop()
+	for game.Alive {
+		action := env.ActionStraight
+		if len(pending) > 0 {
+			if a, ok := game.ActionFor(pending[0]); ok {
+				action = a
+			}
+			pending = pending[1:]
+		}
+
+		if ghost != nil {
+			res.ghostSeen++
+			if int(action) == ghostAction {
+				res.ghostSame++
+			}
+		}
+		game.Step(action)
+		record(action)
+
+		if ghost != nil && game.Alive {
+			ghostAction = ghost.Forward(features.Extract(game))
+		} else {
+			ghostAction = -1
+		}
+		display.RenderHuman(game, int(action), ghostAction, res.ghostSame, res.ghostSeen, "")
+
+		if !game.Alive {
+			break
+		}
+		select {
+		case <-ticker.C:
+		case <-quit:
+			res.quit = true
+			return res, nil
+		}
+	drain:
+		for {
+			select {
+			case d := <-dirs:
+				pending = append(pending, d)
+			default:
+				break drain
+			}
+		}
+	}
+	return res, nil
+}
@@ -5,12 +5,13 @@ import (
 	"flag"
 	"fmt"
 	"os"
-	"os/exec"
-	"runtime"
+	"os/signal"
 	"time"
 
 	"snakeai/internal/config"
 	"snakeai/internal/env"
+	"snakeai/internal/eval"
+	"snakeai/internal/export"
 	"snakeai/internal/nn"
 )
 
@@ -29,9 +30,19 @@ func main() {
 	championPath := flag.String("champion", "artifacts/champion_final.json", "path to champion JSON")
 	seed := flag.Uint("seed", 12345, "random seed for the game")
 	delay := flag.Int("delay", 100, "delay between frames in milliseconds")
+	fps := flag.Float64("fps", 0, "frames per second (overrides -delay when set)")
+	mono := flag.Bool("mono", false, "disable colour (also off when NO_COLOR is set or stdout is not a terminal)")
 	noDisplay := flag.Bool("no-display", false, "run without display (just print stats)")
 	noTimeout := flag.Bool("no-timeout", false, "disable tick cap (play until death)")
 	noStall := flag.Bool("no-stall", false, "disable stall detection")
+	randomize := flag.Bool("randomize", false, "randomise spawn position, heading and body shape")
+	trajectoryPath := flag.String("trajectory", "", "record the episode to a trajectory file (.jsonl or .bin)")
+	human := flag.Bool("human", false, "steer the snake yourself with arrow keys/WASD (uses -delay as tick speed)")
+	ghost := flag.Bool("ghost", false, "in -human mode, show what the champion would do each tick")
+	recordPath := flag.String("record", "", "record the game's actions to a replay file (.json)")
+	exportPath := flag.String("export", "", "export the episode as an animation (.gif, .png/.apng) or head-trail plot (.svg)")
+	replayPath := flag.String("replay", "", "with -export, export this replay file instead of playing")
+	cell := flag.Int("cell", 16, "pixels per board cell in exported images")
 	flag.Parse()
 
 	// Load config
@@ -48,6 +59,90 @@ func main() {
 	if *noStall {
 		cfg.Env.StallWindow = 999999
 	}
+	if *randomize {
+		cfg.Env.Randomize.Position = true
+		cfg.Env.Randomize.Heading = true
+		cfg.Env.Randomize.Shape = true
+	}
+
+	frameDelay := time.Duration(*delay) * time.Millisecond
+	if *fps > 0 {
+		frameDelay = time.Duration(float64(time.Second) / *fps)
+	}
+
+	// Export a saved replay without playing
+	if *replayPath != "" {
+		if *exportPath == "" {
+			fmt.Fprintln(os.Stderr, "Error: -replay needs -export")
+			os.Exit(1)
+		}
+		r, err := env.LoadReplay(*replayPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading replay: %v\n", err)
+			os.Exit(1)
+		}
+		saveExport(export.FromReplay(r, *cell), *exportPath, frameDelay)
+		return
+	}
+
+	// Create game
+	rnd := eval.Randomization(cfg.Env.Randomize)
+	game := eval.NewGame(cfg.Env, rnd, uint32(*seed))
+
+	// Create feature extractor
+	features := env.NewFeatureExtractor(cfg.Track.Obs)
+
+	// Display helper
+	display := NewDisplay(game.Width, game.Height, !*mono && colorSupported(), cfg.Track.Obs)
+
+	// Optional replay recording and image export
+	var replay *env.Replay
+	if *recordPath != "" {
+		replay = eval.NewReplay(cfg.Env, rnd, uint32(*seed))
+	}
+	var recorder *export.Recorder
+	if *exportPath != "" {
+		recorder = export.NewRecorder(game.Width, game.Height, *cell)
+		recorder.Capture(game)
+	}
+
+	// Human mode: the champion is only needed for the ghost
+	if *human {
+		var ghostMLP *nn.MLP
+		if *ghost {
+			champion, err := loadChampion(*championPath)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error loading champion: %v\n", err)
+				os.Exit(1)
+			}
+			ghostMLP = nn.NewMLP(cfg.ObsDim(), cfg.NN.Hidden1, cfg.NN.Hidden2, 3)
+			ghostMLP.SetWeights(champion.Genome)
+		}
+
+		res, err := playHuman(game, frameDelay, ghostMLP, features, display, func(a env.Action) {
+			if replay != nil {
+				replay.Record(a)
+			}
+			if recorder != nil {
+				recorder.Capture(game)
+			}
+		})
+		display.Close()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if res.quit {
+			fmt.Println("\nQuit.")
+		}
+		stats := printStats(game, uint32(*seed))
+		if res.ghostSeen > 0 {
+			fmt.Printf("Agreed with the champion on %d of %d ticks\n", res.ghostSame, res.ghostSeen)
+		}
+		saveReplay(replay, stats, *recordPath)
+		saveExport(recorder, *exportPath, frameDelay)
+		return
+	}
 
 	// Load champion
 	champion, err := loadChampion(*championPath)
@@ -62,58 +157,109 @@ func main() {
 	fmt.Println("Press Ctrl+C to exit")
 	fmt.Println()
 
-	// Create game
-	game := env.NewGame(
-		cfg.Env.Width,
-		cfg.Env.Height,
-		cfg.Env.StartLength,
-		cfg.Env.TickCap,
-		cfg.Env.StallWindow,
-		cfg.Env.FruitEnabled,
-		uint32(*seed),
-	)
-
 	// Create neural network
 	mlp := nn.NewMLP(cfg.ObsDim(), cfg.NN.Hidden1, cfg.NN.Hidden2, 3)
 	mlp.SetWeights(champion.Genome)
 
-	// Create feature extractor
-	features := env.NewFeatureExtractor(cfg.Track.Obs)
+	// Optional per-tick trajectory recording
+	var traj *env.Trajectory
+	if *trajectoryPath != "" {
+		traj = env.NewTrajectory(uint32(*seed), cfg.ObsDim(), 3)
+	}
 
-	// Display helper
-	display := NewDisplay(cfg.Env.Width, cfg.Env.Height)
+	// Stop cleanly on Ctrl+C so the cursor is restored and stats still print
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	ticker := time.NewTicker(frameDelay)
+	defer ticker.Stop()
 
 	// Run game loop
-	frameDelay := time.Duration(*delay) * time.Millisecond
-	
+loop:
 	for game.Alive {
 		// Get observation and action
+		tick := game.Tick
 		obs := features.Extract(game)
 		action := mlp.Forward(obs)
 
 		// Display current state
 		if !*noDisplay {
-			display.Render(game, action)
-			time.Sleep(frameDelay)
+			display.Render(game, action, obs, mlp.Outputs())
+			select {
+			case <-ticker.C:
+			case <-interrupt:
+				break loop
+			}
 		}
 
 		// Step game
-		game.Step(env.Action(action))
+		if replay != nil {
+			replay.Record(env.Action(action))
+		}
+		res := game.Step(env.Action(action))
+		if recorder != nil {
+			recorder.Capture(game)
+		}
+		if traj != nil {
+			traj.Record(tick, obs, env.Action(action), mlp.Outputs(), res)
+		}
 	}
 
 	// Final display
 	if !*noDisplay {
-		display.Render(game, -1)
+		display.Render(game, -1, nil, nil)
+		display.Close()
 	}
 
 	// Print final stats
-	stats := game.Stats(uint32(*seed))
+	stats := printStats(game, uint32(*seed))
+	saveReplay(replay, stats, *recordPath)
+	saveExport(recorder, *exportPath, frameDelay)
+
+	if traj != nil {
+		if err := traj.Save(*trajectoryPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Error saving trajectory: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Trajectory saved to %s (%d steps)\n", *trajectoryPath, len(traj.Steps))
+	}
+}
+
+// printStats prints the end-of-game summary and returns the episode stats
+func printStats(game *env.Game, seed uint32) env.EpisodeStats {
+	stats := game.Stats(seed)
 	fmt.Println()
 	fmt.Println("═══════════════════════════════════")
 	fmt.Printf("  Game Over! Death: %s\n", stats.Death)
 	fmt.Printf("  Ticks: %d, Fruits: %d\n", stats.Ticks, stats.Fruits)
 	fmt.Printf("  Progress Sum: %.2f\n", stats.ProgressSum)
+	fmt.Printf("  Return: %.2f\n", stats.Return)
 	fmt.Println("═══════════════════════════════════")
+	return stats
+}
+
+// saveReplay writes a recorded replay, if any, with its final stats
+func saveReplay(replay *env.Replay, stats env.EpisodeStats, path string) {
+	if replay == nil {
+		return
+	}
+	replay.SetFinalStats(stats)
+	if err := replay.Save(path); err != nil {
+		fmt.Fprintf(os.Stderr, "Error saving replay: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Replay saved to %s (%d actions)\n", path, len(replay.Actions))
+}
+
+// saveExport writes captured frames, if any, to an image file
+func saveExport(recorder *export.Recorder, path string, frameDelay time.Duration) {
+	if recorder == nil {
+		return
+	}
+	if err := recorder.Save(path, frameDelay); err != nil {
+		fmt.Fprintf(os.Stderr, "Error exporting: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Exported %d frames to %s\n", recorder.Frames(), path)
 }
 
 func loadChampion(path string) (*ChampionData, error) {
@@ -127,117 +273,3 @@ func loadChampion(path string) (*ChampionData, error) {
 	}
 	return &champion, nil
 }
-
-// Display handles terminal rendering
-type Display struct {
-	width  int
-	height int
-}
-
-// NewDisplay creates a new display
-func NewDisplay(width, height int) *Display {
-	return &Display{width: width, height: height}
-}
-
-// Render draws the game state to terminal
-func (d *Display) Render(game *env.Game, action int) {
-	clearScreen()
-
-	// Build grid
-	grid := make([][]rune, d.height)
-	for y := 0; y < d.height; y++ {
-		grid[y] = make([]rune, d.width)
-		for x := 0; x < d.width; x++ {
-			grid[y][x] = '·'
-		}
-	}
-
-	// Place fruit
-	if game.FruitEnabled {
-		fruit := game.Fruit
-		if fruit.X >= 0 && fruit.X < d.width && fruit.Y >= 0 && fruit.Y < d.height {
-			grid[fruit.Y][fruit.X] = '🍎'
-		}
-	}
-
-	// Place snake body
-	for i := len(game.Snake) - 1; i >= 0; i-- {
-		p := game.Snake[i]
-		if p.X >= 0 && p.X < d.width && p.Y >= 0 && p.Y < d.height {
-			if i == 0 {
-				// Head - show direction
-				grid[p.Y][p.X] = directionHead(game.Dir)
-			} else {
-				grid[p.Y][p.X] = '█'
-			}
-		}
-	}
-
-	// Draw border and grid
-	fmt.Print("┌")
-	for x := 0; x < d.width; x++ {
-		fmt.Print("──")
-	}
-	fmt.Println("┐")
-
-	for y := 0; y < d.height; y++ {
-		fmt.Print("│")
-		for x := 0; x < d.width; x++ {
-			c := grid[y][x]
-			if c == '🍎' {
-				fmt.Print("🍎")
-			} else if c == '·' {
-				fmt.Print(" ·")
-			} else {
-				fmt.Printf(" %c", c)
-			}
-		}
-		fmt.Println("│")
-	}
-
-	fmt.Print("└")
-	for x := 0; x < d.width; x++ {
-		fmt.Print("──")
-	}
-	fmt.Println("┘")
-
-	// Status line
-	actionStr := []string{"STRAIGHT", "LEFT", "RIGHT"}
-	actionDisplay := "---"
-	if action >= 0 && action < 3 {
-		actionDisplay = actionStr[action]
-	}
-
-	fmt.Printf("  Tick: %3d | Fruits: %d | Length: %d | Action: %s\n",
-		game.Tick, game.FruitsEaten, len(game.Snake), actionDisplay)
-
-	if !game.Alive {
-		fmt.Printf("  💀 DEAD: %s\n", game.DeathReason)
-	}
-}
-
-func directionHead(dir env.Direction) rune {
-	switch dir {
-	case env.DirUp:
-		return '▲'
-	case env.DirRight:
-		return '▶'
-	case env.DirDown:
-		return '▼'
-	case env.DirLeft:
-		return '◀'
-	}
-	return 'O'
-}
-
-func clearScreen() {
-	var cmd *exec.Cmd
-	if runtime.GOOS == "windows" {
-		cmd = exec.Command("cmd", "/c", "cls")
-	} else {
-		cmd = exec.Command("clear")
-	}
-	cmd.Stdout = os.Stdout
-	cmd.Run()
-}
-
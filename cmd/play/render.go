@@ -0,0 +1,323 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"snakeai/internal/env"
+	"snakeai/internal/nn"
+)
+
+// ANSI escape sequences
+const (
+	ansiReset       = "\x1b[0m"
+	ansiBold        = "\x1b[1m"
+	ansiDim         = "\x1b[2m"
+	ansiRed         = "\x1b[31m"
+	ansiGreen       = "\x1b[32m"
+	ansiCyan        = "\x1b[36m"
+	ansiBrightGreen = "\x1b[1;92m"
+	ansiYellow      = "\x1b[93m"
+	ansiClearScreen = "\x1b[2J"
+	ansiClearLine   = "\x1b[K"
+	ansiHideCursor  = "\x1b[?25l"
+	ansiShowCursor  = "\x1b[?25h"
+)
+
+// panelWidth is the visible width of the observation/output panel
+const panelWidth = 36
+
+// Display renders frames in place with ANSI cursor control. Each frame is
+// composed into a back buffer of lines and only lines that differ from the
+// frame on screen are rewritten, so redraws do not flicker.
+type Display struct {
+	width  int
+	height int
+	color  bool
+	names  []string // observation labels for the panel
+
+	out     *bufio.Writer
+	front   []string // lines currently on screen
+	back    []string // frame being composed
+	cols    int      // terminal size the front buffer was drawn for
+	rows    int
+	started bool
+	probs   []float64
+}
+
+// NewDisplay creates a display for a width x height board. obsType labels
+// the observation panel; color enables ANSI colours.
+func NewDisplay(width, height int, color bool, obsType string) *Display {
+	return &Display{
+		width:  width,
+		height: height,
+		color:  color,
+		names:  env.ObsNames(obsType),
+		out:    bufio.NewWriterSize(os.Stdout, 16*1024),
+	}
+}
+
+// colorSupported reports whether stdout is a terminal that should get colour
+func colorSupported() bool {
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	info, err := os.Stdout.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// envSize reads the terminal size from $COLUMNS and $LINES, 0 if unset
+func envSize() (cols, rows int) {
+	cols, _ = strconv.Atoi(os.Getenv("COLUMNS"))
+	rows, _ = strconv.Atoi(os.Getenv("LINES"))
+	return cols, rows
+}
+
+// Render draws the game state with a panel of the observation vector and
+// network outputs. obs and outputs may be nil to leave the panel out.
+func (d *Display) Render(game *env.Game, action int, obs, outputs []float32) {
+	var panel []string
+	if obs != nil {
+		panel = d.panel(obs, outputs, action)
+	}
+	d.draw(d.board(game, nil), d.boardWidth(), panel, d.status(game, action))
+}
+
+// RenderHuman draws the player's board and, if ghostAction is set, a second
+// board beside it marking the cell the champion would move into next. note
+// is shown below the status line.
+func (d *Display) RenderHuman(game *env.Game, action, ghostAction, agreed, ticks int, note string) {
+	lines := d.board(game, nil)
+	width := d.boardWidth()
+	footer := d.status(game, action)
+
+	if ghostAction >= 0 {
+		next := game.NextHead(env.Action(ghostAction))
+		right := d.board(game, &next)
+		header := fmt.Sprintf("%-*s   %s", width, " You", " Champion ghost (◆ = its next move)")
+		for i := range lines {
+			lines[i] += "   " + right[i]
+		}
+		lines = append([]string{header}, lines...)
+		width = 2*width + 3
+
+		agreement := 0.0
+		if ticks > 0 {
+			agreement = 100 * float64(agreed) / float64(ticks)
+		}
+		footer = append(footer, fmt.Sprintf("  Ghost: %s | Agreement: %d/%d (%.0f%%)",
+			actionName(ghostAction), agreed, ticks, agreement))
+	}
+	if note != "" {
+		footer = append(footer, note)
+	}
+	d.draw(lines, width, nil, footer)
+}
+
+// Close moves the cursor below the last frame and shows it again
+func (d *Display) Close() {
+	if !d.started {
+		return
+	}
+	fmt.Fprintf(d.out, "\x1b[%d;1H%s", len(d.front)+1, ansiShowCursor)
+	d.out.Flush()
+	d.started = false
+}
+
+// boardWidth is the visible width of a board including its border
+func (d *Display) boardWidth() int {
+	return 2*d.width + 2
+}
+
+// draw lays out a frame for the current terminal size and writes it. The
+// panel goes beside the board when it fits and below the status otherwise;
+// lines that do not fit the terminal height are dropped.
+func (d *Display) draw(board []string, boardWidth int, panel, footer []string) {
+	cols, rows := terminalSize()
+	d.back = d.back[:0]
+
+	switch {
+	case cols > 0 && rows > 0 && (cols < boardWidth || rows < len(board)+len(footer)):
+		d.back = append(d.back, fmt.Sprintf("Terminal too small: need %dx%d, have %dx%d",
+			boardWidth, len(board)+len(footer), cols, rows))
+	case panel != nil && (cols == 0 || boardWidth+3+panelWidth <= cols):
+		blank := strings.Repeat(" ", boardWidth)
+		for i := 0; i < len(board) || i < len(panel); i++ {
+			line := blank
+			if i < len(board) {
+				line = board[i]
+			}
+			if i < len(panel) {
+				line += "   " + panel[i]
+			}
+			d.back = append(d.back, line)
+		}
+		d.back = append(d.back, footer...)
+	default:
+		d.back = append(d.back, board...)
+		d.back = append(d.back, footer...)
+		d.back = append(d.back, panel...)
+	}
+	if rows > 0 && len(d.back) > rows {
+		d.back = d.back[:rows]
+	}
+
+	d.flush(cols, rows)
+}
+
+// flush writes the lines of the back buffer that changed since the last
+// frame, then swaps buffers. A resize forces a full redraw.
+func (d *Display) flush(cols, rows int) {
+	if !d.started || cols != d.cols || rows != d.rows {
+		d.out.WriteString(ansiHideCursor + ansiClearScreen)
+		d.front = d.front[:0]
+		d.cols, d.rows = cols, rows
+		d.started = true
+	}
+
+	for i, line := range d.back {
+		if i < len(d.front) && d.front[i] == line {
+			continue
+		}
+		fmt.Fprintf(d.out, "\x1b[%d;1H%s%s", i+1, line, ansiClearLine)
+	}
+	for i := len(d.back); i < len(d.front); i++ {
+		fmt.Fprintf(d.out, "\x1b[%d;1H%s", i+1, ansiClearLine)
+	}
+	d.out.Flush()
+
+	d.front, d.back = d.back, d.front
+}
+
+// paint wraps s in an ANSI colour when colour is enabled
+func (d *Display) paint(code, s string) string {
+	if !d.color {
+		return s
+	}
+	return code + s + ansiReset
+}
+
+// board renders the grid with its border as lines of text. If mark is set,
+// that cell is drawn as a ghost move marker.
+func (d *Display) board(game *env.Game, mark *env.Point) []string {
+	// Build grid of two-column cells
+	empty := d.paint(ansiDim, " ·")
+	grid := make([][]string, d.height)
+	for y := 0; y < d.height; y++ {
+		grid[y] = make([]string, d.width)
+		for x := 0; x < d.width; x++ {
+			grid[y][x] = empty
+		}
+	}
+	inside := func(p env.Point) bool {
+		return p.X >= 0 && p.X < d.width && p.Y >= 0 && p.Y < d.height
+	}
+
+	// Place fruit
+	if game.FruitEnabled && inside(game.Fruit) {
+		grid[game.Fruit.Y][game.Fruit.X] = "🍎"
+	}
+
+	// Place snake body
+	body := d.paint(ansiGreen, " █")
+	for i := game.Length() - 1; i >= 0; i-- {
+		p := game.Segment(i)
+		if !inside(p) {
+			continue
+		}
+		if i == 0 {
+			// Head - show direction
+			grid[p.Y][p.X] = d.paint(ansiBrightGreen, " "+string(directionHead(game.Dir)))
+		} else {
+			grid[p.Y][p.X] = body
+		}
+	}
+
+	// Place ghost marker
+	if mark != nil && inside(*mark) {
+		grid[mark.Y][mark.X] = d.paint(ansiYellow, " ◆")
+	}
+
+	// Draw border and grid
+	lines := make([]string, 0, d.height+2)
+	lines = append(lines, "┌"+strings.Repeat("──", d.width)+"┐")
+	for y := 0; y < d.height; y++ {
+		lines = append(lines, "│"+strings.Join(grid[y], "")+"│")
+	}
+	lines = append(lines, "└"+strings.Repeat("──", d.width)+"┘")
+	return lines
+}
+
+// status returns the lines below the board
+func (d *Display) status(game *env.Game, action int) []string {
+	lines := []string{fmt.Sprintf("  Tick: %3d | Fruits: %d | Length: %d | Action: %s",
+		game.Tick, game.FruitsEaten, game.Length(), actionName(action))}
+
+	if !game.Alive {
+		lines = append(lines, d.paint(ansiRed, fmt.Sprintf("  💀 DEAD: %s", game.DeathReason)))
+	}
+	return lines
+}
+
+// panel returns the observation vector and network outputs, with the
+// softmax probability of each action and a marker on the chosen one
+func (d *Display) panel(obs, outputs []float32, action int) []string {
+	lines := []string{d.paint(ansiBold, "Observation")}
+	for i, v := range obs {
+		name := fmt.Sprintf("obs[%d]", i)
+		if i < len(d.names) {
+			name = d.names[i]
+		}
+		lines = append(lines, fmt.Sprintf("%-12s %+6.2f %s", name, v, d.bar(math.Abs(float64(v)), 10)))
+	}
+	if len(outputs) == 0 {
+		return lines
+	}
+
+	if len(d.probs) != len(outputs) {
+		d.probs = make([]float64, len(outputs))
+	}
+	nn.Softmax(outputs, d.probs)
+	lines = append(lines, "", d.paint(ansiBold, "Network outputs"))
+	for i, v := range outputs {
+		line := fmt.Sprintf("%-8s %+7.2f %5.1f%% %s", actionName(i), v, 100*d.probs[i], d.bar(d.probs[i], 10))
+		if i == action {
+			line += d.paint(ansiYellow, " ◀")
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// bar draws frac (clamped to 0..1) as a horizontal bar of the given width
+func (d *Display) bar(frac float64, width int) string {
+	n := int(math.Round(math.Max(0, math.Min(1, frac)) * float64(width)))
+	return d.paint(ansiCyan, strings.Repeat("█", n)) + d.paint(ansiDim, strings.Repeat("░", width-n))
+}
+
+// actionName returns the display name of an action, or --- for none
+func actionName(action int) string {
+	actionStr := []string{"STRAIGHT", "LEFT", "RIGHT"}
+	if action >= 0 && action < 3 {
+		return actionStr[action]
+	}
+	return "---"
+}
+
+func directionHead(dir env.Direction) rune {
+	switch dir {
+	case env.DirUp:
+		return '▲'
+	case env.DirRight:
+		return '▶'
+	case env.DirDown:
+		return '▼'
+	case env.DirLeft:
+		return '◀'
+	}
+	return 'O'
+}
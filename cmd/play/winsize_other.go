@@ -0,0 +1,9 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd)
+
+package main
+
+// terminalSize falls back to $COLUMNS and $LINES where the window size
+// cannot be queried
+func terminalSize() (cols, rows int) {
+	return envSize()
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"snakeai/internal/report"
+)
+
+func main() {
+	// Parse flags
+	out := flag.String("o", "report.html", "output file; .md writes Markdown, anything else HTML (- for stdout)")
+	format := flag.String("format", "", "output format, overriding the -o extension: html|md")
+	metric := flag.String("metric", "robust_score", "final-performance metric for significance tests: "+strings.Join(report.MetricNames(), "|"))
+	labels := flag.String("labels", "", "comma-separated group names (default: the argument base names)")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: report [flags] <run dir or .jsonl>...\n\n")
+		fmt.Fprintf(os.Stderr, "Each argument is one group: a JSONL log, or a directory whose .jsonl logs\n")
+		fmt.Fprintf(os.Stderr, "(searched recursively) are runs of the same variant, e.g. one per seed.\n")
+		fmt.Fprintf(os.Stderr, "The first group is the baseline for significance tests.\n\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	var names []string
+	if *labels != "" {
+		names = strings.Split(*labels, ",")
+		if len(names) != flag.NArg() {
+			fmt.Fprintf(os.Stderr, "Error: %d labels for %d groups\n", len(names), flag.NArg())
+			os.Exit(1)
+		}
+	}
+
+	// Load one group per argument
+	var groups []*report.Group
+	for i, path := range flag.Args() {
+		name := strings.TrimSuffix(filepath.Base(filepath.Clean(path)), ".jsonl")
+		if names != nil {
+			name = strings.TrimSpace(names[i])
+		}
+		g, err := report.LoadGroup(name, path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading runs: %v\n", err)
+			os.Exit(1)
+		}
+		groups = append(groups, g)
+	}
+
+	r, err := report.Build(groups, *metric)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *format == "" {
+		*format = "html"
+		if filepath.Ext(*out) == ".md" {
+			*format = "md"
+		}
+	}
+	write := r.WriteHTML
+	switch *format {
+	case "html":
+	case "md":
+		write = r.WriteMarkdown
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown format %q (want html or md)\n", *format)
+		os.Exit(1)
+	}
+
+	if *out == "-" {
+		err = write(os.Stdout)
+	} else {
+		var f *os.File
+		f, err = os.Create(*out)
+		if err == nil {
+			err = write(f)
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing report: %v\n", err)
+		os.Exit(1)
+	}
+	if *out != "-" {
+		runs := 0
+		for _, g := range groups {
+			runs += len(g.Runs)
+		}
+		fmt.Printf("Wrote %s (%d groups, %d runs)\n", *out, len(groups), runs)
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"path/filepath"
+	"time"
+
+	"snakeai/internal/config"
+	"snakeai/internal/demo"
+	"snakeai/internal/env"
+	"snakeai/internal/eval"
+	"snakeai/internal/ga"
+	"snakeai/internal/logging"
+)
+
+// runBC clones demonstrations into a policy network, benchmarks it and saves
+// it as a champion that cmd/play can load or -init can seed the GA with
+func runBC(cfg *config.Config, demoPaths []string, evaluator *eval.Evaluator, logger *logging.Logger) error {
+	if len(demoPaths) == 0 {
+		return fmt.Errorf("-algo bc needs demonstrations (-demos)")
+	}
+	data, err := demo.Load(demoPaths, cfg.Track.Obs)
+	if err != nil {
+		return fmt.Errorf("loading demonstrations: %w", err)
+	}
+	if data.Len() == 0 {
+		return fmt.Errorf("no demonstrations found in %v", demoPaths)
+	}
+	fmt.Printf("Behaviour cloning on %d decisions, %d epochs\n", data.Len(), cfg.BC.Epochs)
+
+	startTime := time.Now()
+	rng := rand.New(rand.NewSource(cfg.Seed))
+	done := logger.Time("clone")
+	genome := demo.Clone(cfg, data, rng, func(s demo.EpochStats) {
+		fmt.Printf("Epoch %4d | Loss: %.4f | Accuracy: %.3f\n", s.Epoch, s.Loss, s.Accuracy)
+		logger.LogDiagnostic("loss", s.Loss)
+		logger.LogDiagnostic("accuracy", s.Accuracy)
+	})
+	done()
+
+	// Evaluate the clone and log it as a single summary
+	done = logger.Time("evaluate")
+	agent := &ga.Agent{Genome: genome}
+	evaluator.EvaluateCandidatesMultiSeed([]*ga.Agent{agent})
+	agent.Stats = evaluator.EvaluateAgent(agent, uint32(cfg.Seed))
+	agent.Fitness = agent.Stats.Score
+	logger.LogRobustScore(agent.RobustScore)
+	logger.LogBenchmark(evaluator.RunBenchmark([]*ga.Agent{agent}))
+	done()
+	if err := logger.LogEpisodes(cfg.BC.Epochs, []env.EpisodeStats{agent.Stats}); err != nil {
+		return fmt.Errorf("writing logs: %w", err)
+	}
+
+	fmt.Println("---")
+	fmt.Printf("Cloning complete in %v\n", time.Since(startTime))
+	fmt.Printf("Clone: Fitness=%.1f, RobustScore=%.1f, Ticks=%d, Fruits=%d\n",
+		agent.Fitness, agent.RobustScore, agent.Stats.Ticks, agent.Stats.Fruits)
+
+	championPath := filepath.Join("artifacts", "champion_bc.json")
+	if err := logging.SaveChampion(championPath, agent, cfg.BC.Epochs); err != nil {
+		return fmt.Errorf("saving champion: %w", err)
+	}
+	fmt.Printf("Saved %s\n", championPath)
+	return nil
+}
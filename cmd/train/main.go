@@ -6,19 +6,31 @@ import (
 	"math/rand"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"snakeai/internal/config"
-	"snakeai/internal/env"
+	"snakeai/internal/dashboard"
+	"snakeai/internal/dist"
 	"snakeai/internal/eval"
 	"snakeai/internal/ga"
 	"snakeai/internal/logging"
+	"snakeai/internal/metrics"
 )
 
 func main() {
 	// Parse command line flags
 	configPath := flag.String("config", "configs/wall.yaml", "path to config file")
-	generations := flag.Int("generations", 1000, "number of generations to run")
+	generations := flag.Int("generations", 1000, "number of generations (or gradient-based training iterations) to run")
+	algo := flag.String("algo", "ga", "training algorithm: ga|reinforce|ppo|dqn|bc")
+	demos := flag.String("demos", "", "comma-separated demonstration files or directories for -algo bc (trajectories or replays)")
+	initChampion := flag.String("init", "", "champion file to seed the GA population with instead of random genomes")
+	remoteWorkers := flag.String("remote", "", "comma-separated worker addresses (host:port or unix:/path) for distributed evaluation")
+	httpAddr := flag.String("http", "", "serve a live training dashboard on this address (e.g. :8080)")
+	metricsAddr := flag.String("metrics", "", "serve Prometheus/OpenMetrics metrics at /metrics on this address (e.g. :9090)")
+	verbosity := flag.String("verbosity", "", "console log detail, overriding the config: quiet|normal|verbose")
+	seed := flag.Int64("seed", 0, "random seed, overriding the config (0 = keep)")
+	logDir := flag.String("logdir", "", "write all logs to this run directory instead of the configured paths")
 	flag.Parse()
 
 	// Load config
@@ -27,11 +39,31 @@ func main() {
 		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
 		os.Exit(1)
 	}
+	if *verbosity != "" {
+		cfg.Logging.Verbosity = *verbosity
+	}
+	if *seed != 0 {
+		cfg.Seed = *seed
+	}
+	if *logDir != "" {
+		cfg.Logging.CSVPath = filepath.Join(*logDir, "run.csv")
+		cfg.Logging.JSONPath = filepath.Join(*logDir, "run.jsonl")
+		cfg.Logging.ColumnarPath = filepath.Join(*logDir, "run.col")
+		cfg.Logging.TensorBoardDir = filepath.Join(*logDir, "tb")
+	}
 
 	fmt.Printf("Snake AI Trainer - Track: %s\n", cfg.Track.Mode)
 	fmt.Printf("Config: %s\n", *configPath)
 	fmt.Printf("Obs: %s (dim=%d), Hidden: %d\n", cfg.Track.Obs, cfg.ObsDim(), cfg.NN.Hidden1)
-	fmt.Printf("Population: %d, Elites: %d, Tournament K: %d\n", cfg.GA.Population, cfg.GA.Elites, cfg.GA.TournamentK)
+	switch *algo {
+	case "ga":
+		fmt.Printf("Population: %d, Elites: %d, Tournament K: %d, Selection: %s, Crossover: %s\n",
+			cfg.GA.Population, cfg.GA.Elites, cfg.GA.TournamentK, cfg.GA.Selection, cfg.GA.Crossover)
+	case "bc":
+		fmt.Printf("Algorithm: bc, Epochs: %d, Batch: %d, LR: %g\n", cfg.BC.Epochs, cfg.BC.BatchSize, cfg.BC.LearningRate)
+	default:
+		fmt.Printf("Algorithm: %s, Episodes/iter: %d, LR: %g\n", *algo, cfg.RL.EpisodesPerIter, cfg.RL.LearningRate)
+	}
 	fmt.Println("---")
 
 	// Initialize RNG
@@ -41,23 +73,143 @@ func main() {
 	genomeSize := calcGenomeSize(cfg.ObsDim(), cfg.NN.Hidden1, cfg.NN.Hidden2, 3)
 	fmt.Printf("Genome size: %d weights\n", genomeSize)
 
-	// Initialize population
-	pop := ga.NewPopulation(cfg.GA.Population, genomeSize, rng)
+	// Initialize population, around a seed genome if given
+	var pop *ga.Population
+	if *initChampion != "" {
+		seed, err := logging.LoadChampion(*initChampion)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading seed champion: %v\n", err)
+			os.Exit(1)
+		}
+		if len(seed) != genomeSize {
+			fmt.Fprintf(os.Stderr, "Error: seed champion has %d weights, config needs %d\n", len(seed), genomeSize)
+			os.Exit(1)
+		}
+		pop = ga.NewSeededPopulation(cfg.GA.Population, seed, cfg.BC.SeedSigma, rng)
+		fmt.Printf("Population seeded from %s (sigma=%.3f)\n", *initChampion, cfg.BC.SeedSigma)
+	} else {
+		pop = ga.NewPopulation(cfg.GA.Population, genomeSize, rng)
+	}
 
 	// Create evaluator
 	evaluator := eval.NewEvaluator(cfg)
+	defer evaluator.Close()
+
+	// Distribute evaluation to remote workers if requested
+	if *remoteWorkers != "" {
+		coordinator, err := dist.NewCoordinator(cfg, strings.Split(*remoteWorkers, ","))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error connecting to workers: %v\n", err)
+			os.Exit(1)
+		}
+		defer coordinator.Close()
+		evaluator.SetBackend(coordinator)
+		fmt.Printf("Distributed evaluation: %d/%d workers connected\n", coordinator.Live(), len(strings.Split(*remoteWorkers, ",")))
+	}
 
-	// Create logger
-	logger, err := logging.NewLogger(cfg.Logging.CSVPath, cfg.Logging.JSONPath)
+	// Create logger with the configured sinks
+	logger, err := logging.Open(cfg.Logging)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error creating logger: %v\n", err)
 		os.Exit(1)
 	}
-	if err := logger.Init(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error initializing logger: %v\n", err)
+	defer func() {
+		if err := logger.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to close logs: %v\n", err)
+		}
+	}()
+
+	// Snapshot the effective config beside the logs for cmd/report
+	if err := cfg.Save(logging.ConfigSnapshotPath(cfg.Logging.JSONPath)); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to save config snapshot: %v\n", err)
+	}
+
+	// Live dashboard, fed by the logger and the training loop
+	var dash *dashboard.Server
+	if *httpAddr != "" {
+		dash = dashboard.New(*httpAddr)
+		if err := dash.Start(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error starting dashboard: %v\n", err)
+			os.Exit(1)
+		}
+		defer dash.Close()
+		logger.AddListener(dash.PublishGeneration)
+		fmt.Printf("Dashboard: %s\n", dash.URL())
+	}
+
+	// Metrics endpoint for Prometheus scrapers
+	var prom *metrics.Training
+	if *metricsAddr != "" {
+		prom = metrics.NewTraining(evaluator.Usage)
+		srv, err := prom.Registry.Serve(*metricsAddr)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error starting metrics endpoint: %v\n", err)
+			os.Exit(1)
+		}
+		defer srv.Close()
+		logger.AddListener(prom.ObserveGeneration)
+		fmt.Printf("Metrics: http://%s/metrics\n", srv.Addr)
+	}
+
+	// Behaviour cloning and gradient-based training replace the GA loop entirely
+	if *algo == "bc" {
+		var paths []string
+		if *demos != "" {
+			paths = strings.Split(*demos, ",")
+		}
+		if err := runBC(cfg, paths, evaluator, logger); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if *algo != "ga" {
+		if err := runRL(cfg, *algo, *generations, evaluator, logger, monitors{dash: dash, prom: prom}); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	logger.TrackEnvSteps(evaluator.EnvSteps)
+
+	// Multi-objective mode keeps the evaluated parents for (mu + lambda)
+	// environmental selection
+	nsga := cfg.GA.Selection == "nsga2"
+	var parents []*ga.Agent
+	if nsga {
+		if err := checkObjectives(cfg.GA.Objectives); err != nil {
+			fmt.Fprintf(os.Stderr, "Error in config: %v\n", err)
+			os.Exit(1)
+		}
+		logger.TrackHypervolume(cfg.GA.HVReference)
+		fmt.Printf("NSGA-II objectives: %s\n", strings.Join(cfg.GA.Objectives, ", "))
+	}
+	if cfg.GA.DiversityMeasure != "" {
+		if _, err := (ga.Diversity{}).Measure(cfg.GA.DiversityMeasure); err != nil {
+			fmt.Fprintf(os.Stderr, "Error in config: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Diversity trigger: %s < %g (reset %.0f%%, mutation x%g)\n", cfg.GA.DiversityMeasure,
+			cfg.GA.DiversityThreshold, 100*cfg.GA.ResetFraction, cfg.GA.DiversityMutationBoost)
+	}
+	breeder, err := ga.NewBreeder(cfg, *generations)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error in config: %v\n", err)
 		os.Exit(1)
 	}
-	defer logger.Close()
+	if cfg.Eval.PopulationSeeds > 1 {
+		racing := ""
+		if cfg.Eval.Racing {
+			racing = fmt.Sprintf(", racing (first rung %d, eta %g)", cfg.Eval.RacingMinSeeds, cfg.Eval.RacingEta)
+		}
+		fmt.Printf("Population evaluation: %d seeds per generation, %s score%s\n",
+			cfg.Eval.PopulationSeeds, cfg.Eval.PopulationScore, racing)
+	}
+	if cfg.GA.MutationControl != "fixed" || cfg.GA.MutationSchedule != "constant" {
+		fmt.Printf("Mutation: %s control, %s schedule (rate %g -> %g, sigma %g -> %g)\n",
+			cfg.GA.MutationControl, cfg.GA.MutationSchedule, cfg.GA.MutationRate, cfg.GA.MutationRateEnd,
+			cfg.GA.MutationSigma, cfg.GA.MutationSigmaEnd)
+	}
 
 	// Track best ever for stability
 	var bestEver *ga.Agent
@@ -66,15 +218,53 @@ func main() {
 
 	// Main training loop
 	for gen := 1; gen <= *generations; gen++ {
-		genSeed := uint32(cfg.Seed + int64(gen))
+		genSeeds := generationSeeds(cfg.Seed, gen, cfg.Eval.PopulationSeeds)
+		genSeed := genSeeds[0]
+
+		// 1. Evaluate population on the generation's seeds (one by default).
+		// NSGA-II parents play them again with their offspring, so that the
+		// merged fronts compare episodes on the same seeds.
+		done := logger.Time("evaluate")
+		evaluated := pop
+		if nsga {
+			merged := append(append([]*ga.Agent(nil), parents...), pop.Agents...)
+			evaluated = &ga.Population{Agents: merged}
+		}
+		evaluator.EvaluatePopulation(evaluated, genSeeds)
+		if cfg.GA.MutationControl == "one_fifth" {
+			// Compare offspring with their parents on the same episodes
+			evaluator.ScoreParents(pop.Agents, genSeeds)
+		}
 
-		// 1. Evaluate population with single seed (fast)
-		evaluator.EvaluatePopulationSingleSeed(pop, genSeed)
+		// Adapt mutation to how the fresh offspring fared against their parents
+		step := breeder.Mutation.Next(gen, pop.Agents)
 
-		// 2. Log generation summary
-		if cfg.Logging.EveryGenSummary {
-			logger.LogGeneration(gen, pop)
+		// 1b. NSGA-II: merge with parents and keep the best fronts
+		if nsga {
+			ga.SetObjectives(evaluated.Agents, cfg.GA.Objectives)
+			pop.Agents = ga.EnvironmentalSelection(evaluated.Agents, cfg.GA.Population)
+		}
+		done()
+
+		// 2. Measure diversity and check for collapse
+		done = logger.Time("diversity")
+		diversity := ga.MeasureDiversity(pop.Agents)
+		done()
+		logger.LogDiversity(diversity)
+		if dash != nil {
+			dash.PublishDiversity(gen, diversity)
 		}
+		collapsed := false
+		if cfg.GA.DiversityMeasure != "" {
+			value, _ := diversity.Measure(cfg.GA.DiversityMeasure)
+			collapsed = value < cfg.GA.DiversityThreshold
+			if collapsed {
+				logger.LogIntervention(fmt.Sprintf("%s %.3g < %g: reset %.0f%% of offspring, mutation x%g",
+					cfg.GA.DiversityMeasure, value, cfg.GA.DiversityThreshold, 100*cfg.GA.ResetFraction, cfg.GA.DiversityMutationBoost))
+				step.Boost = cfg.GA.DiversityMutationBoost
+			}
+		}
+		logger.LogMutation(step)
 
 		// 3. Get top-K candidates for multi-seed evaluation
 		pop.SortByFitness()
@@ -100,7 +290,9 @@ func main() {
 		}
 
 		// 4. Multi-seed evaluation for candidates
+		done = logger.Time("multiseed")
 		evaluator.EvaluateCandidatesMultiSeed(candidates)
+		done()
 
 		// 5. Find best by robustness
 		var bestRobust *ga.Agent
@@ -110,21 +302,42 @@ func main() {
 			}
 		}
 
+		if bestRobust != nil {
+			logger.LogRobustScore(bestRobust.RobustScore)
+		}
+
 		// Update best ever
 		if bestEver == nil || (bestRobust != nil && bestRobust.RobustScore > bestEver.RobustScore) {
 			bestEver = bestRobust.Clone()
+			if dash != nil {
+				replay, _ := evaluator.EvaluateWithReplay(bestEver, genSeed)
+				dash.PublishChampion(gen, replay)
+			}
 		}
 
-		// 6. Debug: log top-N
-		if gen%10 == 0 && cfg.Logging.TopNDebug > 0 {
-			logger.LogTopK(pop.Agents, cfg.Logging.TopNDebug)
-		}
-
-		// 7. Benchmark evaluation
+		// 6. Benchmark evaluation
 		if cfg.Eval.BenchmarkEvery > 0 && gen%cfg.Eval.BenchmarkEvery == 0 {
+			done = logger.Time("benchmark")
 			benchAgents := pop.TopK(5)
-			results := evaluator.RunBenchmark(benchAgents)
-			logger.LogBenchmark(gen, results)
+			logger.LogBenchmark(evaluator.RunBenchmark(benchAgents))
+
+			if eval.Randomization(cfg.Eval.BenchmarkRandomize).Enabled() {
+				logger.LogGeneralisation(evaluator.RunGeneralisationBenchmark(benchAgents))
+			}
+			done()
+		}
+
+		// 7. Log generation summary with the results staged above
+		if cfg.Logging.EveryGenSummary {
+			if err := logger.LogGeneration(gen, pop); err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing logs: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		// Debug: log top-N
+		if gen%10 == 0 && cfg.Logging.TopNDebug > 0 {
+			logger.LogTopK(pop.Agents, cfg.Logging.TopNDebug)
 		}
 
 		// 8. Save champion
@@ -145,13 +358,25 @@ func main() {
 		}
 
 		// 10. Create next generation
-		nextGen := createNextGeneration(pop, cfg, rng)
+		done = logger.Time("breed")
+		var nextGen []*ga.Agent
+		if nsga {
+			parents = append([]*ga.Agent(nil), pop.Agents...)
+			nextGen = breeder.Offspring(parents, cfg.GA.Population, step, rng)
+		} else {
+			nextGen = createNextGeneration(pop, cfg, breeder, step, rng, collapsed)
+		}
 		pop.Agents = nextGen
+		done()
 	}
 
 	elapsed := time.Since(startTime)
 	fmt.Println("---")
 	fmt.Printf("Training complete! %d generations in %v\n", *generations, elapsed)
+	if u := evaluator.Usage(); u.CacheHits > 0 {
+		fmt.Printf("Evaluation cache: %d of %d episodes reused (%.1f%%)\n",
+			u.CacheHits, u.CacheHits+u.CacheMisses, 100*float64(u.CacheHits)/float64(u.CacheHits+u.CacheMisses))
+	}
 	if bestEver != nil {
 		fmt.Printf("Best ever: Fitness=%.1f, RobustScore=%.1f, Ticks=%d, Fruits=%d\n",
 			bestEver.Fitness, bestEver.RobustScore, bestEver.Stats.Ticks, bestEver.Stats.Fruits)
@@ -162,40 +387,73 @@ func main() {
 			fmt.Fprintf(os.Stderr, "Warning: failed to save final champion: %v\n", err)
 		}
 	}
-}
-
-// createNextGeneration creates the next generation via selection, crossover, and mutation
-func createNextGeneration(pop *ga.Population, cfg *config.Config, rng *rand.Rand) []*ga.Agent {
-	newAgents := make([]*ga.Agent, cfg.GA.Population)
 
-	// 1. Keep elites
-	pop.SortByFitness()
-	for i := 0; i < cfg.GA.Elites && i < len(pop.Agents); i++ {
-		newAgents[i] = pop.Agents[i].Clone()
+	// Save the final Pareto set as one champion per trade-off
+	if nsga && len(parents) > 0 {
+		front := ga.ParetoFront(parents)
+		fmt.Printf("Pareto set: %d champions (hypervolume %.2f)\n",
+			len(front), ga.Hypervolume(front, cfg.GA.HVReference))
+		for i, a := range front {
+			path := filepath.Join("artifacts", "pareto", fmt.Sprintf("champion_pareto_%02d.json", i))
+			if err := logging.SaveChampion(path, a, *generations); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to save Pareto champion: %v\n", err)
+			}
+		}
 	}
+}
 
-	// 2. Create selection pool
-	pool := ga.SelectionPool(pop, cfg.GA.SelectionPool)
-
-	// 3. Fill rest with offspring
-	for i := cfg.GA.Elites; i < cfg.GA.Population; i++ {
-		// Select parents
-		p1, p2 := ga.SelectParents(pool, cfg.GA.TournamentK, rng)
-
-		// Crossover
-		child := ga.CreateChild(p1, p2, cfg.GA.CrossoverRate, rng)
+// monitors are the optional live views of a run; nil members are disabled
+type monitors struct {
+	dash *dashboard.Server
+	prom *metrics.Training
+}
 
-		// Mutation
-		ga.MutateAgent(child, cfg.GA.MutationRate, cfg.GA.MutationSigma, cfg.GA.ResetMutationP, rng)
+// checkObjectives rejects unknown NSGA-II objective names
+func checkObjectives(names []string) error {
+	for _, name := range names {
+		known := false
+		for _, n := range ga.ObjectiveNames {
+			if n == name {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("unknown objective %q (want one of %s)", name, strings.Join(ga.ObjectiveNames, ", "))
+		}
+	}
+	return nil
+}
 
-		newAgents[i] = child
+// generationSeeds returns the k seeds every agent plays in generation gen.
+// Generations get disjoint seed ranges; with k = 1 the seed is seed+gen.
+func generationSeeds(seed int64, gen, k int) []uint32 {
+	seeds := make([]uint32, k)
+	for i := range seeds {
+		seeds[i] = uint32(seed + int64((gen-1)*k+1+i))
 	}
+	return seeds
+}
 
-	// 4. Optionally reset worst fraction
-	if cfg.GA.ResetFraction > 0 && rng.Float64() < 0.1 { // 10% chance per generation
+// createNextGeneration creates the next generation via selection, crossover,
+// and mutation with the generation's mutation step. collapsed reports that
+// the diversity trigger fired.
+func createNextGeneration(pop *ga.Population, cfg *config.Config, breeder *ga.Breeder, step ga.Mutation, rng *rand.Rand, collapsed bool) []*ga.Agent {
+	// 1-3. Keep elites and fill the rest with offspring
+	newAgents := breeder.NextGeneration(pop, step, rng)
+
+	// 4. Optionally reset worst fraction: on diversity collapse if a trigger
+	// is configured, otherwise by chance
+	reset := collapsed
+	if cfg.GA.DiversityMeasure == "" {
+		reset = cfg.GA.ResetFraction > 0 && rng.Float64() < 0.1 // 10% chance per generation
+	}
+	if cfg.GA.ResetFraction > 0 && reset {
 		numReset := int(float64(cfg.GA.Population) * cfg.GA.ResetFraction)
 		for i := cfg.GA.Population - numReset; i < cfg.GA.Population; i++ {
 			if i >= cfg.GA.Elites { // Don't reset elites
+				newAgents[i].Bred = false // no longer descends from its parents
+				newAgents[i].ParentGenomes = nil
 				for j := range newAgents[i].Genome {
 					newAgents[i].Genome[j] = float32(rng.NormFloat64() * 0.5)
 				}
@@ -222,36 +480,3 @@ func calcGenomeSize(inputSize, hidden1, hidden2, outputSize int) int {
 	}
 	return size
 }
-
-// runScriptedTest runs a simple scripted policy to verify the environment works
-func runScriptedTest(cfg *config.Config) {
-	fmt.Println("Running scripted baseline test...")
-
-	game := env.NewGame(
-		cfg.Env.Width,
-		cfg.Env.Height,
-		cfg.Env.StartLength,
-		cfg.Env.TickCap,
-		cfg.Env.StallWindow,
-		cfg.Env.FruitEnabled,
-		12345,
-	)
-
-	// Simple policy: go straight, turn right if wall ahead
-	for game.Alive {
-		action := env.ActionStraight
-		if game.IsDangerWall(env.ActionStraight) {
-			if !game.IsDangerWall(env.ActionRight) {
-				action = env.ActionRight
-			} else if !game.IsDangerWall(env.ActionLeft) {
-				action = env.ActionLeft
-			}
-		}
-		game.Step(action)
-	}
-
-	stats := game.Stats(12345)
-	fmt.Printf("Scripted test: Ticks=%d, Death=%s\n", stats.Ticks, stats.Death)
-	fmt.Println("If ticks < 50 on 10x10, there may be a bug in the environment.")
-}
-
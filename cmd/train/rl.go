@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"snakeai/internal/config"
+	"snakeai/internal/eval"
+	"snakeai/internal/ga"
+	"snakeai/internal/logging"
+	"snakeai/internal/rl"
+)
+
+// runRL trains the policy network with a gradient-based algorithm. Each
+// iteration logs like a GA generation, and the greedy policy is tracked and
+// saved as a champion so cmd/play can load it.
+func runRL(cfg *config.Config, algo string, iterations int, evaluator *eval.Evaluator, logger *logging.Logger,
+	mon monitors) error {
+	trainer, err := rl.NewTrainer(cfg, algo, evaluator)
+	if err != nil {
+		return err
+	}
+	logger.TrackEnvSteps(trainer.EnvSteps)
+
+	var bestEver *ga.Agent
+	startTime := time.Now()
+
+	for iter := 1; iter <= iterations; iter++ {
+		done := logger.Time("train")
+		update := trainer.Iterate()
+		done()
+
+		// 1. Stage optimiser diagnostics
+		switch algo {
+		case "dqn":
+			logger.LogDiagnostic("td_loss", update.ValueLoss)
+			logger.LogDiagnostic("epsilon", update.Epsilon)
+		case "ppo":
+			logger.LogDiagnostic("kl", update.KL)
+			logger.LogDiagnostic("clip_frac", update.ClipFrac)
+			fallthrough
+		default:
+			logger.LogDiagnostic("policy_loss", update.PolicyLoss)
+			logger.LogDiagnostic("value_loss", update.ValueLoss)
+			logger.LogDiagnostic("entropy", update.Entropy)
+		}
+
+		// 2. Multi-seed evaluation of the greedy policy
+		done = logger.Time("multiseed")
+		agent := &ga.Agent{Genome: trainer.Policy()}
+		evaluator.EvaluateCandidatesMultiSeed([]*ga.Agent{agent})
+		iterSeed := uint32(cfg.Seed + int64(iter))
+		agent.Stats = evaluator.EvaluateAgent(agent, iterSeed)
+		agent.Fitness = agent.Stats.Score
+		logger.LogRobustScore(agent.RobustScore)
+		done()
+
+		// Update best ever
+		if bestEver == nil || agent.RobustScore > bestEver.RobustScore {
+			bestEver = agent
+			if mon.dash != nil {
+				replay, _ := evaluator.EvaluateWithReplay(agent, iterSeed)
+				mon.dash.PublishChampion(iter, replay)
+			}
+		}
+
+		// 3. Benchmark evaluation
+		if cfg.Eval.BenchmarkEvery > 0 && iter%cfg.Eval.BenchmarkEvery == 0 {
+			done = logger.Time("benchmark")
+			benchAgents := []*ga.Agent{agent}
+			logger.LogBenchmark(evaluator.RunBenchmark(benchAgents))
+
+			if eval.Randomization(cfg.Eval.BenchmarkRandomize).Enabled() {
+				logger.LogGeneralisation(evaluator.RunGeneralisationBenchmark(benchAgents))
+			}
+			done()
+		}
+
+		// 4. Log rollout summary with the results staged above
+		if cfg.Logging.EveryGenSummary {
+			if err := logger.LogEpisodes(iter, update.Episodes); err != nil {
+				return fmt.Errorf("writing logs: %w", err)
+			}
+		}
+
+		// 5. Save champion
+		if cfg.Logging.SaveChampionEvery > 0 && iter%cfg.Logging.SaveChampionEvery == 0 {
+			championPath := filepath.Join("artifacts", fmt.Sprintf("champion_%s_iter%d.json", algo, iter))
+			if err := logging.SaveChampion(championPath, agent, iter); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to save champion: %v\n", err)
+			}
+		}
+	}
+
+	elapsed := time.Since(startTime)
+	fmt.Println("---")
+	fmt.Printf("Training complete! %d %s iterations (%d env steps) in %v\n", iterations, algo, trainer.EnvSteps(), elapsed)
+	if bestEver != nil {
+		fmt.Printf("Best ever: Fitness=%.1f, RobustScore=%.1f, Ticks=%d, Fruits=%d\n",
+			bestEver.Fitness, bestEver.RobustScore, bestEver.Stats.Ticks, bestEver.Stats.Fruits)
+
+		championPath := filepath.Join("artifacts", fmt.Sprintf("champion_%s.json", algo))
+		if err := logging.SaveChampion(championPath, bestEver, iterations); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to save final champion: %v\n", err)
+		}
+	}
+	return nil
+}
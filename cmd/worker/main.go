@@ -0,0 +1,48 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"runtime"
+	"syscall"
+
+	"snakeai/internal/dist"
+)
+
+func main() {
+	// Parse flags
+	listen := flag.String("listen", ":7070", "address to serve on (host:port, tcp:host:port or unix:/path)")
+	flag.Parse()
+
+	l, err := dist.Listen(*listen)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error listening on %s: %v\n", *listen, err)
+		os.Exit(1)
+	}
+
+	worker := dist.NewWorker()
+
+	// Shut down cleanly on Ctrl+C
+	sig := make(chan os.Signal, 1)
+	stopping := make(chan struct{})
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sig
+		close(stopping)
+		l.Close()
+	}()
+
+	fmt.Printf("Snake AI Worker - listening on %s (%d cores)\n", l.Addr(), runtime.NumCPU())
+	err = worker.Serve(l)
+	worker.Close()
+	select {
+	case <-stopping:
+	default:
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error serving: %v\n", err)
+			os.Exit(1)
+		}
+	}
+}
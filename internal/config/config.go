@@ -1,7 +1,10 @@
 package config
 
 import (
+	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
@@ -16,6 +19,8 @@ type Config struct {
 	Eval    EvalConfig   `yaml:"eval"`
 	Logging LogConfig    `yaml:"logging"`
 	Fitness FitnessConfig `yaml:"fitness"`
+	RL      RLConfig     `yaml:"rl"`
+	BC      BCConfig     `yaml:"bc"`
 }
 
 // TrackConfig defines the training track
@@ -33,6 +38,31 @@ type EnvConfig struct {
 	TickCap      int  `yaml:"tick_cap"`
 	StallWindow  int  `yaml:"stall_window"`
 	FruitEnabled bool `yaml:"fruit_enabled"`
+	Randomize    RandomizeConfig `yaml:"randomize"`
+	Reward       RewardConfig    `yaml:"reward"`
+}
+
+// RewardConfig defines the per-tick reward scheme returned by each step
+type RewardConfig struct {
+	Fruit   float64 `yaml:"fruit"`   // eating a fruit
+	Step    float64 `yaml:"step"`    // every tick
+	Closer  float64 `yaml:"closer"`  // moving closer to the fruit
+	Farther float64 `yaml:"farther"` // moving away from the fruit
+	Wall    float64 `yaml:"wall"`    // dying on a wall
+	Self    float64 `yaml:"self"`    // dying on the body
+	Stall   float64 `yaml:"stall"`   // running out the stall window
+	Timeout float64 `yaml:"timeout"` // reaching the tick cap
+}
+
+// RandomizeConfig defines per-episode start state and board size randomisation
+type RandomizeConfig struct {
+	Position  bool `yaml:"position"`   // random spawn position
+	Heading   bool `yaml:"heading"`    // random initial heading
+	Shape     bool `yaml:"shape"`      // random initial body shape
+	MinWidth  int  `yaml:"min_width"`  // board width range (0 = fixed width)
+	MaxWidth  int  `yaml:"max_width"`
+	MinHeight int  `yaml:"min_height"` // board height range (0 = fixed height)
+	MaxHeight int  `yaml:"max_height"`
 }
 
 // NNConfig defines neural network architecture
@@ -49,10 +79,76 @@ type GAConfig struct {
 	SelectionPool   int     `yaml:"selection_pool"`
 	TournamentK     int     `yaml:"tournament_k"`
 	CrossoverRate   float64 `yaml:"crossover_rate"`
+	Crossover       string  `yaml:"crossover"` // uniform|single_point|two_point|arithmetic|blx|sbx|neuron
+	BLXAlpha        float64 `yaml:"blx_alpha"` // blx interval extension (default 0.5)
+	SBXEta          float64 `yaml:"sbx_eta"`   // sbx distribution index (default 10)
 	MutationRate    float64 `yaml:"mutation_rate"`
 	MutationSigma   float64 `yaml:"mutation_sigma"`
 	ResetMutationP  float64 `yaml:"reset_mutation_p"`
 	ResetFraction   float64 `yaml:"reset_fraction"`
+
+	// Mutation step-size control. The schedule anneals mutation_rate and
+	// mutation_sigma towards their _end values over the run; one_fifth then
+	// scales sigma by the 1/5th success rule, while self_adaptive gives
+	// every agent its own log-normally mutated sigma.
+	MutationControl  string  `yaml:"mutation_control"`   // fixed|one_fifth|self_adaptive
+	MutationSchedule string  `yaml:"mutation_schedule"`  // constant|linear|exponential|cosine
+	MutationRateEnd  float64 `yaml:"mutation_rate_end"`  // rate at the last generation (default mutation_rate)
+	MutationSigmaEnd float64 `yaml:"mutation_sigma_end"` // sigma at the last generation (default mutation_sigma)
+	MutationSigmaMin float64 `yaml:"mutation_sigma_min"` // adaptive sigma lower bound (default 0.001)
+	MutationSigmaMax float64 `yaml:"mutation_sigma_max"` // adaptive sigma upper bound (default 1)
+	SuccessTarget    float64 `yaml:"success_target"`     // one_fifth target success rate, in (0, 1) (default 0.2)
+	SuccessFactor    float64 `yaml:"success_factor"`     // one_fifth sigma factor per generation, in (0, 1) (default 0.85)
+	SelfAdaptTau     float64 `yaml:"self_adapt_tau"`     // self_adaptive learning rate (default 1/sqrt(genome size))
+
+	// Diversity collapse trigger: while diversity_measure is below
+	// diversity_threshold the worst reset_fraction of offspring are
+	// re-randomised and mutation is scaled by diversity_mutation_boost.
+	// Without a measure the reset fires at random in 10% of generations.
+	DiversityMeasure       string  `yaml:"diversity_measure"`        // pairwise_distance|weight_std|behaviour_distance|death_entropy|unique_fraction
+	DiversityThreshold     float64 `yaml:"diversity_threshold"`      // collapse below this value
+	DiversityMutationBoost float64 `yaml:"diversity_mutation_boost"` // mutation rate and sigma multiplier while collapsed (default 1)
+
+	Selection   string    `yaml:"selection"`    // tournament|roulette|sus|linear_rank|exponential_rank|boltzmann|mu_lambda|mu_plus_lambda|nsga2
+	Objectives  []string  `yaml:"objectives"`   // nsga2 objectives, all maximised
+	HVReference []float64 `yaml:"hv_reference"` // hypervolume reference point (default origin)
+
+	RankPressure         float64 `yaml:"rank_pressure"`         // linear_rank: expected selections of the best per draw, in [1, 2] (default 1.5)
+	RankBase             float64 `yaml:"rank_base"`             // exponential_rank: weight ratio between consecutive ranks (default 0.95)
+	BoltzmannTemperature float64 `yaml:"boltzmann_temperature"` // boltzmann: temperature in fitness standard deviations (default 1)
+}
+
+// RLConfig defines policy-gradient trainer parameters (REINFORCE and PPO)
+type RLConfig struct {
+	EpisodesPerIter int     `yaml:"episodes_per_iter"` // episodes collected per update
+	LearningRate    float64 `yaml:"learning_rate"`     // policy Adam step size
+	ValueLR         float64 `yaml:"value_lr"`          // value baseline Adam step size
+	Gamma           float64 `yaml:"gamma"`             // reward discount
+	GAELambda       float64 `yaml:"gae_lambda"`        // PPO advantage smoothing
+	ClipEps         float64 `yaml:"clip_eps"`          // PPO probability ratio clip
+	Epochs          int     `yaml:"epochs"`            // PPO passes over each batch
+	MinibatchSize   int     `yaml:"minibatch_size"`    // PPO transitions per gradient step
+	EntropyCoef     float64 `yaml:"entropy_coef"`      // entropy bonus weight
+	MaxGradNorm     float64 `yaml:"max_grad_norm"`     // gradient L2 norm clip
+
+	// DQN
+	ReplaySize     int     `yaml:"replay_size"`     // replay buffer capacity (transitions)
+	ReplayBatch    int     `yaml:"replay_batch"`    // transitions per gradient step
+	LearningStarts int     `yaml:"learning_starts"` // env steps before the first update
+	TrainEvery     int     `yaml:"train_every"`     // env steps between updates
+	TargetUpdate   int     `yaml:"target_update"`   // env steps between target network syncs
+	DoubleDQN      bool    `yaml:"double_dqn"`      // select next actions with the online network
+	EpsilonStart   float64 `yaml:"epsilon_start"`   // initial exploration rate
+	EpsilonEnd     float64 `yaml:"epsilon_end"`     // final exploration rate
+	EpsilonDecay   int     `yaml:"epsilon_decay"`   // env steps to anneal epsilon linearly
+}
+
+// BCConfig defines behaviour cloning parameters
+type BCConfig struct {
+	Epochs       int     `yaml:"epochs"`        // passes over the demonstrations
+	BatchSize    int     `yaml:"batch_size"`    // decisions per gradient step
+	LearningRate float64 `yaml:"learning_rate"` // Adam step size
+	SeedSigma    float64 `yaml:"seed_sigma"`    // noise on clones of a seed genome in the GA population
 }
 
 // EvalConfig defines evaluation parameters
@@ -64,6 +160,22 @@ type EvalConfig struct {
 	BenchmarkEvery    int     `yaml:"benchmark_every"`
 	BenchmarkSeeds    []int   `yaml:"benchmark_seeds"`
 	Workers           int     `yaml:"workers"`
+	BatchSize         int     `yaml:"batch_size"`   // episodes stepped in lockstep per worker job
+	CacheSize         int     `yaml:"cache_size"`   // episode results cached by genome, seed and env (default 100000, -1 disables)
+	ElitePolicy       string  `yaml:"elite_policy"` // already-evaluated agents: reevaluate|keep|lifetime
+
+	// Population evaluation: every agent plays the same population_seeds
+	// seeds each generation and its fitness is population_score over them
+	PopulationSeeds int     `yaml:"population_seeds"` // default 1
+	PopulationScore string  `yaml:"population_score"` // mean|robust|cvar
+	CVaRAlpha       float64 `yaml:"cvar_alpha"`       // cvar: worst fraction of episodes averaged (default 0.25)
+	Racing          bool    `yaml:"racing"`           // successive halving: only promising agents play every seed
+	RacingEta       float64 `yaml:"racing_eta"`       // racing: keep 1/eta of agents and multiply seeds by eta per rung (default 2)
+	RacingMinSeeds  int     `yaml:"racing_min_seeds"` // racing: seeds in the first rung (default 1)
+
+	// Generalisation suite: benchmark seeds replayed under this randomisation
+	// and compared against the fixed start
+	BenchmarkRandomize RandomizeConfig `yaml:"benchmark_randomize"`
 }
 
 // LogConfig defines logging parameters
@@ -74,18 +186,48 @@ type LogConfig struct {
 	ReplayEvery       int    `yaml:"replay_every"`
 	CSVPath           string `yaml:"csv_path"`
 	JSONPath          string `yaml:"json_path"`
+	Sinks             []string `yaml:"sinks"`           // console|csv|jsonl|columnar|tensorboard
+	Verbosity         string   `yaml:"verbosity"`       // console detail: quiet|normal|verbose
+	ColumnarPath      string   `yaml:"columnar_path"`   // columnar log for analysis tools
+	TensorBoardDir    string   `yaml:"tensorboard_dir"` // directory for TensorBoard event files
 }
 
-// FitnessConfig defines fitness function parameters
+// FitnessConfig defines fitness function parameters. Fitness is the weighted
+// sum of Terms; when no terms are given they are derived from Mode and the
+// scalar weights below.
 type FitnessConfig struct {
-	Mode         string  `yaml:"mode"` // wall|self|fruit|multi
-	WallPenalty  float64 `yaml:"wall_penalty"`
-	SelfPenalty  float64 `yaml:"self_penalty"`
-	StallPenalty float64 `yaml:"stall_penalty"`
-	FruitReward  float64 `yaml:"fruit_reward"`
-	SurvivalCap  int     `yaml:"survival_cap"`
-	SurvivalW    float64 `yaml:"survival_w"`
-	ProgressW    float64 `yaml:"progress_w"`
+	Mode           string        `yaml:"mode"` // wall|self|fruit|multi
+	WallPenalty    float64       `yaml:"wall_penalty"`
+	SelfPenalty    float64       `yaml:"self_penalty"`
+	StallPenalty   float64       `yaml:"stall_penalty"`
+	TimeoutPenalty float64       `yaml:"timeout_penalty"`
+	FruitReward    float64       `yaml:"fruit_reward"`
+	SurvivalCap    int           `yaml:"survival_cap"`
+	SurvivalW      float64       `yaml:"survival_w"`
+	ProgressW      float64       `yaml:"progress_w"`
+	Terms          []FitnessTerm `yaml:"terms"`
+}
+
+// FitnessTerm is one named, weighted component of the fitness sum
+type FitnessTerm struct {
+	Name   string  `yaml:"name"`   // see FitnessTermNames
+	Weight float64 `yaml:"weight"` // negative weights act as penalties
+	Cap    float64 `yaml:"cap"`    // upper bound on the raw term value (0 = none)
+}
+
+// FitnessTermNames lists the episode measures a fitness term can use
+var FitnessTermNames = []string{
+	"fruits",        // fruits eaten
+	"ticks",         // ticks survived
+	"survival",      // ticks survived, capped at survival_cap unless cap is set
+	"progress",      // cumulative distance closed toward fruit
+	"death_wall",    // 1 if the episode ended on a wall
+	"death_self",    // 1 if the episode ended on the body
+	"death_stall",   // 1 if the stall window ran out
+	"death_timeout", // 1 if the tick cap was reached
+	"efficiency",    // ticks per fruit (ticks if no fruit was eaten)
+	"length",        // final snake length
+	"coverage",      // fraction of cells the head visited
 }
 
 // Load reads a YAML config file and returns a Config
@@ -102,9 +244,137 @@ func Load(path string) (*Config, error) {
 
 	// Apply defaults
 	applyDefaults(cfg)
+	if err := validate(cfg); err != nil {
+		return nil, err
+	}
 	return cfg, nil
 }
 
+// Save writes the configuration, with defaults applied, as YAML so a run
+// records exactly the settings it used
+func (c *Config) Save(path string) error {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// validate rejects settings that defaults cannot repair
+func validate(cfg *Config) error {
+	if len(cfg.GA.HVReference) != len(cfg.GA.Objectives) {
+		return fmt.Errorf("config: hv_reference has %d values for %d objectives",
+			len(cfg.GA.HVReference), len(cfg.GA.Objectives))
+	}
+	if cfg.GA.SuccessTarget <= 0 || cfg.GA.SuccessTarget >= 1 {
+		return fmt.Errorf("config: success_target must be in (0, 1), got %g", cfg.GA.SuccessTarget)
+	}
+	if cfg.GA.SuccessFactor <= 0 || cfg.GA.SuccessFactor >= 1 {
+		return fmt.Errorf("config: success_factor must be in (0, 1), got %g", cfg.GA.SuccessFactor)
+	}
+	switch cfg.Eval.ElitePolicy {
+	case "reevaluate", "keep", "lifetime":
+	default:
+		return fmt.Errorf("config: unknown elite_policy %q (want reevaluate, keep or lifetime)", cfg.Eval.ElitePolicy)
+	}
+	switch cfg.Eval.PopulationScore {
+	case "mean", "robust", "cvar":
+	default:
+		return fmt.Errorf("config: unknown population_score %q (want mean, robust or cvar)", cfg.Eval.PopulationScore)
+	}
+	if cfg.Eval.PopulationSeeds < 1 {
+		return fmt.Errorf("config: population_seeds must be at least 1, got %d", cfg.Eval.PopulationSeeds)
+	}
+	if cfg.Eval.CVaRAlpha <= 0 || cfg.Eval.CVaRAlpha > 1 {
+		return fmt.Errorf("config: cvar_alpha must be in (0, 1], got %g", cfg.Eval.CVaRAlpha)
+	}
+	if cfg.Eval.Racing && cfg.Eval.RacingEta <= 1 {
+		return fmt.Errorf("config: racing_eta must be greater than 1, got %g", cfg.Eval.RacingEta)
+	}
+	if cfg.Eval.Racing && (cfg.Eval.RacingMinSeeds < 1 || cfg.Eval.RacingMinSeeds > cfg.Eval.PopulationSeeds) {
+		return fmt.Errorf("config: racing_min_seeds must be between 1 and population_seeds (%d), got %d",
+			cfg.Eval.PopulationSeeds, cfg.Eval.RacingMinSeeds)
+	}
+	if cfg.Env.StartLength > cfg.Env.Width {
+		return fmt.Errorf("config: start_length %d does not fit on a board %d wide", cfg.Env.StartLength, cfg.Env.Width)
+	}
+	if err := validateRandomize("randomize", cfg.Env.Randomize, cfg.Env.StartLength); err != nil {
+		return err
+	}
+	if err := validateRandomize("benchmark_randomize", cfg.Eval.BenchmarkRandomize, cfg.Env.StartLength); err != nil {
+		return err
+	}
+	for _, term := range cfg.Fitness.Terms {
+		if !knownFitnessTerm(term.Name) {
+			return fmt.Errorf("config: unknown fitness term %q (want one of %s)",
+				term.Name, strings.Join(FitnessTermNames, ", "))
+		}
+	}
+	return nil
+}
+
+// validateRandomize checks the board size ranges of a randomisation block.
+// The straight fallback start needs a board at least start_length wide.
+func validateRandomize(name string, r RandomizeConfig, startLength int) error {
+	if r.MinWidth < 0 || r.MaxWidth < 0 || r.MinHeight < 0 || r.MaxHeight < 0 {
+		return fmt.Errorf("config: %s board sizes must not be negative", name)
+	}
+	if r.MinWidth > 0 && r.MinWidth > r.MaxWidth {
+		return fmt.Errorf("config: %s min_width %d exceeds max_width %d", name, r.MinWidth, r.MaxWidth)
+	}
+	if r.MinHeight > 0 && r.MinHeight > r.MaxHeight {
+		return fmt.Errorf("config: %s min_height %d exceeds max_height %d", name, r.MinHeight, r.MaxHeight)
+	}
+	minWidth := r.MinWidth
+	if minWidth == 0 {
+		minWidth = r.MaxWidth
+	}
+	if minWidth > 0 && minWidth < startLength {
+		return fmt.Errorf("config: %s width range starts at %d, below start_length %d", name, minWidth, startLength)
+	}
+	return nil
+}
+
+func knownFitnessTerm(name string) bool {
+	for _, n := range FitnessTermNames {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultFitnessTerms expresses the per-mode fitness presets as terms
+func defaultFitnessTerms(f FitnessConfig) []FitnessTerm {
+	switch f.Mode {
+	case "self":
+		return []FitnessTerm{
+			{Name: "ticks", Weight: 1},
+			{Name: "death_self", Weight: -f.SelfPenalty},
+			{Name: "death_wall", Weight: -f.WallPenalty * 0.33}, // lighter wall penalty for self track
+			{Name: "death_stall", Weight: -f.StallPenalty},
+		}
+	case "fruit", "multi":
+		return []FitnessTerm{
+			{Name: "fruits", Weight: f.FruitReward},
+			{Name: "survival", Weight: f.SurvivalW, Cap: float64(f.SurvivalCap)},
+			{Name: "progress", Weight: f.ProgressW},
+			{Name: "death_wall", Weight: -f.WallPenalty},
+			{Name: "death_self", Weight: -f.SelfPenalty},
+			{Name: "death_stall", Weight: -f.StallPenalty},
+			{Name: "death_timeout", Weight: -f.TimeoutPenalty},
+		}
+	default: // wall
+		return []FitnessTerm{
+			{Name: "ticks", Weight: 1},
+			{Name: "death_wall", Weight: -f.WallPenalty},
+		}
+	}
+}
+
 func applyDefaults(cfg *Config) {
 	if cfg.Seed == 0 {
 		cfg.Seed = 1337
@@ -127,6 +397,9 @@ func applyDefaults(cfg *Config) {
 	if cfg.Env.StallWindow == 0 {
 		cfg.Env.StallWindow = 9999
 	}
+	if cfg.Env.Reward == (RewardConfig{}) {
+		cfg.Env.Reward = RewardConfig{Fruit: 1, Closer: 0.01, Farther: -0.01, Wall: -1, Self: -1, Stall: -0.5}
+	}
 	if cfg.NN.Hidden1 == 0 {
 		cfg.NN.Hidden1 = 8
 	}
@@ -148,6 +421,15 @@ func applyDefaults(cfg *Config) {
 	if cfg.GA.CrossoverRate == 0 {
 		cfg.GA.CrossoverRate = 0.7
 	}
+	if cfg.GA.Crossover == "" {
+		cfg.GA.Crossover = "uniform"
+	}
+	if cfg.GA.BLXAlpha == 0 {
+		cfg.GA.BLXAlpha = 0.5
+	}
+	if cfg.GA.SBXEta == 0 {
+		cfg.GA.SBXEta = 10
+	}
 	if cfg.GA.MutationRate == 0 {
 		cfg.GA.MutationRate = 0.10
 	}
@@ -160,6 +442,51 @@ func applyDefaults(cfg *Config) {
 	if cfg.GA.ResetFraction == 0 {
 		cfg.GA.ResetFraction = 0.10
 	}
+	if cfg.GA.MutationControl == "" {
+		cfg.GA.MutationControl = "fixed"
+	}
+	if cfg.GA.MutationSchedule == "" {
+		cfg.GA.MutationSchedule = "constant"
+	}
+	if cfg.GA.MutationRateEnd == 0 {
+		cfg.GA.MutationRateEnd = cfg.GA.MutationRate
+	}
+	if cfg.GA.MutationSigmaEnd == 0 {
+		cfg.GA.MutationSigmaEnd = cfg.GA.MutationSigma
+	}
+	if cfg.GA.MutationSigmaMin == 0 {
+		cfg.GA.MutationSigmaMin = 0.001
+	}
+	if cfg.GA.MutationSigmaMax == 0 {
+		cfg.GA.MutationSigmaMax = 1
+	}
+	if cfg.GA.SuccessTarget == 0 {
+		cfg.GA.SuccessTarget = 0.2
+	}
+	if cfg.GA.SuccessFactor == 0 {
+		cfg.GA.SuccessFactor = 0.85
+	}
+	if cfg.GA.DiversityMutationBoost == 0 {
+		cfg.GA.DiversityMutationBoost = 1
+	}
+	if cfg.GA.Selection == "" {
+		cfg.GA.Selection = "tournament"
+	}
+	if cfg.GA.RankPressure == 0 {
+		cfg.GA.RankPressure = 1.5
+	}
+	if cfg.GA.RankBase == 0 {
+		cfg.GA.RankBase = 0.95
+	}
+	if cfg.GA.BoltzmannTemperature == 0 {
+		cfg.GA.BoltzmannTemperature = 1
+	}
+	if len(cfg.GA.Objectives) == 0 {
+		cfg.GA.Objectives = []string{"fruits", "ticks", "efficiency"}
+	}
+	if len(cfg.GA.HVReference) == 0 {
+		cfg.GA.HVReference = make([]float64, len(cfg.GA.Objectives))
+	}
 	if cfg.Eval.TopKMultiseed == 0 {
 		cfg.Eval.TopKMultiseed = 50
 	}
@@ -175,6 +502,27 @@ func applyDefaults(cfg *Config) {
 	if cfg.Eval.BenchmarkEvery == 0 {
 		cfg.Eval.BenchmarkEvery = 50
 	}
+	if cfg.Eval.BatchSize == 0 {
+		cfg.Eval.BatchSize = 32
+	}
+	if cfg.Eval.ElitePolicy == "" {
+		cfg.Eval.ElitePolicy = "reevaluate"
+	}
+	if cfg.Eval.PopulationSeeds == 0 {
+		cfg.Eval.PopulationSeeds = 1
+	}
+	if cfg.Eval.PopulationScore == "" {
+		cfg.Eval.PopulationScore = "mean"
+	}
+	if cfg.Eval.CVaRAlpha == 0 {
+		cfg.Eval.CVaRAlpha = 0.25
+	}
+	if cfg.Eval.RacingEta == 0 {
+		cfg.Eval.RacingEta = 2
+	}
+	if cfg.Eval.RacingMinSeeds == 0 {
+		cfg.Eval.RacingMinSeeds = 1
+	}
 	if len(cfg.Eval.BenchmarkSeeds) == 0 {
 		cfg.Eval.BenchmarkSeeds = []int{2000, 2001, 2002, 2003, 2004, 2005, 2006, 2007, 2008, 2009}
 	}
@@ -193,6 +541,18 @@ func applyDefaults(cfg *Config) {
 	if cfg.Logging.JSONPath == "" {
 		cfg.Logging.JSONPath = "runs/run.jsonl"
 	}
+	if len(cfg.Logging.Sinks) == 0 {
+		cfg.Logging.Sinks = []string{"console", "csv", "jsonl"}
+	}
+	if cfg.Logging.Verbosity == "" {
+		cfg.Logging.Verbosity = "normal"
+	}
+	if cfg.Logging.ColumnarPath == "" {
+		cfg.Logging.ColumnarPath = "runs/run.col"
+	}
+	if cfg.Logging.TensorBoardDir == "" {
+		cfg.Logging.TensorBoardDir = "runs/tb"
+	}
 	if cfg.Fitness.WallPenalty == 0 {
 		cfg.Fitness.WallPenalty = 500
 	}
@@ -202,6 +562,9 @@ func applyDefaults(cfg *Config) {
 	if cfg.Fitness.StallPenalty == 0 {
 		cfg.Fitness.StallPenalty = 100
 	}
+	if cfg.Fitness.TimeoutPenalty == 0 {
+		cfg.Fitness.TimeoutPenalty = 150
+	}
 	if cfg.Fitness.FruitReward == 0 {
 		cfg.Fitness.FruitReward = 5000
 	}
@@ -214,6 +577,80 @@ func applyDefaults(cfg *Config) {
 	if cfg.Fitness.ProgressW == 0 {
 		cfg.Fitness.ProgressW = 10.0
 	}
+	if cfg.RL.EpisodesPerIter == 0 {
+		cfg.RL.EpisodesPerIter = 16
+	}
+	if cfg.RL.LearningRate == 0 {
+		cfg.RL.LearningRate = 0.003
+	}
+	if cfg.RL.ValueLR == 0 {
+		cfg.RL.ValueLR = 0.005
+	}
+	if cfg.RL.Gamma == 0 {
+		cfg.RL.Gamma = 0.99
+	}
+	if cfg.RL.GAELambda == 0 {
+		cfg.RL.GAELambda = 0.95
+	}
+	if cfg.RL.ClipEps == 0 {
+		cfg.RL.ClipEps = 0.2
+	}
+	if cfg.RL.Epochs == 0 {
+		cfg.RL.Epochs = 4
+	}
+	if cfg.RL.MinibatchSize == 0 {
+		cfg.RL.MinibatchSize = 256
+	}
+	if cfg.RL.EntropyCoef == 0 {
+		cfg.RL.EntropyCoef = 0.01
+	}
+	if cfg.RL.MaxGradNorm == 0 {
+		cfg.RL.MaxGradNorm = 0.5
+	}
+	if cfg.RL.ReplaySize == 0 {
+		cfg.RL.ReplaySize = 50000
+	}
+	if cfg.RL.ReplayBatch == 0 {
+		cfg.RL.ReplayBatch = 64
+	}
+	if cfg.RL.LearningStarts == 0 {
+		cfg.RL.LearningStarts = 1000
+	}
+	if cfg.RL.TrainEvery == 0 {
+		cfg.RL.TrainEvery = 4
+	}
+	if cfg.RL.TargetUpdate == 0 {
+		cfg.RL.TargetUpdate = 1000
+	}
+	if cfg.RL.EpsilonStart == 0 {
+		cfg.RL.EpsilonStart = 1.0
+	}
+	if cfg.RL.EpsilonEnd == 0 {
+		cfg.RL.EpsilonEnd = 0.05
+	}
+	if cfg.RL.EpsilonDecay == 0 {
+		cfg.RL.EpsilonDecay = 20000
+	}
+	if cfg.BC.Epochs == 0 {
+		cfg.BC.Epochs = 30
+	}
+	if cfg.BC.BatchSize == 0 {
+		cfg.BC.BatchSize = 64
+	}
+	if cfg.BC.LearningRate == 0 {
+		cfg.BC.LearningRate = 0.005
+	}
+	if cfg.BC.SeedSigma == 0 {
+		cfg.BC.SeedSigma = 0.1
+	}
+	if len(cfg.Fitness.Terms) == 0 {
+		cfg.Fitness.Terms = defaultFitnessTerms(cfg.Fitness)
+	}
+	for i, term := range cfg.Fitness.Terms {
+		if term.Name == "survival" && term.Cap == 0 {
+			cfg.Fitness.Terms[i].Cap = float64(cfg.Fitness.SurvivalCap)
+		}
+	}
 }
 
 // ObsDim returns the observation dimension for the given obs type
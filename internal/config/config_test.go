@@ -0,0 +1,49 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// loadYAML writes body to a temporary file and loads it
+func loadYAML(t *testing.T, body string) (*Config, error) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return Load(path)
+}
+
+func TestBoardSizeValidation(t *testing.T) {
+	cases := []struct {
+		name string
+		yaml string
+		err  string
+	}{
+		{"fits", "env: {width: 10, start_length: 8}", ""},
+		{"range fits", "env: {start_length: 4, randomize: {min_width: 4, max_width: 8}}", ""},
+		{"max only", "env: {start_length: 4, randomize: {max_width: 6}}", ""},
+		{"too long", "env: {width: 5, start_length: 6}", "start_length 6 does not fit"},
+		{"range too narrow", "env: {start_length: 6, randomize: {min_width: 3, max_width: 4}}", "width range starts at 3"},
+		{"max only too narrow", "env: {start_length: 6, randomize: {max_width: 4}}", "width range starts at 4"},
+		{"width inverted", "env: {randomize: {min_width: 9, max_width: 6}}", "min_width 9 exceeds max_width 6"},
+		{"min without max", "env: {randomize: {min_height: 9}}", "min_height 9 exceeds max_height 0"},
+		{"negative", "env: {randomize: {max_height: -1}}", "must not be negative"},
+		{"benchmark", "env: {start_length: 5}\neval: {benchmark_randomize: {min_width: 4, max_width: 12}}", "benchmark_randomize width range"},
+	}
+	for _, c := range cases {
+		_, err := loadYAML(t, c.yaml)
+		if c.err == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", c.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), c.err) {
+			t.Errorf("%s: got error %v, want one containing %q", c.name, err, c.err)
+		}
+	}
+}
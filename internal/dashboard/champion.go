@@ -0,0 +1,58 @@
+package dashboard
+
+import "snakeai/internal/env"
+
+// Champion is a replay expanded into per-tick board states for the
+// browser, which cannot re-simulate the game's seeded fruit spawns. Only
+// the head, length and fruit are sent per tick: the body is always the
+// last Length cells of the head's trail, starting from Start.
+type Champion struct {
+	Generation int              `json:"generation"`
+	Width      int              `json:"width"`
+	Height     int              `json:"height"`
+	Start      [][2]int         `json:"start"` // initial body, head first
+	Heads      [][2]int         `json:"heads"` // head after each tick
+	Lengths    []int            `json:"lengths"`
+	Fruits     [][2]int         `json:"fruits"` // fruit at each tick, -1,-1 if none
+	Stats      env.EpisodeStats `json:"stats"`
+}
+
+// NewChampion plays a replay back and records every tick the snake
+// survives; the death itself is reported through Stats
+func NewChampion(gen int, replay *env.Replay) *Champion {
+	g := replay.Playback()
+	c := &Champion{
+		Generation: gen,
+		Width:      g.Width,
+		Height:     g.Height,
+		Stats:      replay.FinalStats,
+	}
+	for _, p := range g.Body(nil) {
+		c.Start = append(c.Start, [2]int{p.X, p.Y})
+	}
+	c.record(g)
+
+	for _, a := range replay.Actions {
+		g.Step(a)
+		if !g.Alive {
+			break
+		}
+		c.record(g)
+	}
+	if c.Stats.Ticks == 0 {
+		c.Stats = g.Stats(replay.Seed)
+	}
+	return c
+}
+
+// record appends the current tick
+func (c *Champion) record(g *env.Game) {
+	head := g.Head()
+	fruit := [2]int{-1, -1}
+	if g.FruitEnabled {
+		fruit = [2]int{g.Fruit.X, g.Fruit.Y}
+	}
+	c.Heads = append(c.Heads, [2]int{head.X, head.Y})
+	c.Lengths = append(c.Lengths, g.Length())
+	c.Fruits = append(c.Fruits, fruit)
+}
@@ -0,0 +1,231 @@
+// Package dashboard serves a live training dashboard over HTTP. Generation
+// summaries, diversity measurements and champion replays are pushed to the
+// browser with server-sent events; all assets are embedded so the page
+// works offline.
+package dashboard
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"snakeai/internal/env"
+	"snakeai/internal/ga"
+	"snakeai/internal/logging"
+)
+
+//go:embed static
+var static embed.FS
+
+// clientBuffer is how many events a slow browser may fall behind before its
+// stream is closed; the page then reconnects and reloads /api/state
+const clientBuffer = 64
+
+// keepAlive is the interval of SSE comments that stop idle connections
+// from being closed by proxies
+const keepAlive = 15 * time.Second
+
+// DiversityPoint is one generation's population diversity
+type DiversityPoint struct {
+	Generation int `json:"generation"`
+	ga.Diversity
+}
+
+// event is one server-sent event. seq numbers every published update so a
+// browser can tell whether it missed one.
+type event struct {
+	seq  uint64
+	name string
+	data []byte
+}
+
+// Server holds the run history and streams updates to connected browsers.
+// A nil *Server ignores every Publish call, so callers need not check
+// whether the dashboard is enabled.
+type Server struct {
+	srv *http.Server
+	ln  net.Listener
+
+	mu          sync.Mutex
+	seq         uint64 // sequence number of the latest update
+	generations []logging.GenerationSummary
+	diversity   []DiversityPoint
+	champion    *Champion
+	clients     map[chan event]struct{}
+}
+
+// New creates a dashboard server for addr (host:port, e.g. ":8080")
+func New(addr string) *Server {
+	s := &Server{clients: make(map[chan event]struct{})}
+
+	assets, _ := fs.Sub(static, "static")
+	mux := http.NewServeMux()
+	mux.Handle("/", http.FileServer(http.FS(assets)))
+	mux.HandleFunc("/api/state", s.handleState)
+	mux.HandleFunc("/events", s.handleEvents)
+	s.srv = &http.Server{Addr: addr, Handler: mux}
+	return s
+}
+
+// Start listens and serves in the background
+func (s *Server) Start() error {
+	ln, err := net.Listen("tcp", s.srv.Addr)
+	if err != nil {
+		return err
+	}
+	s.ln = ln
+	go s.srv.Serve(ln)
+	return nil
+}
+
+// URL returns the address browsers should open
+func (s *Server) URL() string {
+	addr := s.ln.Addr().(*net.TCPAddr)
+	host := addr.IP.String()
+	if addr.IP.IsUnspecified() {
+		host = "localhost"
+	}
+	return fmt.Sprintf("http://%s", net.JoinHostPort(host, fmt.Sprint(addr.Port)))
+}
+
+// Close stops the server and disconnects all browsers
+func (s *Server) Close() error {
+	if s == nil {
+		return nil
+	}
+	return s.srv.Close()
+}
+
+// PublishGeneration records a generation summary and streams it. It has the
+// signature of a logging.Logger listener.
+func (s *Server) PublishGeneration(summary logging.GenerationSummary) {
+	if s == nil {
+		return
+	}
+	s.publish("generation", summary, func() { s.generations = append(s.generations, summary) })
+}
+
+// PublishDiversity records a generation's population diversity and streams it
+func (s *Server) PublishDiversity(gen int, d ga.Diversity) {
+	if s == nil {
+		return
+	}
+	point := DiversityPoint{Generation: gen, Diversity: d}
+	s.publish("diversity", point, func() { s.diversity = append(s.diversity, point) })
+}
+
+// PublishChampion replaces the champion shown in the replay canvas
+func (s *Server) PublishChampion(gen int, replay *env.Replay) {
+	if s == nil {
+		return
+	}
+	champion := NewChampion(gen, replay)
+	s.publish("champion", champion, func() { s.champion = champion })
+}
+
+// publish applies an update to the history and sends it to every connected
+// browser without blocking the training loop. Both happen under one lock,
+// so the sequence numbers in /api/state and on the stream agree and events
+// reach each browser in order. A browser that has fallen clientBuffer
+// events behind is disconnected rather than silently skipped.
+func (s *Server) publish(name string, v any, apply func()) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	apply()
+	s.seq++
+	ev := event{seq: s.seq, name: name, data: data}
+	for ch := range s.clients {
+		select {
+		case ch <- ev:
+		default:
+			delete(s.clients, ch)
+			close(ch)
+		}
+	}
+}
+
+// subscribe registers a browser stream
+func (s *Server) subscribe() chan event {
+	ch := make(chan event, clientBuffer)
+	s.mu.Lock()
+	s.clients[ch] = struct{}{}
+	s.mu.Unlock()
+	return ch
+}
+
+// unsubscribe removes a browser stream unless publish already dropped it
+func (s *Server) unsubscribe(ch chan event) {
+	s.mu.Lock()
+	if _, ok := s.clients[ch]; ok {
+		delete(s.clients, ch)
+		close(ch)
+	}
+	s.mu.Unlock()
+}
+
+// handleState returns the whole history so a new page can catch up. seq is
+// the last update it includes; streamed events with a higher id follow it.
+func (s *Server) handleState(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	state := struct {
+		Seq         uint64                      `json:"seq"`
+		Generations []logging.GenerationSummary `json:"generations"`
+		Diversity   []DiversityPoint            `json:"diversity"`
+		Champion    *Champion                   `json:"champion"`
+	}{s.seq, s.generations, s.diversity, s.champion}
+	data, err := json.Marshal(state)
+	s.mu.Unlock()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+// handleEvents streams updates as server-sent events, each with its
+// sequence number as the event id, until the browser disconnects or falls
+// too far behind
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	ch := s.subscribe()
+	defer s.unsubscribe(ch)
+
+	fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+
+	ticker := time.NewTicker(keepAlive)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case ev, ok := <-ch:
+			if !ok {
+				return
+			}
+			fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", ev.seq, ev.name, ev.data)
+		case <-ticker.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		}
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,111 @@
+package dashboard
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"snakeai/internal/logging"
+)
+
+// readEvent reads one server-sent event, skipping comments, and returns its
+// id, name and data lines
+func readEvent(t *testing.T, r *bufio.Reader) (id, name, data string) {
+	t.Helper()
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("reading event: %v", err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "":
+			if name != "" {
+				return id, name, data
+			}
+		case strings.HasPrefix(line, "id: "):
+			id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "event: "):
+			name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+func TestStateAndEventsShareSequence(t *testing.T) {
+	s := New("")
+	ts := httptest.NewServer(s.srv.Handler)
+	defer ts.Close()
+
+	s.PublishGeneration(logging.GenerationSummary{Generation: 1, BestFitness: 10})
+	s.PublishGeneration(logging.GenerationSummary{Generation: 2, BestFitness: 20})
+
+	res, err := http.Get(ts.URL + "/api/state")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var state struct {
+		Seq         uint64                      `json:"seq"`
+		Generations []logging.GenerationSummary `json:"generations"`
+	}
+	err = json.NewDecoder(res.Body).Decode(&state)
+	res.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Seq != 2 || len(state.Generations) != 2 || state.Generations[1].BestFitness != 20 {
+		t.Fatalf("got state seq %d with %d generations, want seq 2 with 2", state.Seq, len(state.Generations))
+	}
+
+	res, err = http.Get(ts.URL + "/events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	if ct := res.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("got content type %q", ct)
+	}
+	r := bufio.NewReader(res.Body)
+	if line, _ := r.ReadString('\n'); line != ": connected\n" {
+		t.Fatalf("got first line %q, want the connected comment", line)
+	}
+
+	s.PublishGeneration(logging.GenerationSummary{Generation: 3, BestFitness: 30})
+	id, name, data := readEvent(t, r)
+	var summary logging.GenerationSummary
+	if err := json.Unmarshal([]byte(data), &summary); err != nil {
+		t.Fatal(err)
+	}
+	if id != "3" || name != "generation" || summary.Generation != 3 {
+		t.Errorf("got event id %s, name %s, generation %d; want id 3, generation, 3", id, name, summary.Generation)
+	}
+}
+
+func TestSlowClientIsDisconnected(t *testing.T) {
+	s := New("")
+	ch := s.subscribe()
+	for gen := 1; gen <= clientBuffer+1; gen++ {
+		s.PublishGeneration(logging.GenerationSummary{Generation: gen})
+	}
+
+	s.mu.Lock()
+	clients := len(s.clients)
+	s.mu.Unlock()
+	if clients != 0 {
+		t.Errorf("got %d clients after an overflow, want 0", clients)
+	}
+	var received []uint64
+	for ev := range ch {
+		received = append(received, ev.seq)
+	}
+	if len(received) != clientBuffer || received[clientBuffer-1] != clientBuffer {
+		t.Errorf("got %d buffered events ending at %v, want %d ending at %d",
+			len(received), received[len(received)-1:], clientBuffer, clientBuffer)
+	}
+	// Unsubscribing after publish already dropped the client must not panic
+	s.unsubscribe(ch)
+}
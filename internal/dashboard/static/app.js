@@ -0,0 +1,304 @@
+// Live training dashboard: follows server-sent events from /events and
+// loads the run history from /api/state to catch up. Charts are drawn on
+// plain canvases so the page has no external dependencies.
+"use strict";
+
+const state = { generations: [], diversity: [], champion: null };
+
+const COLORS = {
+  best: "#3fb950",
+  mean: "#58a6ff",
+  wall: "#f85149",
+  self: "#d2a8ff",
+  stall: "#e3b341",
+  timeout: "#8b949e",
+  distance: "#58a6ff",
+  pairwise: "#d2a8ff",
+  weightStd: "#3fb950",
+  fitnessStd: "#e3b341",
+  unique: "#58a6ff",
+  behaviour: "#f85149",
+};
+
+// ---- charts -------------------------------------------------------------
+
+// lineChart draws series of {name, color, points: [[x, y], ...]} with
+// shared axes and a legend
+function lineChart(canvas, series) {
+  const dpr = window.devicePixelRatio || 1;
+  const w = canvas.clientWidth, h = canvas.clientHeight;
+  canvas.width = w * dpr;
+  canvas.height = h * dpr;
+  const ctx = canvas.getContext("2d");
+  ctx.scale(dpr, dpr);
+  ctx.clearRect(0, 0, w, h);
+  ctx.font = "11px ui-monospace, monospace";
+
+  let xMin = Infinity, xMax = -Infinity, yMin = Infinity, yMax = -Infinity;
+  for (const s of series) {
+    for (const [x, y] of s.points) {
+      if (!isFinite(y)) continue;
+      xMin = Math.min(xMin, x); xMax = Math.max(xMax, x);
+      yMin = Math.min(yMin, y); yMax = Math.max(yMax, y);
+    }
+  }
+  if (!isFinite(xMin)) {
+    ctx.fillStyle = "#8b949e";
+    ctx.fillText("waiting for data…", 10, 20);
+    return;
+  }
+  if (xMax === xMin) xMax = xMin + 1;
+  if (yMax === yMin) { yMax += 1; yMin -= 1; }
+  const pad = (yMax - yMin) * 0.05;
+  yMin -= pad; yMax += pad;
+
+  const left = 56, right = 8, top = 18, bottom = 20;
+  const px = x => left + (x - xMin) / (xMax - xMin) * (w - left - right);
+  const py = y => top + (1 - (y - yMin) / (yMax - yMin)) * (h - top - bottom);
+
+  // Grid and labels
+  ctx.strokeStyle = "#21262d";
+  ctx.fillStyle = "#8b949e";
+  ctx.lineWidth = 1;
+  for (let i = 0; i <= 4; i++) {
+    const y = yMin + (yMax - yMin) * i / 4;
+    ctx.beginPath();
+    ctx.moveTo(left, py(y)); ctx.lineTo(w - right, py(y));
+    ctx.stroke();
+    ctx.textAlign = "right";
+    ctx.fillText(formatNumber(y), left - 4, py(y) + 4);
+  }
+  ctx.textAlign = "center";
+  ctx.fillText(Math.round(xMin), px(xMin) + 8, h - 4);
+  ctx.fillText(Math.round(xMax), px(xMax) - 12, h - 4);
+
+  // Lines
+  for (const s of series) {
+    ctx.strokeStyle = s.color;
+    ctx.lineWidth = 1.5;
+    ctx.beginPath();
+    let started = false;
+    for (const [x, y] of s.points) {
+      if (!isFinite(y)) continue;
+      if (started) ctx.lineTo(px(x), py(y)); else ctx.moveTo(px(x), py(y));
+      started = true;
+    }
+    ctx.stroke();
+  }
+
+  // Legend
+  let lx = left + 4;
+  ctx.textAlign = "left";
+  for (const s of series) {
+    ctx.fillStyle = s.color;
+    ctx.fillRect(lx, 5, 10, 8);
+    ctx.fillStyle = "#c9d1d9";
+    ctx.fillText(s.name, lx + 14, 13);
+    lx += ctx.measureText(s.name).width + 30;
+  }
+}
+
+function formatNumber(v) {
+  const a = Math.abs(v);
+  if (a >= 1e6) return (v / 1e6).toFixed(1) + "M";
+  if (a >= 1e3) return (v / 1e3).toFixed(1) + "k";
+  if (a >= 10) return v.toFixed(0);
+  return v.toFixed(2);
+}
+
+function series(name, rows, get) {
+  return { name, color: COLORS[name], points: rows.map(r => [r.generation, get(r)]) };
+}
+
+let drawPending = false;
+
+function redraw() {
+  if (drawPending) return;
+  drawPending = true;
+  requestAnimationFrame(() => {
+    drawPending = false;
+    const g = state.generations, d = state.diversity;
+    lineChart(document.getElementById("fitness"), [
+      series("best", g, r => r.best_fitness),
+      series("mean", g, r => r.mean_fitness),
+    ]);
+    lineChart(document.getElementById("ticks"), [
+      series("best", g, r => r.best_ticks),
+      series("mean", g, r => r.mean_ticks),
+    ]);
+    lineChart(document.getElementById("fruits"), [
+      series("best", g, r => r.best_fruits),
+      series("mean", g, r => r.mean_fruits),
+    ]);
+    lineChart(document.getElementById("deaths"), ["wall", "self", "stall", "timeout"].map(
+      reason => series(reason, g, r => (r.death_counts || {})[reason] || 0)));
+    lineChart(document.getElementById("genomeDiversity"), [
+      { name: "centroid distance", color: COLORS.distance, points: d.map(r => [r.generation, r.centroid_distance]) },
+      { name: "pairwise distance", color: COLORS.pairwise, points: d.map(r => [r.generation, r.pairwise_distance]) },
+      { name: "weight std ×10", color: COLORS.weightStd, points: d.map(r => [r.generation, 10 * r.weight_std]) },
+    ]);
+    lineChart(document.getElementById("fitnessDiversity"), [
+      { name: "fitness std", color: COLORS.fitnessStd, points: d.map(r => [r.generation, r.fitness_std]) },
+    ]);
+    lineChart(document.getElementById("behaviourDiversity"), [
+      { name: "unique actions %", color: COLORS.unique, points: d.map(r => [r.generation, 100 * r.unique_fraction]) },
+      { name: "behaviour distance ×100", color: COLORS.behaviour, points: d.map(r => [r.generation, 100 * r.behaviour_distance]) },
+    ]);
+
+    const last = g[g.length - 1];
+    if (last) {
+      text("gen", last.generation);
+      text("best", formatNumber(last.best_fitness));
+      text("mean", formatNumber(last.mean_fitness));
+      text("steps", last.env_steps ? formatNumber(last.env_steps) : "–");
+    }
+  });
+}
+
+function text(id, value) {
+  document.getElementById(id).textContent = value;
+}
+
+// ---- champion replay ----------------------------------------------------
+
+const player = { frame: 0, hold: 0 };
+
+// bodyAt rebuilds the snake at a tick: the last length cells of the trail
+// formed by the initial body (tail first) followed by each new head
+function bodyAt(c, k) {
+  const start = c.start.length;
+  const headIdx = start - 1 + k;
+  const cell = i => i < start ? c.start[start - 1 - i] : c.heads[i - start + 1];
+  const body = [];
+  for (let i = headIdx; i > headIdx - c.lengths[k] && i >= 0; i--) body.push(cell(i));
+  return body;
+}
+
+function drawBoard() {
+  const c = state.champion;
+  const canvas = document.getElementById("board");
+  if (!c) return;
+  canvas.style.aspectRatio = c.width + " / " + c.height;
+
+  const dpr = window.devicePixelRatio || 1;
+  const w = canvas.clientWidth, h = canvas.clientHeight;
+  canvas.width = w * dpr;
+  canvas.height = h * dpr;
+  const ctx = canvas.getContext("2d");
+  ctx.scale(dpr, dpr);
+  const cw = w / c.width, ch = h / c.height;
+
+  ctx.fillStyle = "#0d1117";
+  ctx.fillRect(0, 0, w, h);
+  ctx.fillStyle = "#161b22";
+  for (let y = 0; y < c.height; y++) {
+    for (let x = 0; x < c.width; x++) ctx.fillRect(x * cw + 1, y * ch + 1, cw - 2, ch - 2);
+  }
+
+  const k = Math.min(player.frame, c.heads.length - 1);
+  const fruit = c.fruits[k];
+  if (fruit[0] >= 0) {
+    ctx.fillStyle = "#f85149";
+    ctx.beginPath();
+    ctx.arc((fruit[0] + 0.5) * cw, (fruit[1] + 0.5) * ch, Math.min(cw, ch) * 0.35, 0, 2 * Math.PI);
+    ctx.fill();
+  }
+  const body = bodyAt(c, k);
+  body.forEach(([x, y], i) => {
+    ctx.fillStyle = i === 0 ? "#7ee787" : "#2ea043";
+    ctx.fillRect(x * cw + 1, y * ch + 1, cw - 2, ch - 2);
+  });
+
+  const done = k === c.heads.length - 1;
+  text("championTick", "tick " + k + " / " + (c.heads.length - 1) +
+    " · length " + c.lengths[k] + (done ? " · " + deathName(c.stats.Death) : ""));
+}
+
+function deathName(d) {
+  return ["alive", "wall", "self", "stall", "timeout"][d] || "unknown";
+}
+
+function stepChampion() {
+  const c = state.champion;
+  if (c) {
+    if (player.frame >= c.heads.length - 1) {
+      // Pause on the final tick before looping
+      if (++player.hold > 15) { player.frame = 0; player.hold = 0; }
+    } else {
+      player.frame++;
+    }
+    drawBoard();
+  }
+  setTimeout(stepChampion, 80);
+}
+
+function setChampion(c) {
+  state.champion = c;
+  player.frame = 0;
+  player.hold = 0;
+  text("championInfo", "gen " + c.generation + " · " + c.stats.Fruits + " fruits, " +
+    c.stats.Ticks + " ticks, score " + formatNumber(c.stats.Score));
+}
+
+// ---- data feed ----------------------------------------------------------
+
+// Every update carries a sequence number (the SSE event id). The page opens
+// the stream before loading /api/state and buffers events meanwhile, drops
+// events the snapshot already covers, and reloads the snapshot whenever an
+// id is skipped or the stream reconnects.
+let lastSeq = 0;
+let pending = null; // events buffered while /api/state is loading
+
+const handlers = {
+  generation: data => { state.generations.push(data); redraw(); },
+  diversity: data => { state.diversity.push(data); redraw(); },
+  champion: data => setChampion(data),
+};
+
+function receive(name, seq, data) {
+  if (pending) { pending.push([name, seq, data]); return; }
+  if (seq <= lastSeq) return;
+  if (seq !== lastSeq + 1) {
+    resync();
+    pending.push([name, seq, data]);
+    return;
+  }
+  lastSeq = seq;
+  handlers[name](JSON.parse(data));
+}
+
+async function resync() {
+  if (pending) return;
+  pending = [];
+  for (;;) {
+    try {
+      const res = await fetch("api/state");
+      const s = await res.json();
+      state.generations = s.generations || [];
+      state.diversity = s.diversity || [];
+      if (s.champion) setChampion(s.champion);
+      lastSeq = s.seq;
+      break;
+    } catch (err) {
+      await new Promise(resolve => setTimeout(resolve, 1000));
+    }
+  }
+  const buffered = pending;
+  pending = null;
+  redraw();
+  for (const [name, seq, data] of buffered) receive(name, seq, data);
+}
+
+function connect() {
+  const conn = document.getElementById("conn");
+  const es = new EventSource("events");
+  es.onopen = () => { conn.textContent = "live"; conn.className = "on"; resync(); };
+  es.onerror = () => { conn.textContent = "disconnected"; conn.className = "off"; };
+  for (const name of Object.keys(handlers)) {
+    es.addEventListener(name, e => receive(name, Number(e.lastEventId), e.data));
+  }
+}
+
+window.addEventListener("resize", redraw);
+connect();
+stepChampion();
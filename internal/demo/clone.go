@@ -0,0 +1,74 @@
+package demo
+
+import (
+	"math"
+	"math/rand"
+
+	"snakeai/internal/config"
+	"snakeai/internal/nn"
+)
+
+// EpochStats reports training progress after one pass over the dataset
+type EpochStats struct {
+	Epoch    int
+	Loss     float64 // mean cross-entropy
+	Accuracy float64 // fraction of decisions where the argmax matched
+}
+
+// Clone fits a policy network to the demonstrations by minimising the
+// cross-entropy between its softmax outputs and the demonstrated actions,
+// and returns the weights in genome layout. report, if not nil, is called
+// after every epoch.
+func Clone(cfg *config.Config, data *Dataset, rng *rand.Rand, report func(EpochStats)) []float32 {
+	mlp := nn.NewMLP(cfg.ObsDim(), cfg.NN.Hidden1, cfg.NN.Hidden2, 3)
+	mlp.SetWeights(nn.RandomGenome(mlp.GenomeSize(), rng))
+	opt := nn.NewAdam(mlp.GenomeSize(), cfg.BC.LearningRate)
+
+	grad := make([]float32, mlp.GenomeSize())
+	probs := make([]float64, 3)
+	dOut := make([]float32, 3)
+	order := rng.Perm(data.Len())
+
+	for epoch := 1; epoch <= cfg.BC.Epochs; epoch++ {
+		rng.Shuffle(len(order), func(i, j int) { order[i], order[j] = order[j], order[i] })
+		stats := EpochStats{Epoch: epoch}
+
+		for start := 0; start < len(order); start += cfg.BC.BatchSize {
+			end := start + cfg.BC.BatchSize
+			if end > len(order) {
+				end = len(order)
+			}
+			scale := 1 / float32(end-start)
+
+			for _, i := range order[start:end] {
+				obs := data.Observation(i)
+				action := data.Actions[i]
+				if mlp.Forward(obs) == action {
+					stats.Accuracy++
+				}
+				nn.Softmax(mlp.Outputs(), probs)
+				stats.Loss -= logProb(probs[action])
+
+				for k, p := range probs {
+					dOut[k] = float32(p) * scale
+				}
+				dOut[action] -= scale
+				mlp.Backward(obs, dOut, grad)
+			}
+			opt.Step(mlp.Weights, grad)
+			clear(grad)
+		}
+
+		if report != nil && data.Len() > 0 {
+			stats.Loss /= float64(data.Len())
+			stats.Accuracy /= float64(data.Len())
+			report(stats)
+		}
+	}
+	return nn.CloneGenome(mlp.Weights)
+}
+
+// logProb is a log that stays finite for probabilities that underflow to 0
+func logProb(p float64) float64 {
+	return math.Log(math.Max(p, 1e-12))
+}
@@ -0,0 +1,131 @@
+// Package demo records demonstrations from scripted policies or human play
+// and clones them into a policy network by supervised learning.
+package demo
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"snakeai/internal/env"
+)
+
+// Dataset is a set of (observation, action) pairs
+type Dataset struct {
+	ObsDim  int
+	Obs     []float32 // Len() * ObsDim observations
+	Actions []int
+}
+
+// NewDataset creates an empty dataset for observations of size obsDim
+func NewDataset(obsDim int) *Dataset {
+	return &Dataset{ObsDim: obsDim}
+}
+
+// Add appends one demonstrated decision
+func (d *Dataset) Add(obs []float32, action env.Action) {
+	d.Obs = append(d.Obs, obs...)
+	d.Actions = append(d.Actions, int(action))
+}
+
+// Len returns the number of decisions
+func (d *Dataset) Len() int {
+	return len(d.Actions)
+}
+
+// Observation returns the observation of decision i
+func (d *Dataset) Observation(i int) []float32 {
+	return d.Obs[i*d.ObsDim : (i+1)*d.ObsDim]
+}
+
+// AddTrajectory appends every tick of a recorded trajectory
+func (d *Dataset) AddTrajectory(t *env.Trajectory) error {
+	if t.ObsDim != d.ObsDim {
+		return fmt.Errorf("demo: trajectory has obs dim %d, want %d", t.ObsDim, d.ObsDim)
+	}
+	for _, step := range t.Steps {
+		d.Add(step.Obs, step.Action)
+	}
+	return nil
+}
+
+// AddReplay re-simulates a replay, such as a recorded human game, and
+// appends the observation seen before each action
+func (d *Dataset) AddReplay(r *env.Replay, obsType string) error {
+	if env.ObsDim(obsType) != d.ObsDim {
+		return fmt.Errorf("demo: obs type %s has dim %d, want %d", obsType, env.ObsDim(obsType), d.ObsDim)
+	}
+	game := r.Playback()
+	features := env.NewFeatureExtractor(obsType)
+	for _, action := range r.Actions {
+		if !game.Alive {
+			break
+		}
+		d.Add(features.Extract(game), action)
+		game.Step(action)
+	}
+	return nil
+}
+
+// Load builds a dataset from demonstration files: trajectories (.jsonl or
+// .bin) and replays (.json). Directories are searched for such files.
+// Replays are observed with obsType.
+func Load(paths []string, obsType string) (*Dataset, error) {
+	d := NewDataset(env.ObsDim(obsType))
+	for _, path := range paths {
+		files, err := demoFiles(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			if err := d.addFile(file, obsType); err != nil {
+				return nil, fmt.Errorf("%s: %w", file, err)
+			}
+		}
+	}
+	return d, nil
+}
+
+// addFile loads one demonstration file by extension
+func (d *Dataset) addFile(path, obsType string) error {
+	if filepath.Ext(path) == ".json" {
+		r, err := env.LoadReplay(path)
+		if err != nil {
+			return err
+		}
+		return d.AddReplay(r, obsType)
+	}
+	t, err := env.LoadTrajectory(path)
+	if err != nil {
+		return err
+	}
+	return d.AddTrajectory(t)
+}
+
+// demoFiles expands a directory into its demonstration files in name order
+func demoFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, e := range entries {
+		switch filepath.Ext(e.Name()) {
+		case ".json", ".jsonl", ".bin":
+			if !e.IsDir() {
+				files = append(files, filepath.Join(path, e.Name()))
+			}
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
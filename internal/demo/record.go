@@ -0,0 +1,27 @@
+package demo
+
+import (
+	"snakeai/internal/config"
+	"snakeai/internal/env"
+	"snakeai/internal/eval"
+	"snakeai/internal/policy"
+)
+
+// Record plays one episode with a policy and returns its trajectory and
+// final statistics. Trajectories carry no network outputs.
+func Record(cfg *config.Config, p policy.Policy, seed uint32) (*env.Trajectory, env.EpisodeStats) {
+	game := eval.NewGame(cfg.Env, eval.Randomization(cfg.Env.Randomize), seed)
+	traj := env.NewTrajectory(seed, cfg.ObsDim(), 0)
+	features := env.NewFeatureExtractor(cfg.Track.Obs)
+	policy.Reset(p, seed)
+
+	for game.Alive {
+		tick := game.Tick
+		obs := features.Extract(game)
+		action := p.Act(game)
+		res := game.Step(action)
+		traj.Record(tick, obs, action, nil, res)
+	}
+
+	return traj, game.Stats(seed)
+}
@@ -0,0 +1,279 @@
+package dist
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/rpc"
+	"os"
+	"sync"
+	"time"
+
+	"snakeai/internal/config"
+	"snakeai/internal/env"
+	"snakeai/internal/eval"
+)
+
+const (
+	// HeartbeatInterval is how often the coordinator pings each worker
+	HeartbeatInterval = 2 * time.Second
+	// CallTimeout bounds a single evaluation request before it is retried elsewhere
+	CallTimeout = 60 * time.Second
+	// pingTimeout bounds a heartbeat round trip
+	pingTimeout = time.Second
+	// chunkSize is the number of episodes shipped per request
+	chunkSize = 256
+	// inflightPerWorker is the number of concurrent requests per worker
+	inflightPerWorker = 2
+)
+
+// ErrNoWorkers is returned when every worker is unreachable
+var ErrNoWorkers = errors.New("dist: no live workers")
+
+// remote is one worker connection
+type remote struct {
+	addr   string
+	mu     sync.Mutex
+	client *rpc.Client
+}
+
+// Coordinator ships evaluation tasks to remote workers. It implements
+// eval.Backend: tasks lost to a failed or timed-out worker are retried on
+// the remaining ones, and results are returned in task order, so they are
+// identical to local evaluation.
+type Coordinator struct {
+	cfg      config.Config
+	configID string
+	workers  []*remote
+	stop     chan struct{}
+	wg       sync.WaitGroup
+}
+
+// NewCoordinator dials the given worker addresses and starts heartbeats.
+// Unreachable workers are retried by the heartbeat loop.
+func NewCoordinator(cfg *config.Config, addrs []string) (*Coordinator, error) {
+	if len(addrs) == 0 {
+		return nil, ErrNoWorkers
+	}
+	c := &Coordinator{
+		cfg:      *cfg,
+		configID: ConfigID(cfg),
+		stop:     make(chan struct{}),
+	}
+	live := 0
+	for _, addr := range addrs {
+		r := &remote{addr: addr}
+		if err := r.dial(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: worker %s unreachable: %v\n", addr, err)
+		} else {
+			live++
+		}
+		c.workers = append(c.workers, r)
+	}
+	if live == 0 {
+		return nil, ErrNoWorkers
+	}
+
+	c.wg.Add(1)
+	go c.heartbeat()
+	return c, nil
+}
+
+// Close stops heartbeats and closes all connections
+func (c *Coordinator) Close() {
+	close(c.stop)
+	c.wg.Wait()
+	for _, r := range c.workers {
+		r.drop()
+	}
+}
+
+// Live returns the number of workers currently connected
+func (c *Coordinator) Live() int {
+	n := 0
+	for _, r := range c.workers {
+		if r.conn() != nil {
+			n++
+		}
+	}
+	return n
+}
+
+// Evaluate plays the tasks on the remote workers and returns their episode
+// statistics in task order
+func (c *Coordinator) Evaluate(tasks []eval.Task) ([]env.EpisodeStats, error) {
+	results := make([]env.EpisodeStats, len(tasks))
+	numChunks := (len(tasks) + chunkSize - 1) / chunkSize
+	if numChunks == 0 {
+		return results, nil
+	}
+
+	queue := make(chan int, numChunks)
+	for i := 0; i < numChunks; i++ {
+		queue <- i
+	}
+
+	var mu sync.Mutex
+	remaining := numChunks
+	finished := make(chan struct{})
+
+	var senders sync.WaitGroup
+	for _, r := range c.workers {
+		if r.conn() == nil {
+			continue
+		}
+		for k := 0; k < inflightPerWorker; k++ {
+			senders.Add(1)
+			go func(r *remote) {
+				defer senders.Done()
+				for {
+					var idx int
+					select {
+					case idx = <-queue:
+					case <-finished:
+						return
+					}
+
+					start := idx * chunkSize
+					end := start + chunkSize
+					if end > len(tasks) {
+						end = len(tasks)
+					}
+					stats, err := c.call(r, tasks[start:end])
+					if err != nil {
+						// Hand the chunk to another worker and retire this one
+						if err != ErrNoWorkers {
+							fmt.Fprintf(os.Stderr, "Warning: worker %s failed, retrying its tasks: %v\n", r.addr, err)
+						}
+						r.drop()
+						queue <- idx
+						return
+					}
+					copy(results[start:end], stats)
+
+					mu.Lock()
+					remaining--
+					if remaining == 0 {
+						close(finished)
+					}
+					mu.Unlock()
+				}
+			}(r)
+		}
+	}
+
+	exited := make(chan struct{})
+	go func() {
+		senders.Wait()
+		close(exited)
+	}()
+
+	select {
+	case <-finished:
+		return results, nil
+	case <-exited:
+		mu.Lock()
+		defer mu.Unlock()
+		if remaining == 0 {
+			return results, nil
+		}
+		return nil, ErrNoWorkers
+	}
+}
+
+// call sends one chunk of tasks to a worker with a timeout
+func (c *Coordinator) call(r *remote, tasks []eval.Task) ([]env.EpisodeStats, error) {
+	client := r.conn()
+	if client == nil {
+		return nil, ErrNoWorkers
+	}
+	args := &EvalArgs{ConfigID: c.configID, Config: c.cfg, Tasks: tasks}
+	var reply EvalReply
+	call := client.Go(ServiceName+".Evaluate", args, &reply, make(chan *rpc.Call, 1))
+
+	select {
+	case <-call.Done:
+		if call.Error != nil {
+			return nil, call.Error
+		}
+		if len(reply.Stats) != len(tasks) {
+			return nil, fmt.Errorf("dist: worker returned %d results for %d tasks", len(reply.Stats), len(tasks))
+		}
+		return reply.Stats, nil
+	case <-time.After(CallTimeout):
+		return nil, errors.New("dist: evaluation timed out")
+	}
+}
+
+// heartbeat pings live workers and redials dead ones
+func (c *Coordinator) heartbeat() {
+	defer c.wg.Done()
+	ticker := time.NewTicker(HeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+		}
+		for _, r := range c.workers {
+			if r.conn() == nil {
+				if err := r.dial(); err == nil {
+					fmt.Fprintf(os.Stderr, "Worker %s reconnected\n", r.addr)
+				}
+				continue
+			}
+			if err := r.ping(); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: worker %s missed heartbeat: %v\n", r.addr, err)
+				r.drop()
+			}
+		}
+	}
+}
+
+// dial connects to the worker
+func (r *remote) dial() error {
+	network, address := splitAddr(r.addr)
+	conn, err := net.DialTimeout(network, address, pingTimeout)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	r.client = rpc.NewClient(conn)
+	r.mu.Unlock()
+	return nil
+}
+
+// conn returns the current client, or nil if the worker is down
+func (r *remote) conn() *rpc.Client {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.client
+}
+
+// drop closes the connection and marks the worker down
+func (r *remote) drop() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.client != nil {
+		r.client.Close()
+		r.client = nil
+	}
+}
+
+// ping performs one heartbeat round trip
+func (r *remote) ping() error {
+	client := r.conn()
+	if client == nil {
+		return ErrNoWorkers
+	}
+	var reply PingReply
+	call := client.Go(ServiceName+".Ping", &PingArgs{}, &reply, make(chan *rpc.Call, 1))
+	select {
+	case <-call.Done:
+		return call.Error
+	case <-time.After(pingTimeout):
+		return errors.New("dist: heartbeat timed out")
+	}
+}
@@ -0,0 +1,78 @@
+package dist
+
+import (
+	"math/rand"
+	"net"
+	"reflect"
+	"testing"
+
+	"snakeai/internal/config"
+	"snakeai/internal/eval"
+	"snakeai/internal/ga"
+	"snakeai/internal/nn"
+)
+
+// startWorker serves a new worker on a free localhost port
+func startWorker(t *testing.T) (*Worker, string) {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := NewWorker()
+	go w.Serve(l)
+	t.Cleanup(func() {
+		l.Close()
+		w.Close()
+	})
+	return w, l.Addr().String()
+}
+
+func TestRemoteMatchesLocal(t *testing.T) {
+	cfg, err := config.Load("../../configs/fruit.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w1, addr1 := startWorker(t)
+	w2, addr2 := startWorker(t)
+	coordinator, err := NewCoordinator(cfg, []string{addr1, addr2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer coordinator.Close()
+
+	// Enough chunks that both workers get some
+	rng := rand.New(rand.NewSource(1))
+	genomeSize := nn.NewMLP(cfg.ObsDim(), cfg.NN.Hidden1, cfg.NN.Hidden2, 3).GenomeSize()
+	pop := ga.NewPopulation(100, genomeSize, rng)
+	var tasks []eval.Task
+	for _, a := range pop.Agents {
+		for seed := uint32(0); seed < 10; seed++ {
+			tasks = append(tasks, eval.Task{Genome: a.Genome, Seed: seed})
+		}
+	}
+
+	local := eval.NewEvaluator(cfg)
+	defer local.Close()
+	want := local.PlayEpisodes(tasks)
+	got, err := coordinator.Evaluate(tasks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Error("remote episodes differ from local ones")
+	}
+	for i, w := range []*Worker{w1, w2} {
+		if len(w.evaluators) != 1 {
+			t.Errorf("worker %d served %d configs, want 1", i+1, len(w.evaluators))
+		}
+	}
+
+	// Scored through the coordinator as a backend, results match too
+	remote := eval.NewEvaluator(cfg)
+	defer remote.Close()
+	remote.SetBackend(coordinator)
+	if !reflect.DeepEqual(remote.EvaluateEpisodes(tasks), local.EvaluateEpisodes(tasks)) {
+		t.Error("scored remote episodes differ from local ones")
+	}
+}
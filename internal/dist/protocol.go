@@ -0,0 +1,56 @@
+package dist
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+
+	"snakeai/internal/config"
+	"snakeai/internal/env"
+	"snakeai/internal/eval"
+)
+
+// ServiceName is the net/rpc service name registered by workers
+const ServiceName = "Worker"
+
+// EvalArgs asks a worker to play a batch of episodes. The full config travels
+// with every request so a restarted worker can serve it without a handshake;
+// ConfigID lets the worker reuse its evaluator for repeated configs.
+type EvalArgs struct {
+	ConfigID string
+	Config   config.Config
+	Tasks    []eval.Task
+}
+
+// EvalReply carries unscored episode statistics in task order
+type EvalReply struct {
+	Stats []env.EpisodeStats
+}
+
+// PingArgs is the heartbeat request
+type PingArgs struct{}
+
+// PingReply is the heartbeat response
+type PingReply struct {
+	Workers int // local evaluation goroutines on the worker
+}
+
+// ConfigID fingerprints a config so workers can cache evaluators
+func ConfigID(cfg *config.Config) string {
+	data, _ := json.Marshal(cfg)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// splitAddr parses "unix:/path" or "tcp:host:port" (plain "host:port" means
+// tcp) into a network and address for net.Dial/net.Listen
+func splitAddr(addr string) (string, string) {
+	if rest, ok := strings.CutPrefix(addr, "unix:"); ok {
+		return "unix", rest
+	}
+	if rest, ok := strings.CutPrefix(addr, "tcp:"); ok {
+		return "tcp", rest
+	}
+	return "tcp", addr
+}
@@ -0,0 +1,86 @@
+package dist
+
+import (
+	"net"
+	"net/rpc"
+	"os"
+	"runtime"
+	"sync"
+
+	"snakeai/internal/eval"
+)
+
+// Worker evaluates episodes on behalf of a remote coordinator using a local
+// eval.Evaluator per distinct config
+type Worker struct {
+	mu         sync.Mutex
+	evaluators map[string]*eval.Evaluator
+}
+
+// NewWorker creates an idle worker
+func NewWorker() *Worker {
+	return &Worker{evaluators: make(map[string]*eval.Evaluator)}
+}
+
+// Evaluate plays the requested episodes on the local worker pool and
+// returns them unscored; the coordinator's evaluator scores them
+func (w *Worker) Evaluate(args *EvalArgs, reply *EvalReply) error {
+	reply.Stats = w.evaluator(args).PlayEpisodes(args.Tasks)
+	return nil
+}
+
+// Ping answers coordinator heartbeats
+func (w *Worker) Ping(args *PingArgs, reply *PingReply) error {
+	reply.Workers = runtime.NumCPU()
+	return nil
+}
+
+// evaluator returns the cached evaluator for the request's config
+func (w *Worker) evaluator(args *EvalArgs) *eval.Evaluator {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if ev, ok := w.evaluators[args.ConfigID]; ok {
+		return ev
+	}
+	cfg := args.Config
+	cfg.Eval.Workers = 0 // use all local cores regardless of the coordinator's setting
+	ev := eval.NewEvaluator(&cfg)
+	w.evaluators[args.ConfigID] = ev
+	return ev
+}
+
+// Close releases all cached evaluators
+func (w *Worker) Close() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for id, ev := range w.evaluators {
+		ev.Close()
+		delete(w.evaluators, id)
+	}
+}
+
+// Listen opens a listener for a worker address ("host:port", "tcp:host:port"
+// or "unix:/path"). A stale unix socket file is removed first.
+func Listen(addr string) (net.Listener, error) {
+	network, address := splitAddr(addr)
+	if network == "unix" {
+		os.Remove(address)
+	}
+	return net.Listen(network, address)
+}
+
+// Serve registers the worker and serves RPC connections until l is closed
+func (w *Worker) Serve(l net.Listener) error {
+	server := rpc.NewServer()
+	if err := server.RegisterName(ServiceName, w); err != nil {
+		return err
+	}
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+		go server.ServeConn(conn)
+	}
+}
@@ -31,6 +31,21 @@ func ObsDim(obsType string) int {
 	}
 }
 
+// ObsNames returns a short label for each observation feature of the given type
+func ObsNames(obsType string) []string {
+	switch obsType {
+	case "self_min":
+		return []string{"danger_front", "danger_left", "danger_right", "body_front", "body_left", "body_right"}
+	case "fruit_min":
+		return []string{"fruit_dx", "fruit_dy", "danger_front", "danger_left", "danger_right", "length"}
+	case "multi_min":
+		return []string{"danger_front", "danger_left", "danger_right", "body_front", "body_left", "body_right",
+			"fruit_dx", "fruit_dy", "fruit_dist", "length"}
+	default:
+		return []string{"wall_front", "wall_left", "wall_right"}
+	}
+}
+
 // Extract builds the observation vector for the current game state
 // Returns a slice that should not be modified (internal buffer)
 func (f *FeatureExtractor) Extract(g *Game) []float32 {
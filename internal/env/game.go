@@ -1,6 +1,7 @@
 package env
 
 import (
+	"fmt"
 	"math/rand"
 )
 
@@ -28,6 +29,12 @@ type Point struct {
 	X, Y int
 }
 
+// FNV-1a parameters for hashing action sequences
+const (
+	fnvOffset uint64 = 14695981039346656037
+	fnvPrime  uint64 = 1099511628211
+)
+
 // Game represents the snake game environment
 type Game struct {
 	Width       int
@@ -35,9 +42,10 @@ type Game struct {
 	TickCap     int
 	StallWindow int
 	FruitEnabled bool
+	StartLength int
+	Randomize   Randomization
 
 	// State
-	Snake        []Point   // head is at index 0
 	Dir          Direction
 	Fruit        Point
 	Tick         int
@@ -47,24 +55,67 @@ type Game struct {
 	DeathReason  DeathReason
 	ProgressSum  float64
 	LastFruitDist float64
+	TotalReward  float64
+	ActionHash   uint64 // FNV-1a hash of the actions taken this episode
+
+	// Per-tick reward scheme
+	Reward RewardScheme
+
+	// Snake body as a ring buffer: segment i (head = 0) lives at
+	// body[(bodyHead+i)%len(body)]
+	body     []Point
+	bodyHead int
+	length   int
+
+	// Occupancy bitmap over board cells (bit y*Width+x) for O(1) collision checks
+	occupied  []uint64
+	freeCells int
+
+	// Cells the head has visited this episode, for coverage
+	visited      []uint64
+	CellsVisited int
+
+	// Configured board size before randomisation
+	baseWidth  int
+	baseHeight int
 
 	rng *rand.Rand
 }
 
 // NewGame creates a new game instance
 func NewGame(width, height, startLength, tickCap, stallWindow int, fruitEnabled bool, seed uint32) *Game {
+	return NewRandomizedGame(width, height, startLength, tickCap, stallWindow, fruitEnabled, Randomization{}, seed)
+}
+
+// NewRandomizedGame creates a new game instance whose board size and start
+// state are drawn from rnd using the episode seed
+func NewRandomizedGame(width, height, startLength, tickCap, stallWindow int, fruitEnabled bool, rnd Randomization, seed uint32) *Game {
 	g := &Game{
 		Width:        width,
 		Height:       height,
 		TickCap:      tickCap,
 		StallWindow:  stallWindow,
 		FruitEnabled: fruitEnabled,
+		StartLength:  startLength,
+		Randomize:    rnd,
+		baseWidth:    width,
+		baseHeight:   height,
+		Reward:       DefaultRewardScheme(),
 		rng:          rand.New(rand.NewSource(int64(seed))),
 	}
+	g.Width, g.Height = rnd.boardSize(width, height, g.rng)
 	g.Reset(startLength)
 	return g
 }
 
+// Restart begins a new episode on the given seed, reusing the game's buffers.
+// The result is identical to a fresh game created with the same parameters.
+func (g *Game) Restart(seed uint32) {
+	g.rng.Seed(int64(seed))
+	g.Width, g.Height = g.Randomize.boardSize(g.baseWidth, g.baseHeight, g.rng)
+	g.Reset(g.StartLength)
+}
+
 // Reset initializes the game to starting state
 func (g *Game) Reset(startLength int) {
 	g.Tick = 0
@@ -74,16 +125,16 @@ func (g *Game) Reset(startLength int) {
 	g.DeathReason = DeathNone
 	g.ProgressSum = 0
 	g.LastFruitDist = 0
+	g.TotalReward = 0
+	g.ActionHash = fnvOffset
 
-	// Spawn snake in center, facing right
-	centerX := g.Width / 2
-	centerY := g.Height / 2
-	g.Dir = DirRight
-
-	g.Snake = make([]Point, startLength)
-	for i := 0; i < startLength; i++ {
-		g.Snake[i] = Point{X: centerX - i, Y: centerY}
+	g.resetBody(startLength)
+	if g.Randomize.startEnabled() {
+		g.spawnRandom(startLength)
+	} else {
+		g.spawnCentre(startLength)
 	}
+	g.visit(g.Head())
 
 	// Spawn fruit
 	if g.FruitEnabled {
@@ -92,36 +143,52 @@ func (g *Game) Reset(startLength int) {
 	}
 }
 
-// Step advances the game by one tick with the given action
-func (g *Game) Step(action Action) {
+// spawnCentre lays a straight snake facing right with its head in the centre
+// row. The head starts in the centre column unless the body would then run
+// off the left edge, in which case it moves right just far enough to fit.
+// A body longer than the board is wide cannot be placed, which config
+// validation rules out, so it panics rather than laying segments off-board.
+func (g *Game) spawnCentre(startLength int) {
+	if startLength > g.Width {
+		panic(fmt.Sprintf("env: cannot fit a snake of length %d on a %dx%d board", startLength, g.Width, g.Height))
+	}
+	headX := g.Width / 2
+	if headX < startLength-1 {
+		headX = startLength - 1
+	}
+	g.Dir = DirRight
+	for i := 0; i < startLength; i++ {
+		g.pushTail(Point{X: headX - i, Y: g.Height / 2})
+	}
+}
+
+// Step advances the game by one tick with the given action and reports the
+// tick's reward, events and whether the episode ended
+func (g *Game) Step(action Action) StepResult {
 	if !g.Alive {
-		return
+		return StepResult{Done: true, Death: g.DeathReason}
 	}
 
 	g.Tick++
 	g.TicksNoFruit++
+	g.ActionHash = (g.ActionHash ^ uint64(action)) * fnvPrime
+	res := StepResult{Reward: g.Reward.Step}
 
 	// Turn based on relative action
 	g.Dir = g.applyTurn(action)
 
 	// Move head
-	head := g.Snake[0]
+	head := g.Head()
 	newHead := g.moveInDirection(head, g.Dir)
 
 	// Check wall collision
-	if newHead.X < 0 || newHead.X >= g.Width || newHead.Y < 0 || newHead.Y >= g.Height {
-		g.Alive = false
-		g.DeathReason = DeathWall
-		return
+	if !g.inBounds(newHead) {
+		return g.die(DeathWall, res)
 	}
 
 	// Check self collision (excluding tail which will move)
-	for i := 0; i < len(g.Snake)-1; i++ {
-		if g.Snake[i] == newHead {
-			g.Alive = false
-			g.DeathReason = DeathSelf
-			return
-		}
+	if g.hitsBody(newHead) {
+		return g.die(DeathSelf, res)
 	}
 
 	// Check fruit
@@ -130,21 +197,27 @@ func (g *Game) Step(action Action) {
 	// Update snake body
 	if ateFruit {
 		// Grow: don't remove tail
-		g.Snake = append([]Point{newHead}, g.Snake...)
+		g.pushHead(newHead)
 		g.FruitsEaten++
 		g.TicksNoFruit = 0
 		g.spawnFruit()
 		g.LastFruitDist = g.distanceToFruit()
+		res.Event |= EventAteFruit
+		res.Reward += g.Reward.Fruit
 	} else {
-		// Move: shift body
-		g.Snake = append([]Point{newHead}, g.Snake[:len(g.Snake)-1]...)
-		
+		// Move: drop tail, then add head (the head may enter the old tail cell)
+		g.popTail()
+		g.pushHead(newHead)
+
 		// Track progress toward fruit
 		if g.FruitEnabled {
 			newDist := g.distanceToFruit()
 			improvement := g.LastFruitDist - newDist
 			if improvement > 0 {
 				g.ProgressSum += improvement
+				res.Reward += g.Reward.Closer
+			} else if improvement < 0 {
+				res.Reward += g.Reward.Farther
 			}
 			g.LastFruitDist = newDist
 		}
@@ -152,17 +225,28 @@ func (g *Game) Step(action Action) {
 
 	// Check stall
 	if g.TicksNoFruit >= g.StallWindow {
-		g.Alive = false
-		g.DeathReason = DeathStall
-		return
+		return g.die(DeathStall, res)
 	}
 
 	// Check tick cap
 	if g.Tick >= g.TickCap {
-		g.Alive = false
-		g.DeathReason = DeathTimeout
-		return
+		return g.die(DeathTimeout, res)
 	}
+
+	g.TotalReward += res.Reward
+	return res
+}
+
+// die ends the episode and completes the step result
+func (g *Game) die(reason DeathReason, res StepResult) StepResult {
+	g.Alive = false
+	g.DeathReason = reason
+	res.Done = true
+	res.Death = reason
+	res.Event |= EventDied
+	res.Reward += g.Reward.deathReward(reason)
+	g.TotalReward += res.Reward
+	return res
 }
 
 // applyTurn returns new direction after applying relative action
@@ -192,33 +276,30 @@ func (g *Game) moveInDirection(p Point, dir Direction) Point {
 	return p
 }
 
-// spawnFruit places fruit at a random empty cell
+// spawnFruit places fruit at a random empty cell. Empty cells are numbered
+// in row-major order, so the pick matches a scan of the board from the top
+// left without building the list.
 func (g *Game) spawnFruit() {
-	// Build set of occupied cells
-	occupied := make(map[Point]bool)
-	for _, p := range g.Snake {
-		occupied[p] = true
-	}
-
-	// Find all empty cells
-	var empty []Point
-	for y := 0; y < g.Height; y++ {
-		for x := 0; x < g.Width; x++ {
-			p := Point{X: x, Y: y}
-			if !occupied[p] {
-				empty = append(empty, p)
-			}
-		}
+	if g.freeCells <= 0 {
+		return
 	}
 
-	if len(empty) > 0 {
-		g.Fruit = empty[g.rng.Intn(len(empty))]
+	k := g.rng.Intn(g.freeCells)
+	for idx := 0; idx < g.Width*g.Height; idx++ {
+		if g.occupied[idx>>6]&(1<<(idx&63)) != 0 {
+			continue
+		}
+		if k == 0 {
+			g.Fruit = Point{X: idx % g.Width, Y: idx / g.Width}
+			return
+		}
+		k--
 	}
 }
 
 // distanceToFruit returns Manhattan distance from head to fruit
 func (g *Game) distanceToFruit() float64 {
-	head := g.Snake[0]
+	head := g.Head()
 	dx := head.X - g.Fruit.X
 	dy := head.Y - g.Fruit.Y
 	if dx < 0 {
@@ -232,12 +313,140 @@ func (g *Game) distanceToFruit() float64 {
 
 // Head returns the snake's head position
 func (g *Game) Head() Point {
-	return g.Snake[0]
+	return g.body[g.bodyHead]
 }
 
 // Tail returns the snake's tail position
 func (g *Game) Tail() Point {
-	return g.Snake[len(g.Snake)-1]
+	return g.Segment(g.length - 1)
+}
+
+// Length returns the number of body segments including the head
+func (g *Game) Length() int {
+	return g.length
+}
+
+// Segment returns body segment i, where 0 is the head
+func (g *Game) Segment(i int) Point {
+	idx := g.bodyHead + i
+	if idx >= len(g.body) {
+		idx -= len(g.body)
+	}
+	return g.body[idx]
+}
+
+// Body appends the body segments from head to tail to dst and returns it
+func (g *Game) Body(dst []Point) []Point {
+	for i := 0; i < g.length; i++ {
+		dst = append(dst, g.Segment(i))
+	}
+	return dst
+}
+
+// Occupied reports whether a board cell is covered by the snake
+func (g *Game) Occupied(p Point) bool {
+	if !g.inBounds(p) {
+		return false
+	}
+	idx := p.Y*g.Width + p.X
+	return g.occupied[idx>>6]&(1<<(idx&63)) != 0
+}
+
+// hitsBody reports whether moving the head onto p collides with the body.
+// The tail is excluded because it moves away on the same tick.
+func (g *Game) hitsBody(p Point) bool {
+	return g.Occupied(p) && p != g.Tail()
+}
+
+// resetBody clears the snake, reusing buffers when they are large enough
+func (g *Game) resetBody(startLength int) {
+	capacity := g.Width*g.Height + startLength
+	if cap(g.body) < capacity {
+		g.body = make([]Point, capacity)
+	}
+	g.body = g.body[:capacity]
+	g.bodyHead = 0
+	g.length = 0
+
+	words := (g.Width*g.Height + 63) / 64
+	if cap(g.occupied) < words {
+		g.occupied = make([]uint64, words)
+	}
+	g.occupied = g.occupied[:words]
+	for i := range g.occupied {
+		g.occupied[i] = 0
+	}
+	g.freeCells = g.Width * g.Height
+
+	if cap(g.visited) < words {
+		g.visited = make([]uint64, words)
+	}
+	g.visited = g.visited[:words]
+	for i := range g.visited {
+		g.visited[i] = 0
+	}
+	g.CellsVisited = 0
+}
+
+// pushHead adds a new head segment
+func (g *Game) pushHead(p Point) {
+	g.bodyHead--
+	if g.bodyHead < 0 {
+		g.bodyHead += len(g.body)
+	}
+	g.body[g.bodyHead] = p
+	g.length++
+	g.mark(p, true)
+	g.visit(p)
+}
+
+// pushTail appends a segment behind the current tail
+func (g *Game) pushTail(p Point) {
+	idx := g.bodyHead + g.length
+	if idx >= len(g.body) {
+		idx -= len(g.body)
+	}
+	g.body[idx] = p
+	g.length++
+	g.mark(p, true)
+}
+
+// visit records that the head entered p
+func (g *Game) visit(p Point) {
+	if !g.inBounds(p) {
+		return
+	}
+	idx := p.Y*g.Width + p.X
+	bit := uint64(1) << (idx & 63)
+	if g.visited[idx>>6]&bit == 0 {
+		g.visited[idx>>6] |= bit
+		g.CellsVisited++
+	}
+}
+
+// popTail removes the tail segment
+func (g *Game) popTail() {
+	tail := g.Tail()
+	g.length--
+	g.mark(tail, false)
+}
+
+// mark sets or clears a cell in the occupancy bitmap. Off-board segments
+// (possible with very long start lengths) are not tracked.
+func (g *Game) mark(p Point, set bool) {
+	if !g.inBounds(p) {
+		return
+	}
+	idx := p.Y*g.Width + p.X
+	bit := uint64(1) << (idx & 63)
+	was := g.occupied[idx>>6]&bit != 0
+	if set && !was {
+		g.occupied[idx>>6] |= bit
+		g.freeCells--
+	} else if !set && was {
+		g.occupied[idx>>6] &^= bit
+		g.freeCells++
+	}
 }
 
 // Stats returns the episode statistics
@@ -248,27 +457,45 @@ func (g *Game) Stats(seed uint32) EpisodeStats {
 		ProgressSum: g.ProgressSum,
 		Death:       g.DeathReason,
 		Seed:        seed,
+		Return:      g.TotalReward,
+		Length:      g.length,
+		Coverage:    float64(g.CellsVisited) / float64(g.Width*g.Height),
+		ActionHash:  g.ActionHash,
 	}
 }
 
+// NextHead returns the cell the head would move into with the given action
+func (g *Game) NextHead(action Action) Point {
+	return g.moveInDirection(g.Head(), g.applyTurn(action))
+}
+
+// ActionFor returns the relative action that moves the head in the absolute
+// direction dir, or false if dir points straight back into the neck
+func (g *Game) ActionFor(dir Direction) (Action, bool) {
+	switch (dir - g.Dir + 4) % 4 {
+	case 0:
+		return ActionStraight, true
+	case 1:
+		return ActionRight, true
+	case 3:
+		return ActionLeft, true
+	}
+	return ActionStraight, false
+}
+
 // IsDangerWall checks if moving in direction would hit wall
 func (g *Game) IsDangerWall(relDir Action) bool {
 	newDir := g.applyTurn(relDir)
-	newPos := g.moveInDirection(g.Snake[0], newDir)
-	return newPos.X < 0 || newPos.X >= g.Width || newPos.Y < 0 || newPos.Y >= g.Height
+	newPos := g.moveInDirection(g.Head(), newDir)
+	return !g.inBounds(newPos)
 }
 
 // IsDangerBody checks if moving in direction would hit body
 func (g *Game) IsDangerBody(relDir Action) bool {
 	newDir := g.applyTurn(relDir)
-	newPos := g.moveInDirection(g.Snake[0], newDir)
+	newPos := g.moveInDirection(g.Head(), newDir)
 	// Check all but tail (it will move)
-	for i := 0; i < len(g.Snake)-1; i++ {
-		if g.Snake[i] == newPos {
-			return true
-		}
-	}
-	return false
+	return g.hitsBody(newPos)
 }
 
 // IsDanger checks if moving in direction would cause any collision
@@ -279,7 +506,7 @@ func (g *Game) IsDanger(relDir Action) bool {
 // BodyDistanceInDir returns normalized distance to body in relative direction (0..1, 1 if none)
 func (g *Game) BodyDistanceInDir(relDir Action) float32 {
 	newDir := g.applyTurn(relDir)
-	head := g.Snake[0]
+	head := g.Head()
 	maxDist := float32(g.Width + g.Height) // max possible
 
 	// Cast ray in direction
@@ -297,15 +524,13 @@ func (g *Game) BodyDistanceInDir(relDir Action) float32 {
 		}
 
 		// Out of bounds
-		if checkPos.X < 0 || checkPos.X >= g.Width || checkPos.Y < 0 || checkPos.Y >= g.Height {
+		if !g.inBounds(checkPos) {
 			return 1.0
 		}
 
 		// Check body collision
-		for _, p := range g.Snake {
-			if p == checkPos {
-				return float32(dist) / maxDist
-			}
+		if g.Occupied(checkPos) {
+			return float32(dist) / maxDist
 		}
 	}
 	return 1.0
@@ -317,7 +542,7 @@ func (g *Game) FruitDirection() (float32, float32) {
 		return 0, 0
 	}
 
-	head := g.Snake[0]
+	head := g.Head()
 	// World-space delta
 	dx := float32(g.Fruit.X - head.X)
 	dy := float32(g.Fruit.Y - head.Y)
@@ -355,12 +580,12 @@ func (g *Game) FruitDistanceNorm() float32 {
 // LengthNorm returns normalized snake length
 func (g *Game) LengthNorm() float32 {
 	maxLen := float32(g.Width * g.Height)
-	return float32(len(g.Snake)) / maxLen
+	return float32(g.length) / maxLen
 }
 
 // TailDirection returns normalized (dx, dy) to tail
 func (g *Game) TailDirection() (float32, float32) {
-	head := g.Snake[0]
+	head := g.Head()
 	tail := g.Tail()
 	dx := float32(tail.X - head.X)
 	dy := float32(tail.Y - head.Y)
@@ -0,0 +1,182 @@
+package env
+
+import "testing"
+
+// trajectoryCase is a fixed game and action sequence with the outcome
+// recorded from the slice-based body that preceded the ring buffer and
+// occupancy bitmap
+type trajectoryCase struct {
+	name                                             string
+	width, height, startLength, tickCap, stallWindow int
+	fruit                                            bool
+	rnd                                              Randomization
+	seed                                             uint32
+	policySeed                                       uint64
+	explore                                          uint64 // one step in explore is random, 0 for none
+
+	hash   uint64 // playTrajectory hash of every step
+	fruits int
+	ticks  int
+	death  DeathReason
+}
+
+var trajectoryCases = []trajectoryCase{
+	{"default", 10, 10, 3, 200, 60, true, Randomization{}, 1, 1, 0,
+		0x8996f412896fc3dd, 18, 144, DeathSelf},
+	{"wide", 20, 15, 5, 500, 100, true, Randomization{}, 42, 2, 64,
+		0x5b048a41f145d97f, 27, 391, DeathWall},
+	{"random_start", 12, 12, 6, 400, 80, true, Randomization{Position: true, Heading: true, Shape: true}, 7, 3, 0,
+		0xf541a2dad8601254, 8, 65, DeathSelf},
+	{"random_board", 10, 10, 4, 400, 80, true, Randomization{Position: true, MinWidth: 8, MaxWidth: 16, MinHeight: 8, MaxHeight: 16}, 99, 4, 0,
+		0x23719def6ea365c5, 21, 188, DeathSelf},
+	{"stall", 16, 16, 3, 300, 12, true, Randomization{}, 3, 5, 0,
+		0x14be1e89267c982f, 1, 23, DeathStall},
+	{"timeout", 10, 10, 3, 40, 1000, true, Randomization{}, 8, 6, 0,
+		0x580bbb7a81985b7, 6, 40, DeathTimeout},
+	{"long", 12, 12, 10, 600, 120, true, Randomization{Shape: true}, 5, 7, 32,
+		0x880d324cae50a51, 3, 33, DeathSelf},
+	{"crowded", 6, 6, 4, 1000, 200, true, Randomization{}, 11, 8, 0,
+		0xbeaeb785336efbf8, 8, 37, DeathWall},
+	{"wall", 10, 10, 3, 200, 60, true, Randomization{}, 2, 9, 3,
+		0x97ab6e4fce0d85da, 0, 8, DeathWall},
+}
+
+// playTrajectory plays c with a seeded policy that heads for the fruit while
+// avoiding danger, but sometimes moves at random so that every death reason
+// comes up. It returns the game and an FNV-1a hash of the head, fruit and
+// body after every step.
+func playTrajectory(c trajectoryCase) (*Game, uint64) {
+	g := NewRandomizedGame(c.width, c.height, c.startLength, c.tickCap, c.stallWindow, c.fruit, c.rnd, c.seed)
+	h := uint64(14695981039346656037)
+	mix := func(v int) { h = (h ^ uint64(v)) * 1099511628211 }
+	x := c.policySeed
+	for g.Alive {
+		x = x*6364136223846793005 + 1442695040888963407
+		action := Action((x >> 33) % 3)
+		if c.explore == 0 || (x>>40)%c.explore != 0 {
+			best := -1
+			for _, a := range []Action{ActionStraight, ActionLeft, ActionRight} {
+				if g.IsDanger(a) {
+					continue
+				}
+				p := g.moveInDirection(g.Head(), g.applyTurn(a))
+				d := abs(p.X-g.Fruit.X) + abs(p.Y-g.Fruit.Y)
+				if best < 0 || d < best {
+					best, action = d, a
+				}
+			}
+		}
+		g.Step(action)
+		mix(g.Fruit.X)
+		mix(g.Fruit.Y)
+		for _, p := range trajectoryBody(g) {
+			mix(p.X)
+			mix(p.Y)
+		}
+	}
+	return g, h
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// trajectoryBody returns the body from head to tail
+func trajectoryBody(g *Game) []Point {
+	return g.Body(nil)
+}
+
+func TestTrajectoriesMatchSliceBody(t *testing.T) {
+	for _, c := range trajectoryCases {
+		g, hash := playTrajectory(c)
+		if hash != c.hash || g.FruitsEaten != c.fruits || g.Tick != c.ticks || g.DeathReason != c.death {
+			t.Errorf("%s: got hash %#x, %d fruits, %d ticks, %s; want %#x, %d fruits, %d ticks, %s",
+				c.name, hash, g.FruitsEaten, g.Tick, g.DeathReason, c.hash, c.fruits, c.ticks, c.death)
+		}
+	}
+}
+
+func TestRestartMatchesNewGame(t *testing.T) {
+	for _, c := range trajectoryCases {
+		g := NewRandomizedGame(c.width, c.height, c.startLength, c.tickCap, c.stallWindow, c.fruit, c.rnd, c.seed+1)
+		for g.Alive {
+			g.Step(ActionLeft)
+		}
+		g.Restart(c.seed)
+		fresh := NewRandomizedGame(c.width, c.height, c.startLength, c.tickCap, c.stallWindow, c.fruit, c.rnd, c.seed)
+		if g.Width != fresh.Width || g.Height != fresh.Height || g.Fruit != fresh.Fruit || g.Dir != fresh.Dir {
+			t.Errorf("%s: restarted game differs from a new one", c.name)
+			continue
+		}
+		got, want := g.Body(nil), fresh.Body(nil)
+		if len(got) != len(want) {
+			t.Errorf("%s: restarted body has %d segments, want %d", c.name, len(got), len(want))
+			continue
+		}
+		for i := range got {
+			if got[i] != want[i] {
+				t.Errorf("%s: restarted body segment %d is %v, want %v", c.name, i, got[i], want[i])
+			}
+		}
+	}
+}
+
+// benchmarkActions is a fixed action pattern that wanders the board
+var benchmarkActions = []Action{ActionStraight, ActionStraight, ActionLeft, ActionStraight, ActionRight, ActionRight, ActionStraight, ActionLeft}
+
+func BenchmarkStep(b *testing.B) {
+	g := NewGame(20, 20, 3, 1000, 1000, true, 1)
+	seed := uint32(1)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if !g.Alive {
+			seed++
+			g.Restart(seed)
+		}
+		g.Step(benchmarkActions[i%len(benchmarkActions)])
+	}
+}
+
+func BenchmarkEpisode(b *testing.B) {
+	g := NewGame(10, 10, 3, 150, 40, true, 0)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g.Restart(uint32(i))
+		for t := 0; g.Alive; t++ {
+			g.Step(benchmarkActions[t%len(benchmarkActions)])
+		}
+	}
+}
+
+func TestCentreStartStaysOnBoard(t *testing.T) {
+	for _, c := range []struct{ width, length, headX int }{
+		{10, 3, 5}, {10, 6, 5}, {10, 8, 7}, {10, 10, 9}, {3, 3, 2},
+	} {
+		g := NewGame(c.width, 10, c.length, 100, 50, false, 1)
+		if g.Length() != c.length || g.Head().X != c.headX {
+			t.Errorf("width %d, length %d: got head x %d, length %d; want head x %d",
+				c.width, c.length, g.Head().X, g.Length(), c.headX)
+		}
+		for _, p := range g.Body(nil) {
+			if !g.inBounds(p) {
+				t.Errorf("width %d, length %d: segment %v off the board", c.width, c.length, p)
+			}
+		}
+	}
+}
+
+func TestSpawnPanicsWhenSnakeCannotFit(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for a snake longer than the board is wide")
+		}
+	}()
+	// The randomised spawn gives up after maxSpawnAttempts and falls back to
+	// the centre start, which must not lay segments off the board.
+	NewRandomizedGame(4, 4, 6, 100, 50, false, Randomization{Position: true, Heading: true}, 1)
+}
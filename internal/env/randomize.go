@@ -0,0 +1,124 @@
+package env
+
+import (
+	"math/rand"
+)
+
+// Randomization controls per-episode start state and domain randomisation.
+// All draws come from the game's seeded RNG, so a seed always yields the same
+// board and spawn. The zero value keeps the fixed centre start.
+type Randomization struct {
+	Position  bool `json:"position,omitempty"`  // random head position
+	Heading   bool `json:"heading,omitempty"`   // random initial heading
+	Shape     bool `json:"shape,omitempty"`     // random self-avoiding body instead of a straight line
+	MinWidth  int  `json:"min_width,omitempty"` // board width range (0 = use configured width)
+	MaxWidth  int  `json:"max_width,omitempty"`
+	MinHeight int  `json:"min_height,omitempty"` // board height range (0 = use configured height)
+	MaxHeight int  `json:"max_height,omitempty"`
+}
+
+// maxSpawnAttempts bounds the retries when a random body does not fit
+const maxSpawnAttempts = 100
+
+// Enabled reports whether any randomisation is active
+func (r Randomization) Enabled() bool {
+	return r.startEnabled() || r.MaxWidth > 0 || r.MaxHeight > 0
+}
+
+// startEnabled reports whether the spawn state is randomised
+func (r Randomization) startEnabled() bool {
+	return r.Position || r.Heading || r.Shape
+}
+
+// boardSize draws the board dimensions for an episode
+func (r Randomization) boardSize(width, height int, rng *rand.Rand) (int, int) {
+	return drawRange(width, r.MinWidth, r.MaxWidth, rng), drawRange(height, r.MinHeight, r.MaxHeight, rng)
+}
+
+// drawRange returns a uniform value in [lo, hi], or def if the range is unset
+func drawRange(def, lo, hi int, rng *rand.Rand) int {
+	if hi <= 0 {
+		return def
+	}
+	if lo <= 0 || lo > hi {
+		lo = hi
+	}
+	if lo == hi {
+		return lo
+	}
+	return lo + rng.Intn(hi-lo+1)
+}
+
+// spawnRandom places the snake according to the randomisation options,
+// falling back to the fixed centre start if no valid layout is found
+func (g *Game) spawnRandom(startLength int) {
+	for attempt := 0; attempt < maxSpawnAttempts; attempt++ {
+		dir := DirRight
+		if g.Randomize.Heading {
+			dir = Direction(g.rng.Intn(4))
+		}
+		head := Point{X: g.Width / 2, Y: g.Height / 2}
+		if g.Randomize.Position {
+			head = Point{X: g.rng.Intn(g.Width), Y: g.rng.Intn(g.Height)}
+		}
+		if body, ok := g.layBody(head, dir, startLength); ok {
+			for _, p := range body {
+				g.pushTail(p)
+			}
+			g.Dir = dir
+			return
+		}
+	}
+
+	g.spawnCentre(startLength)
+}
+
+// layBody builds a body of the given length behind head. The neck always
+// sits directly behind the heading so the snake never starts facing itself;
+// later segments continue straight or, with Shape, wander randomly.
+func (g *Game) layBody(head Point, dir Direction, length int) ([]Point, bool) {
+	if !g.inBounds(head) {
+		return nil, false
+	}
+	body := make([]Point, 1, length)
+	body[0] = head
+	back := Direction((dir + 2) % 4)
+
+	for len(body) < length {
+		last := body[len(body)-1]
+		next := g.moveInDirection(last, back)
+		if g.Randomize.Shape && len(body) > 1 {
+			found := false
+			for _, i := range g.rng.Perm(4) {
+				d := Direction(i)
+				p := g.moveInDirection(last, d)
+				if g.inBounds(p) && !containsPoint(body, p) {
+					next, found = p, true
+					break
+				}
+			}
+			if !found {
+				return nil, false
+			}
+		}
+		if !g.inBounds(next) || containsPoint(body, next) {
+			return nil, false
+		}
+		body = append(body, next)
+	}
+	return body, true
+}
+
+// inBounds reports whether p lies on the board
+func (g *Game) inBounds(p Point) bool {
+	return p.X >= 0 && p.X < g.Width && p.Y >= 0 && p.Y < g.Height
+}
+
+func containsPoint(points []Point, p Point) bool {
+	for _, q := range points {
+		if q == p {
+			return true
+		}
+	}
+	return false
+}
@@ -21,6 +21,7 @@ type ReplayConfig struct {
 	TickCap      int  `json:"tick_cap"`
 	StallWindow  int  `json:"stall_window"`
 	FruitEnabled bool `json:"fruit_enabled"`
+	Randomize    Randomization `json:"randomize,omitempty"`
 }
 
 // NewReplay creates a new replay recorder
@@ -66,13 +67,14 @@ func LoadReplay(path string) (*Replay, error) {
 
 // Playback recreates the game from the replay
 func (r *Replay) Playback() *Game {
-	g := NewGame(
+	g := NewRandomizedGame(
 		r.Config.Width,
 		r.Config.Height,
 		r.Config.StartLength,
 		r.Config.TickCap,
 		r.Config.StallWindow,
 		r.Config.FruitEnabled,
+		r.Config.Randomize,
 		r.Seed,
 	)
 	return g
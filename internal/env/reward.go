@@ -0,0 +1,79 @@
+package env
+
+import "strings"
+
+// Event flags what happened during a tick
+type Event uint8
+
+const (
+	EventAteFruit Event = 1 << iota // the snake ate a fruit
+	EventDied                       // the episode ended
+)
+
+// Has reports whether all flags in f are set
+func (e Event) Has(f Event) bool {
+	return e&f == f
+}
+
+func (e Event) String() string {
+	var parts []string
+	if e.Has(EventAteFruit) {
+		parts = append(parts, "ate_fruit")
+	}
+	if e.Has(EventDied) {
+		parts = append(parts, "died")
+	}
+	if len(parts) == 0 {
+		return "none"
+	}
+	return strings.Join(parts, "|")
+}
+
+// StepResult is the outcome of a single Step
+type StepResult struct {
+	Reward float64     // reward for this tick under the game's reward scheme
+	Done   bool        // the episode has ended
+	Event  Event       // what happened this tick
+	Death  DeathReason // why the episode ended, if Done
+}
+
+// RewardScheme assigns per-tick rewards for reinforcement learning and
+// trajectory analysis. Fitness for the GA is computed separately.
+type RewardScheme struct {
+	Fruit   float64 `json:"fruit"`   // eating a fruit
+	Step    float64 `json:"step"`    // every tick
+	Closer  float64 `json:"closer"`  // moving closer to the fruit
+	Farther float64 `json:"farther"` // moving away from the fruit
+	Wall    float64 `json:"wall"`    // dying on a wall
+	Self    float64 `json:"self"`    // dying on the body
+	Stall   float64 `json:"stall"`   // running out the stall window
+	Timeout float64 `json:"timeout"` // reaching the tick cap
+}
+
+// DefaultRewardScheme returns the standard shaped reward: +1 per fruit, -1
+// for a collision, and a small shaping signal toward the fruit
+func DefaultRewardScheme() RewardScheme {
+	return RewardScheme{
+		Fruit:   1,
+		Closer:  0.01,
+		Farther: -0.01,
+		Wall:    -1,
+		Self:    -1,
+		Stall:   -0.5,
+	}
+}
+
+// deathReward returns the terminal reward for a death reason
+func (r RewardScheme) deathReward(reason DeathReason) float64 {
+	switch reason {
+	case DeathWall:
+		return r.Wall
+	case DeathSelf:
+		return r.Self
+	case DeathStall:
+		return r.Stall
+	case DeathTimeout:
+		return r.Timeout
+	}
+	return 0
+}
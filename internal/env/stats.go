@@ -1,6 +1,11 @@
 package env
 
-import "math"
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math"
+	"sort"
+)
 
 // DeathReason indicates how the snake died
 type DeathReason int
@@ -38,6 +43,12 @@ type EpisodeStats struct {
 	ProgressSum float64     // cumulative distance improvement
 	Death       DeathReason // how the episode ended
 	Seed        uint32      // seed used for this episode
+	Return      float64     // sum of per-tick rewards
+	Length      int         // final snake length
+	Coverage    float64     // fraction of board cells the head visited
+	ActionHash  uint64      // hash of the action sequence; equal hashes mean the same moves
+
+	Terms map[string]float64 // weighted contribution of each fitness term to Score
 }
 
 // AggregatedStats holds statistics across multiple episodes
@@ -49,6 +60,7 @@ type AggregatedStats struct {
 	ProgressMean float64
 	DeathCounts  map[DeathReason]int
 	NumEpisodes  int
+	TermsMean    map[string]float64 // mean contribution of each fitness term
 }
 
 // Aggregate computes statistics from multiple episode stats
@@ -61,6 +73,7 @@ func Aggregate(episodes []EpisodeStats) AggregatedStats {
 	agg := AggregatedStats{
 		DeathCounts: make(map[DeathReason]int),
 		NumEpisodes: n,
+		TermsMean:   MeanTerms(episodes),
 	}
 
 	var scoreSum, fruitsSum, ticksSum, progressSum float64
@@ -89,8 +102,89 @@ func Aggregate(episodes []EpisodeStats) AggregatedStats {
 	return agg
 }
 
+// MeanTerms averages the per-term fitness breakdown across episodes
+func MeanTerms(episodes []EpisodeStats) map[string]float64 {
+	if len(episodes) == 0 {
+		return nil
+	}
+	means := make(map[string]float64)
+	for _, ep := range episodes {
+		for name, v := range ep.Terms {
+			means[name] += v
+		}
+	}
+	for name := range means {
+		means[name] /= float64(len(episodes))
+	}
+	return means
+}
+
 // RobustnessScore computes the ranking score: mean - lambda * std
 func (a AggregatedStats) RobustnessScore(lambda float64) float64 {
 	return a.ScoreMean - lambda*a.ScoreStd
 }
 
+// CVaR computes the conditional value at risk of the episode scores: the
+// mean score of the worst alpha fraction of episodes, at least one
+func CVaR(episodes []EpisodeStats, alpha float64) float64 {
+	if len(episodes) == 0 {
+		return 0
+	}
+	scores := make([]float64, len(episodes))
+	for i, ep := range episodes {
+		scores[i] = ep.Score
+	}
+	sort.Float64s(scores)
+	n := int(math.Ceil(alpha * float64(len(scores))))
+	n = max(1, min(n, len(scores)))
+	var sum float64
+	for _, s := range scores[:n] {
+		sum += s
+	}
+	return sum / float64(n)
+}
+
+// MeanEpisode summarises episodes as one: the mean of every quantity, with
+// counts rounded, the most common death reason (the earliest on ties), and
+// the first episode's seed. The action hash combines every episode's, so
+// agents share it only if they made the same moves on every seed. A single
+// episode is returned unchanged.
+func MeanEpisode(episodes []EpisodeStats) EpisodeStats {
+	if len(episodes) == 1 {
+		return episodes[0]
+	}
+	if len(episodes) == 0 {
+		return EpisodeStats{}
+	}
+	mean := EpisodeStats{Seed: episodes[0].Seed, Terms: MeanTerms(episodes)}
+	var fruits, ticks, length float64
+	deaths := make(map[DeathReason]int)
+	h := fnv.New64a()
+	for _, ep := range episodes {
+		mean.Score += ep.Score
+		mean.ProgressSum += ep.ProgressSum
+		mean.Return += ep.Return
+		mean.Coverage += ep.Coverage
+		fruits += float64(ep.Fruits)
+		ticks += float64(ep.Ticks)
+		length += float64(ep.Length)
+		deaths[ep.Death]++
+		binary.Write(h, binary.LittleEndian, ep.ActionHash)
+	}
+	n := float64(len(episodes))
+	mean.Score /= n
+	mean.ProgressSum /= n
+	mean.Return /= n
+	mean.Coverage /= n
+	mean.Fruits = int(math.Round(fruits / n))
+	mean.Ticks = int(math.Round(ticks / n))
+	mean.Length = int(math.Round(length / n))
+	mean.ActionHash = h.Sum64()
+	mean.Death = episodes[0].Death
+	for _, ep := range episodes {
+		if deaths[ep.Death] > deaths[mean.Death] {
+			mean.Death = ep.Death
+		}
+	}
+	return mean
+}
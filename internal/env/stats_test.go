@@ -0,0 +1,29 @@
+package env
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCVaR(t *testing.T) {
+	var episodes []EpisodeStats
+	for _, s := range []float64{3, 1, 4, 1, 5} {
+		episodes = append(episodes, EpisodeStats{Score: s})
+	}
+	for _, c := range []struct{ alpha, want float64 }{
+		{1, 2.8},                 // every episode: the mean
+		{0.5, (1 + 1 + 3) / 3.0}, // ceil(2.5) = 3 worst
+		{0.4, 1},                 // the 2 worst
+		{0.01, 1},                // never fewer than one episode
+	} {
+		if got := CVaR(episodes, c.alpha); math.Abs(got-c.want) > 1e-12 {
+			t.Errorf("CVaR at alpha %g = %v, want %v", c.alpha, got, c.want)
+		}
+	}
+	if got, mean := CVaR(episodes, 1), Aggregate(episodes).ScoreMean; math.Abs(got-mean) > 1e-12 {
+		t.Errorf("CVaR at alpha 1 = %v, mean %v", got, mean)
+	}
+	if got := CVaR(nil, 0.5); got != 0 {
+		t.Errorf("CVaR of no episodes = %v, want 0", got)
+	}
+}
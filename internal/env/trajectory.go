@@ -0,0 +1,268 @@
+package env
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+)
+
+// TrajectoryStep is one recorded tick: what the agent saw, what it did and
+// what it got for it
+type TrajectoryStep struct {
+	Tick    int       `json:"tick"`
+	Obs     []float32 `json:"obs"`
+	Action  Action    `json:"action"`
+	Outputs []float32 `json:"outputs,omitempty"` // raw network outputs
+	Reward  float64   `json:"reward"`
+	Event   string    `json:"event,omitempty"`
+	Done    bool      `json:"done,omitempty"`
+}
+
+// Trajectory records a full episode tick by tick for offline analysis
+type Trajectory struct {
+	Seed   uint32           `json:"seed"`
+	ObsDim int              `json:"obs_dim"`
+	OutDim int              `json:"out_dim"`
+	Steps  []TrajectoryStep `json:"-"`
+}
+
+// trajectoryMagic identifies the compact binary format
+var trajectoryMagic = [4]byte{'S', 'N', 'K', 'T'}
+
+const trajectoryVersion = 1
+
+// maxPreallocSteps caps the steps ReadBinary allocates before reading them
+const maxPreallocSteps = 4096
+
+// NewTrajectory creates an empty recorder
+func NewTrajectory(seed uint32, obsDim, outDim int) *Trajectory {
+	return &Trajectory{
+		Seed:   seed,
+		ObsDim: obsDim,
+		OutDim: outDim,
+		Steps:  make([]TrajectoryStep, 0, 256),
+	}
+}
+
+// Record appends a tick. Observation and output slices are copied, so
+// internal buffers may be passed directly.
+func (t *Trajectory) Record(tick int, obs []float32, action Action, outputs []float32, res StepResult) {
+	step := TrajectoryStep{
+		Tick:    tick,
+		Obs:     append([]float32(nil), obs...),
+		Action:  action,
+		Outputs: append([]float32(nil), outputs...),
+		Reward:  res.Reward,
+		Done:    res.Done,
+	}
+	if res.Event != 0 {
+		step.Event = res.Event.String()
+	}
+	t.Steps = append(t.Steps, step)
+}
+
+// Return sums the recorded rewards
+func (t *Trajectory) Return() float64 {
+	total := 0.0
+	for _, s := range t.Steps {
+		total += s.Reward
+	}
+	return total
+}
+
+// Save writes the trajectory as JSONL (.jsonl) or the compact binary format
+// (any other extension, conventionally .bin)
+func (t *Trajectory) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	if filepath.Ext(path) == ".jsonl" {
+		err = t.WriteJSONL(w)
+	} else {
+		err = t.WriteBinary(w)
+	}
+	if err == nil {
+		err = w.Flush()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// WriteJSONL writes a header line followed by one JSON object per tick
+func (t *Trajectory) WriteJSONL(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	if err := enc.Encode(t); err != nil {
+		return err
+	}
+	for i := range t.Steps {
+		if err := enc.Encode(&t.Steps[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteBinary writes the compact little-endian format:
+//
+//	header: magic "SNKT", version u8, seed u32, obs_dim u16, out_dim u16, steps u32
+//	step:   tick u32, action u8, event u8, done u8, reward f32,
+//	        obs [obs_dim]f32, outputs [out_dim]f32
+func (t *Trajectory) WriteBinary(w io.Writer) error {
+	header := make([]byte, 0, 17)
+	header = append(header, trajectoryMagic[:]...)
+	header = append(header, trajectoryVersion)
+	header = binary.LittleEndian.AppendUint32(header, t.Seed)
+	header = binary.LittleEndian.AppendUint16(header, uint16(t.ObsDim))
+	header = binary.LittleEndian.AppendUint16(header, uint16(t.OutDim))
+	header = binary.LittleEndian.AppendUint32(header, uint32(len(t.Steps)))
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+
+	buf := make([]byte, 0, 11+4*(t.ObsDim+t.OutDim))
+	for _, s := range t.Steps {
+		buf = buf[:0]
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(s.Tick))
+		buf = append(buf, byte(s.Action), byte(eventFromString(s.Event)), boolByte(s.Done))
+		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(float32(s.Reward)))
+		buf = appendFloats(buf, s.Obs, t.ObsDim)
+		buf = appendFloats(buf, s.Outputs, t.OutDim)
+		if _, err := w.Write(buf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadTrajectory reads a trajectory saved in either format
+func LoadTrajectory(path string) (*Trajectory, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	r := bufio.NewReader(f)
+	if filepath.Ext(path) == ".jsonl" {
+		return ReadJSONL(r)
+	}
+	return ReadBinary(r)
+}
+
+// ReadJSONL reads a trajectory written by WriteJSONL
+func ReadJSONL(r io.Reader) (*Trajectory, error) {
+	dec := json.NewDecoder(r)
+	var t Trajectory
+	if err := dec.Decode(&t); err != nil {
+		return nil, err
+	}
+	for {
+		var s TrajectoryStep
+		err := dec.Decode(&s)
+		if err == io.EOF {
+			return &t, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		t.Steps = append(t.Steps, s)
+	}
+}
+
+// ReadBinary reads a trajectory written by WriteBinary
+func ReadBinary(r io.Reader) (*Trajectory, error) {
+	header := make([]byte, 17)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+	if [4]byte(header[:4]) != trajectoryMagic {
+		return nil, errors.New("env: not a trajectory file")
+	}
+	if header[4] != trajectoryVersion {
+		return nil, fmt.Errorf("env: unsupported trajectory version %d", header[4])
+	}
+	t := &Trajectory{
+		Seed:   binary.LittleEndian.Uint32(header[5:]),
+		ObsDim: int(binary.LittleEndian.Uint16(header[9:])),
+		OutDim: int(binary.LittleEndian.Uint16(header[11:])),
+	}
+	// The step count comes from the file, so steps are appended as they are
+	// read rather than allocated up front: a corrupt count then fails at the
+	// end of the input instead of allocating gigabytes
+	n := int(binary.LittleEndian.Uint32(header[13:]))
+	t.Steps = make([]TrajectoryStep, 0, min(n, maxPreallocSteps))
+
+	buf := make([]byte, 11+4*(t.ObsDim+t.OutDim))
+	for i := 0; i < n; i++ {
+		if _, err := io.ReadFull(r, buf); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return nil, fmt.Errorf("env: trajectory truncated at step %d of %d", i, n)
+			}
+			return nil, err
+		}
+		t.Steps = append(t.Steps, TrajectoryStep{})
+		s := &t.Steps[i]
+		s.Tick = int(binary.LittleEndian.Uint32(buf))
+		s.Action = Action(buf[4])
+		if ev := Event(buf[5]); ev != 0 {
+			s.Event = ev.String()
+		}
+		s.Done = buf[6] != 0
+		s.Reward = float64(math.Float32frombits(binary.LittleEndian.Uint32(buf[7:])))
+		s.Obs = readFloats(buf[11:], t.ObsDim)
+		s.Outputs = readFloats(buf[11+4*t.ObsDim:], t.OutDim)
+	}
+	return t, nil
+}
+
+// eventFromString parses Event.String output
+func eventFromString(s string) Event {
+	var e Event
+	for _, candidate := range []Event{EventAteFruit, EventDied, EventAteFruit | EventDied} {
+		if candidate.String() == s {
+			e = candidate
+		}
+	}
+	return e
+}
+
+func appendFloats(buf []byte, vals []float32, n int) []byte {
+	for i := 0; i < n; i++ {
+		var v float32
+		if i < len(vals) {
+			v = vals[i]
+		}
+		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(v))
+	}
+	return buf
+}
+
+func readFloats(buf []byte, n int) []float32 {
+	if n == 0 {
+		return nil
+	}
+	vals := make([]float32, n)
+	for i := range vals {
+		vals[i] = math.Float32frombits(binary.LittleEndian.Uint32(buf[4*i:]))
+	}
+	return vals
+}
+
+func boolByte(b bool) byte {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,88 @@
+package env
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// sampleTrajectory records a short episode with every kind of event. Values
+// are exact in float32, which the binary format stores.
+func sampleTrajectory(outDim int) *Trajectory {
+	tr := NewTrajectory(42, 3, outDim)
+	results := []StepResult{
+		{Reward: -0.25},
+		{Reward: 10, Event: EventAteFruit},
+		{Reward: 0.5},
+		{Reward: -10, Event: EventAteFruit | EventDied, Done: true},
+	}
+	for i, res := range results {
+		obs := []float32{float32(i), -1.5, 0.125}
+		outputs := make([]float32, outDim)
+		for k := range outputs {
+			outputs[k] = float32(k) - 0.75
+		}
+		tr.Record(i, obs, Action(i%3), outputs, res)
+	}
+	return tr
+}
+
+func TestTrajectoryRoundTrip(t *testing.T) {
+	for _, outDim := range []int{0, 3} {
+		want := sampleTrajectory(outDim)
+
+		var jsonl bytes.Buffer
+		if err := want.WriteJSONL(&jsonl); err != nil {
+			t.Fatal(err)
+		}
+		got, err := ReadJSONL(&jsonl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("out_dim %d: JSONL round trip got %+v, want %+v", outDim, got, want)
+		}
+
+		var bin bytes.Buffer
+		if err := want.WriteBinary(&bin); err != nil {
+			t.Fatal(err)
+		}
+		got, err = ReadBinary(&bin)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("out_dim %d: binary round trip got %+v, want %+v", outDim, got, want)
+		}
+	}
+}
+
+func TestReadBinaryRejectsBadInput(t *testing.T) {
+	var bin bytes.Buffer
+	if err := sampleTrajectory(3).WriteBinary(&bin); err != nil {
+		t.Fatal(err)
+	}
+	valid := bin.Bytes()
+
+	// A corrupt step count must fail at the end of the input rather than
+	// allocate for four billion steps
+	huge := append([]byte(nil), valid...)
+	binary.LittleEndian.PutUint32(huge[13:], 0xffffffff)
+	if _, err := ReadBinary(bytes.NewReader(huge)); err == nil || !strings.Contains(err.Error(), "truncated at step 4") {
+		t.Errorf("corrupt step count: got error %v, want truncation at step 4", err)
+	}
+
+	if _, err := ReadBinary(bytes.NewReader(valid[:len(valid)-5])); err == nil || !strings.Contains(err.Error(), "truncated at step 3 of 4") {
+		t.Errorf("truncated step: got error %v, want truncation at step 3 of 4", err)
+	}
+	if _, err := ReadBinary(bytes.NewReader([]byte("JUNKJUNKJUNKJUNKJUNK"))); err == nil || !strings.Contains(err.Error(), "not a trajectory") {
+		t.Errorf("bad magic: got error %v", err)
+	}
+	version := append([]byte(nil), valid...)
+	version[4] = 9
+	if _, err := ReadBinary(bytes.NewReader(version)); err == nil || !strings.Contains(err.Error(), "version 9") {
+		t.Errorf("bad version: got error %v", err)
+	}
+}
@@ -0,0 +1,142 @@
+package env
+
+// VecEnv steps many games in lockstep and exposes their observations as a
+// single row-major matrix, one row per game
+type VecEnv struct {
+	games    []*Game
+	seeds    []uint32
+	features *FeatureExtractor
+	obsDim   int
+	obs      []float32
+	active   []bool
+	live     int
+
+	// Game parameters used when the pool grows
+	width, height, startLength int
+	tickCap, stallWindow       int
+	fruitEnabled               bool
+	rnd                        Randomization
+	reward                     RewardScheme
+}
+
+// NewVecEnv creates an empty vectorised environment. Games are allocated on
+// the first Reset and reused afterwards.
+func NewVecEnv(width, height, startLength, tickCap, stallWindow int, fruitEnabled bool, rnd Randomization, obsType string) *VecEnv {
+	return &VecEnv{
+		features:     NewFeatureExtractor(obsType),
+		obsDim:       ObsDim(obsType),
+		width:        width,
+		height:       height,
+		startLength:  startLength,
+		tickCap:      tickCap,
+		stallWindow:  stallWindow,
+		fruitEnabled: fruitEnabled,
+		rnd:          rnd,
+		reward:       DefaultRewardScheme(),
+	}
+}
+
+// Reset starts one episode per seed. Game i plays seeds[i].
+func (v *VecEnv) Reset(seeds []uint32) {
+	n := len(seeds)
+	for len(v.games) < n {
+		g := NewRandomizedGame(v.width, v.height, v.startLength,
+			v.tickCap, v.stallWindow, v.fruitEnabled, v.rnd, 0)
+		g.Reward = v.reward
+		v.games = append(v.games, g)
+	}
+	if cap(v.obs) < n*v.obsDim {
+		v.obs = make([]float32, n*v.obsDim)
+		v.active = make([]bool, n)
+		v.seeds = make([]uint32, n)
+	}
+	v.obs = v.obs[:n*v.obsDim]
+	v.active = v.active[:n]
+	v.seeds = v.seeds[:n]
+
+	for i, seed := range seeds {
+		v.games[i].Restart(seed)
+		v.seeds[i] = seed
+		v.active[i] = v.games[i].Alive
+	}
+	v.live = n
+}
+
+// SetRandomization changes the randomisation used from the next Reset
+func (v *VecEnv) SetRandomization(rnd Randomization) {
+	v.rnd = rnd
+	for _, g := range v.games {
+		g.Randomize = rnd
+	}
+}
+
+// SetRewardScheme changes the per-tick reward used by all games
+func (v *VecEnv) SetRewardScheme(r RewardScheme) {
+	v.reward = r
+	for _, g := range v.games {
+		g.Reward = r
+	}
+}
+
+// Randomization returns the randomisation applied on Reset
+func (v *VecEnv) Randomization() Randomization {
+	return v.rnd
+}
+
+// Size returns the number of games in the current batch
+func (v *VecEnv) Size() int {
+	return len(v.seeds)
+}
+
+// ObsDim returns the width of one observation row
+func (v *VecEnv) ObsDim() int {
+	return v.obsDim
+}
+
+// Game returns game i of the current batch
+func (v *VecEnv) Game(i int) *Game {
+	return v.games[i]
+}
+
+// Active returns the per-game alive mask. Do not modify.
+func (v *VecEnv) Active() []bool {
+	return v.active
+}
+
+// Done reports whether every game in the batch has ended
+func (v *VecEnv) Done() bool {
+	return v.live == 0
+}
+
+// Observe extracts observations for all live games into the shared matrix.
+// Rows of finished games keep their last values.
+func (v *VecEnv) Observe() []float32 {
+	for i, alive := range v.active {
+		if !alive {
+			continue
+		}
+		row := v.obs[i*v.obsDim : (i+1)*v.obsDim]
+		copy(row, v.features.Extract(v.games[i]))
+	}
+	return v.obs
+}
+
+// Step applies actions[i] to every live game
+func (v *VecEnv) Step(actions []int) {
+	for i, alive := range v.active {
+		if !alive {
+			continue
+		}
+		g := v.games[i]
+		g.Step(Action(actions[i]))
+		if !g.Alive {
+			v.active[i] = false
+			v.live--
+		}
+	}
+}
+
+// Stats returns the episode statistics for game i
+func (v *VecEnv) Stats(i int) EpisodeStats {
+	return v.games[i].Stats(v.seeds[i])
+}
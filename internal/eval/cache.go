@@ -0,0 +1,111 @@
+package eval
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sync"
+
+	"snakeai/internal/config"
+	"snakeai/internal/env"
+	"snakeai/internal/nn"
+)
+
+// defaultCacheSize bounds the episode cache when eval.cache_size is unset
+const defaultCacheSize = 100000
+
+// cacheKey identifies an episode. Episodes are deterministic, so the same
+// genome on the same seed, start randomisation and environment always plays
+// out the same way. Genomes are identified by a 128-bit hash rather than
+// compared weight by weight; a lookup could only return another genome's
+// episode if their hashes collided, which at 128 bits is vanishingly
+// unlikely for the genomes of any run.
+type cacheKey struct {
+	genome    [16]byte
+	seed      uint32
+	randomize env.Randomization
+	env       uint64
+}
+
+// episodeCache remembers unscored episode results, evicting the oldest once
+// full. It is safe for concurrent use.
+type episodeCache struct {
+	mu      sync.Mutex
+	entries map[cacheKey]env.EpisodeStats
+	order   []cacheKey // insertion ring for eviction
+	next    int
+	env     uint64
+	hits    int64
+	misses  int64
+}
+
+// newEpisodeCache creates a cache of up to size episodes for the config's
+// environment, or returns nil if size is negative
+func newEpisodeCache(cfg *config.Config, size int) *episodeCache {
+	if size < 0 {
+		return nil
+	}
+	if size == 0 {
+		size = defaultCacheSize
+	}
+	return &episodeCache{
+		entries: make(map[cacheKey]env.EpisodeStats, size),
+		order:   make([]cacheKey, 0, size),
+		env:     envHash(cfg),
+	}
+}
+
+// envHash fingerprints the settings an episode depends on besides the task:
+// the environment, observations, actions and network shape
+func envHash(cfg *config.Config) uint64 {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%#v|%s|%s|%d|%d", cfg.Env, cfg.Track.Obs, cfg.Track.Actions, cfg.NN.Hidden1, cfg.NN.Hidden2)
+	return h.Sum64()
+}
+
+func (c *episodeCache) key(t Task) cacheKey {
+	return cacheKey{genome: nn.HashGenome(t.Genome), seed: t.Seed, randomize: t.Randomize, env: c.env}
+}
+
+// get returns the cached result for key
+func (c *episodeCache) get(key cacheKey) (env.EpisodeStats, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats, ok := c.entries[key]
+	return stats, ok
+}
+
+// record counts lookups answered without and with playing an episode
+func (c *episodeCache) record(hits, misses int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.hits += int64(hits)
+	c.misses += int64(misses)
+}
+
+// put stores an unscored result, evicting the oldest entry when full
+func (c *episodeCache) put(key cacheKey, stats env.EpisodeStats) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[key]; ok {
+		return
+	}
+	stats.Terms = nil
+	if len(c.order) < cap(c.order) {
+		c.order = append(c.order, key)
+	} else {
+		delete(c.entries, c.order[c.next])
+		c.order[c.next] = key
+		c.next = (c.next + 1) % len(c.order)
+	}
+	c.entries[key] = stats
+}
+
+// counts returns the lookups that hit and missed so far
+func (c *episodeCache) counts() (hits, misses int64) {
+	if c == nil {
+		return 0, 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits, c.misses
+}
@@ -0,0 +1,111 @@
+package eval
+
+import (
+	"math"
+	"testing"
+
+	"snakeai/internal/env"
+	"snakeai/internal/ga"
+)
+
+func TestCachedEpisodesAreNotReplayed(t *testing.T) {
+	cfg := fruitConfig(t)
+	cfg.Eval.Workers = 2
+	e := NewEvaluator(cfg)
+	defer e.Close()
+
+	pop := randomPopulation(cfg, 20, 3)
+	// A clone shares its genome's episodes with the original
+	pop.Agents = append(pop.Agents, pop.Agents[0].Clone())
+	seeds := []uint32{5, 6}
+	e.EvaluatePopulation(pop, seeds)
+	u := e.Usage()
+	if u.Episodes != 40 || u.CacheMisses != 40 || u.CacheHits != 2 {
+		t.Fatalf("first generation: played %d, missed %d, hit %d; want 40, 40, 2", u.Episodes, u.CacheMisses, u.CacheHits)
+	}
+	first := make([]float64, len(pop.Agents))
+	for i, a := range pop.Agents {
+		first[i] = a.Fitness
+	}
+
+	e.EvaluatePopulation(pop, seeds)
+	u = e.Usage()
+	if u.Episodes != 40 || u.CacheHits != 44 {
+		t.Errorf("second generation: played %d in total with %d hits; want 40 and 44", u.Episodes, u.CacheHits)
+	}
+	for i, a := range pop.Agents {
+		if a.Fitness != first[i] {
+			t.Errorf("agent %d fitness %v from the cache, %v when played", i, a.Fitness, first[i])
+		}
+	}
+}
+
+func TestCacheEvictsOldestBeyondCapacity(t *testing.T) {
+	c := newEpisodeCache(fruitConfig(t), 3)
+	keys := make([]cacheKey, 5)
+	for i := range keys {
+		keys[i] = cacheKey{seed: uint32(i)}
+		c.put(keys[i], env.EpisodeStats{Ticks: i})
+		if want := min(i+1, 3); len(c.entries) != want {
+			t.Fatalf("after %d puts the cache holds %d entries, want %d", i+1, len(c.entries), want)
+		}
+	}
+	for i, key := range keys {
+		stats, ok := c.get(key)
+		if want := i >= 2; ok != want || (ok && stats.Ticks != i) {
+			t.Errorf("key %d: cached %v with ticks %d, want cached %v", i, ok, stats.Ticks, want)
+		}
+	}
+
+	// Re-putting a cached key neither duplicates nor evicts it
+	c.put(keys[4], env.EpisodeStats{Ticks: 99})
+	if stats, _ := c.get(keys[4]); stats.Ticks != 4 || len(c.entries) != 3 {
+		t.Errorf("re-put changed the cache: ticks %d, %d entries", stats.Ticks, len(c.entries))
+	}
+}
+
+func TestElitePolicies(t *testing.T) {
+	seeds := []uint32{21, 22, 23}
+
+	// The fitness a fresh agent gets on each seed on its own
+	cfg := fruitConfig(t)
+	e := NewEvaluator(cfg)
+	fresh := make([]float64, len(seeds))
+	var agent *ga.Agent
+	for i, seed := range seeds {
+		pop := randomPopulation(cfg, 1, 4)
+		e.EvaluatePopulationSingleSeed(pop, seed)
+		fresh[i] = pop.Agents[0].Fitness
+		agent = pop.Agents[0]
+	}
+	e.Close()
+	if fresh[0] == fresh[1] && fresh[1] == fresh[2] {
+		t.Fatalf("the seeds all give fitness %v; pick seeds that differ", fresh[0])
+	}
+
+	for _, policy := range []string{"reevaluate", "keep", "lifetime"} {
+		cfg := fruitConfig(t)
+		cfg.Eval.ElitePolicy = policy
+		e := NewEvaluator(cfg)
+		pop := &ga.Population{Agents: []*ga.Agent{{Genome: agent.Genome}}}
+		sum := 0.0
+		for i, seed := range seeds {
+			e.EvaluatePopulationSingleSeed(pop, seed)
+			sum += fresh[i]
+			a := pop.Agents[0]
+
+			want, evaluations := fresh[i], 1
+			switch policy {
+			case "keep":
+				want = fresh[0]
+			case "lifetime":
+				want, evaluations = sum/float64(i+1), i+1
+			}
+			if math.Abs(a.Fitness-want) > 1e-9 || a.Evaluations != evaluations {
+				t.Errorf("%s after %d evaluations: fitness %v over %d, want %v over %d",
+					policy, i+1, a.Fitness, a.Evaluations, want, evaluations)
+			}
+		}
+		e.Close()
+	}
+}
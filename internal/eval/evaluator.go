@@ -1,51 +1,94 @@
 package eval
 
 import (
+	"fmt"
 	"math"
+	"os"
 	"runtime"
-	"sync"
+	"sort"
+	"sync/atomic"
+	"time"
 
 	"snakeai/internal/config"
 	"snakeai/internal/env"
 	"snakeai/internal/ga"
 	"snakeai/internal/nn"
+	"snakeai/internal/policy"
 )
 
 // Evaluator handles episode evaluation and fitness computation
 type Evaluator struct {
-	cfg      *config.Config
-	features *env.FeatureExtractor
-	mlp      *nn.MLP
-	workers  int
+	cfg       *config.Config
+	features  *env.FeatureExtractor
+	mlp       *nn.MLP
+	workers   int
+	batchSize int
+	pool      *pool
+	backend   Backend
+	cache     *episodeCache // nil when disabled
+	envSteps  atomic.Int64  // environment steps played by population evaluation
+	episodes  atomic.Int64  // episodes played through EvaluateEpisodes
 }
 
-// NewEvaluator creates a new evaluator
+// Backend evaluates episodes outside the local worker pool, for example on
+// remote machines. Results must be in task order and unscored.
+type Backend interface {
+	Evaluate(tasks []Task) ([]env.EpisodeStats, error)
+}
+
+// NewEvaluator creates a new evaluator and starts its worker pool
 func NewEvaluator(cfg *config.Config) *Evaluator {
 	workers := cfg.Eval.Workers
 	if workers <= 0 {
 		workers = runtime.NumCPU()
 	}
 
-	return &Evaluator{
-		cfg:      cfg,
-		features: env.NewFeatureExtractor(cfg.Track.Obs),
-		mlp:      nn.NewMLP(cfg.ObsDim(), cfg.NN.Hidden1, cfg.NN.Hidden2, 3),
-		workers:  workers,
+	e := &Evaluator{
+		cfg:       cfg,
+		features:  env.NewFeatureExtractor(cfg.Track.Obs),
+		mlp:       nn.NewMLP(cfg.ObsDim(), cfg.NN.Hidden1, cfg.NN.Hidden2, 3),
+		workers:   workers,
+		batchSize: cfg.Eval.BatchSize,
+		cache:     newEpisodeCache(cfg, cfg.Eval.CacheSize),
 	}
+	e.pool = newPool(workers, e.newWorker)
+	return e
 }
 
-// EvaluateAgent runs a single episode with the given agent and seed
-func (e *Evaluator) EvaluateAgent(agent *ga.Agent, seed uint32) env.EpisodeStats {
-	// Create game
-	game := env.NewGame(
+// newWorker allocates the per-worker environment and inference buffers
+func (e *Evaluator) newWorker() *worker {
+	vec := env.NewVecEnv(
 		e.cfg.Env.Width,
 		e.cfg.Env.Height,
 		e.cfg.Env.StartLength,
 		e.cfg.Env.TickCap,
 		e.cfg.Env.StallWindow,
 		e.cfg.Env.FruitEnabled,
-		seed,
+		Randomization(e.cfg.Env.Randomize),
+		e.cfg.Track.Obs,
 	)
+	vec.SetRewardScheme(RewardScheme(e.cfg.Env.Reward))
+	return &worker{
+		vec:   vec,
+		batch: nn.NewBatchMLP(e.cfg.ObsDim(), e.cfg.NN.Hidden1, e.cfg.NN.Hidden2, 3),
+	}
+}
+
+// SetBackend routes episode evaluation through b. If the backend fails,
+// evaluation falls back to the local pool. Pass nil to evaluate locally.
+func (e *Evaluator) SetBackend(b Backend) {
+	e.backend = b
+}
+
+// Close stops the worker pool
+func (e *Evaluator) Close() {
+	e.pool.close()
+}
+
+// EvaluateAgent runs a single episode with the given agent and seed
+func (e *Evaluator) EvaluateAgent(agent *ga.Agent, seed uint32) env.EpisodeStats {
+	// Create game
+	game := NewGame(e.cfg.Env, Randomization(e.cfg.Env.Randomize), seed)
 
 	// Create local MLP and feature extractor (avoid race conditions)
 	mlp := nn.NewMLP(e.cfg.ObsDim(), e.cfg.NN.Hidden1, e.cfg.NN.Hidden2, 3)
@@ -60,161 +103,414 @@ func (e *Evaluator) EvaluateAgent(agent *ga.Agent, seed uint32) env.EpisodeStats
 	}
 
 	stats := game.Stats(seed)
-	stats.Score = e.ComputeFitness(stats)
+	e.Score(&stats)
 	return stats
 }
 
+// EvaluateEpisodes plays every task on the worker pool and returns the
+// scored episode statistics in task order. Tasks are cut into fixed-size
+// batches that workers step in lockstep, so results are identical for any
+// worker count or scheduling.
+func (e *Evaluator) EvaluateEpisodes(tasks []Task) []env.EpisodeStats {
+	results, _ := e.evaluateEpisodes(tasks)
+	return results
+}
+
+// evaluateEpisodes is EvaluateEpisodes, also returning the environment
+// steps actually played
+func (e *Evaluator) evaluateEpisodes(tasks []Task) ([]env.EpisodeStats, int) {
+	results, steps := e.playEpisodes(tasks)
+	for i := range results {
+		e.Score(&results[i])
+	}
+	return results, steps
+}
+
+// PlayEpisodes plays every task like EvaluateEpisodes but returns the
+// episodes unscored, as a Backend must. Remote workers serve it.
+func (e *Evaluator) PlayEpisodes(tasks []Task) []env.EpisodeStats {
+	results, _ := e.playEpisodes(tasks)
+	return results
+}
+
+// playEpisodes plays tasks unscored and returns the environment steps
+// actually played. Episodes in the cache, or repeated within tasks, are
+// played at most once.
+func (e *Evaluator) playEpisodes(tasks []Task) ([]env.EpisodeStats, int) {
+	results := make([]env.EpisodeStats, len(tasks))
+	pending := tasks
+	var keys []cacheKey
+	var slots []int // index into pending of each task still to play, -1 when cached
+	if e.cache != nil {
+		pending = nil
+		slots = make([]int, len(tasks))
+		first := make(map[cacheKey]int)
+		hits := 0
+		for i, t := range tasks {
+			key := e.cache.key(t)
+			if stats, ok := e.cache.get(key); ok {
+				results[i] = stats
+				slots[i] = -1
+				hits++
+			} else if j, ok := first[key]; ok {
+				slots[i] = j
+				hits++
+			} else {
+				first[key] = len(pending)
+				slots[i] = len(pending)
+				pending = append(pending, t)
+				keys = append(keys, key)
+			}
+		}
+		e.cache.record(hits, len(pending))
+	}
+
+	played := e.play(pending)
+	steps := 0
+	for j := range played {
+		steps += played[j].Ticks
+		if e.cache != nil {
+			e.cache.put(keys[j], played[j])
+		}
+	}
+	for i := range results {
+		if slots == nil {
+			results[i] = played[i]
+		} else if slots[i] >= 0 {
+			results[i] = played[slots[i]]
+		}
+	}
+	e.episodes.Add(int64(len(played)))
+	return results, steps
+}
+
+// play evaluates tasks unscored on the backend, falling back to the local
+// worker pool
+func (e *Evaluator) play(tasks []Task) []env.EpisodeStats {
+	if len(tasks) == 0 {
+		return nil
+	}
+	if e.backend != nil {
+		results, err := e.backend.Evaluate(tasks)
+		if err == nil {
+			return results
+		}
+		fmt.Fprintf(os.Stderr, "Warning: remote evaluation failed, evaluating locally: %v\n", err)
+	}
+	return e.evaluateLocal(tasks)
+}
+
+// evaluateLocal plays tasks on the local worker pool
+func (e *Evaluator) evaluateLocal(tasks []Task) []env.EpisodeStats {
+	results := make([]env.EpisodeStats, len(tasks))
+	chunks := splitTasks(tasks, e.batchSize)
+	e.pool.run(len(chunks), func(w *worker, i int) {
+		c := chunks[i]
+		w.play(tasks[c.start:c.end], results[c.start:c.end])
+	})
+	return results
+}
+
 // EvaluatePopulationSingleSeed evaluates all agents with a single seed
 func (e *Evaluator) EvaluatePopulationSingleSeed(pop *ga.Population, seed uint32) {
-	var wg sync.WaitGroup
-	sem := make(chan struct{}, e.workers)
+	e.EvaluatePopulation(pop, []uint32{seed})
+}
 
-	for _, agent := range pop.Agents {
-		wg.Add(1)
-		sem <- struct{}{}
-		go func(a *ga.Agent) {
-			defer wg.Done()
-			defer func() { <-sem }()
-			stats := e.EvaluateAgent(a, seed)
-			a.Stats = stats
-			a.Fitness = stats.Score
-		}(agent)
+// EvaluatePopulation evaluates all agents on the same seeds (common random
+// numbers), setting each agent's fitness to eval.population_score over its
+// episodes and its Stats to the mean of its episodes. With eval.racing the
+// seeds are spent by successive halving: every agent plays the first
+// racing_min_seeds, then only the best 1/racing_eta go on to eta times as
+// many seeds, until the survivors have played them all. Agents eliminated
+// early rank below every agent that went further (see rankByRung).
+//
+// Agents already evaluated in an earlier generation, such as elites, follow
+// eval.elite_policy: reevaluate replaces their fitness with this
+// generation's, keep skips them, and lifetime averages this generation's
+// into their fitness.
+func (e *Evaluator) EvaluatePopulation(pop *ga.Population, seeds []uint32) {
+	var agents []*ga.Agent
+	for _, a := range pop.Agents {
+		if a.Evaluations > 0 && e.cfg.Eval.ElitePolicy == "keep" {
+			continue
+		}
+		agents = append(agents, a)
 	}
-	wg.Wait()
-}
 
-// EvaluateMultiSeed evaluates an agent across multiple seeds
-func (e *Evaluator) EvaluateMultiSeed(agent *ga.Agent, baseSeed int, numSeeds int) env.AggregatedStats {
-	episodes := make([]env.EpisodeStats, numSeeds)
-	for i := 0; i < numSeeds; i++ {
-		seed := uint32(baseSeed + i)
-		episodes[i] = e.EvaluateAgent(agent, seed)
+	episodes, rungs, steps := e.race(agents, seeds)
+	scores := make([]float64, len(agents))
+	for i := range agents {
+		scores[i] = e.populationScore(episodes[i])
 	}
-	return env.Aggregate(episodes)
+	rankByRung(scores, rungs)
+	for i, a := range agents {
+		score := scores[i]
+		a.Stats = env.MeanEpisode(episodes[i])
+		if a.Evaluations > 0 && e.cfg.Eval.ElitePolicy == "lifetime" {
+			a.Fitness += (score - a.Fitness) / float64(a.Evaluations+1)
+			a.Evaluations++
+		} else {
+			a.Fitness = score
+			a.Evaluations = 1
+		}
+	}
+	e.envSteps.Add(int64(steps))
 }
 
-// EvaluateCandidatesMultiSeed evaluates top-K candidates with multiple seeds
-func (e *Evaluator) EvaluateCandidatesMultiSeed(candidates []*ga.Agent) {
-	var wg sync.WaitGroup
-	sem := make(chan struct{}, e.workers)
+// ScoreParents plays the parents of bred agents on the seeds the agents were
+// evaluated on and sets each agent's ParentFitness to its fitter parent's
+// eval.population_score there. The 1/5th success rule then compares child
+// and parent on the same episodes, not across two generations' seeds.
+func (e *Evaluator) ScoreParents(agents []*ga.Agent, seeds []uint32) {
+	rnd := Randomization(e.cfg.Env.Randomize)
+	index := make(map[[16]byte]int)
+	var tasks []Task
+	for _, a := range agents {
+		if !a.Bred {
+			continue
+		}
+		for _, g := range a.ParentGenomes {
+			h := nn.HashGenome(g)
+			if _, ok := index[h]; ok {
+				continue
+			}
+			index[h] = len(tasks) / len(seeds)
+			for _, seed := range seeds {
+				tasks = append(tasks, Task{Genome: g, Seed: seed, Randomize: rnd})
+			}
+		}
+	}
 
-	for _, agent := range candidates {
-		wg.Add(1)
-		sem <- struct{}{}
-		go func(a *ga.Agent) {
-			defer wg.Done()
-			defer func() { <-sem }()
-			agg := e.EvaluateMultiSeed(a, e.cfg.Eval.MultiseedBaseSeed, e.cfg.Eval.MultiseedRuns)
-			a.AggStats = agg
-			a.RobustScore = agg.RobustnessScore(e.cfg.Eval.RobustnessLambda)
-		}(agent)
+	results, steps := e.evaluateEpisodes(tasks)
+	for _, a := range agents {
+		if !a.Bred || len(a.ParentGenomes) == 0 {
+			continue
+		}
+		a.ParentFitness = math.Inf(-1)
+		for _, g := range a.ParentGenomes {
+			j := index[nn.HashGenome(g)]
+			a.ParentFitness = math.Max(a.ParentFitness, e.populationScore(results[j*len(seeds):(j+1)*len(seeds)]))
+		}
+		a.ParentGenomes = nil
 	}
-	wg.Wait()
+	e.envSteps.Add(int64(steps))
 }
 
-// RunBenchmark evaluates agents on the fixed benchmark seed suite
-func (e *Evaluator) RunBenchmark(agents []*ga.Agent) []env.AggregatedStats {
-	results := make([]env.AggregatedStats, len(agents))
-	seeds := e.cfg.Eval.BenchmarkSeeds
+// race plays agents on seeds, by successive halving if eval.racing is set,
+// and returns each agent's episodes in seed order, the last rung each agent
+// played (0 for all without racing) and the steps played
+func (e *Evaluator) race(agents []*ga.Agent, seeds []uint32) ([][]env.EpisodeStats, []int, int) {
+	rnd := Randomization(e.cfg.Env.Randomize)
+	episodes := make([][]env.EpisodeStats, len(agents))
+	rungs := make([]int, len(agents))
+	alive := make([]int, len(agents))
+	for i := range alive {
+		alive[i] = i
+	}
+	played, n := 0, len(seeds)
+	if e.cfg.Eval.Racing {
+		n = min(e.cfg.Eval.RacingMinSeeds, len(seeds))
+	}
+
+	steps := 0
+	for rung := 0; ; rung++ {
+		tasks := make([]Task, 0, len(alive)*(n-played))
+		for _, i := range alive {
+			for _, seed := range seeds[played:n] {
+				tasks = append(tasks, Task{Genome: agents[i].Genome, Seed: seed, Randomize: rnd})
+			}
+		}
+		results, s := e.evaluateEpisodes(tasks)
+		steps += s
+		for j, i := range alive {
+			episodes[i] = append(episodes[i], results[j*(n-played):(j+1)*(n-played)]...)
+			rungs[i] = rung
+		}
+		played = n
+		if n == len(seeds) || len(alive) <= 1 {
+			return episodes, rungs, steps
+		}
 
-	for i, agent := range agents {
-		episodes := make([]env.EpisodeStats, len(seeds))
-		for j, seed := range seeds {
-			episodes[j] = e.EvaluateAgent(agent, uint32(seed))
+		// Promote the best 1/eta to eta times as many seeds
+		eta := e.cfg.Eval.RacingEta
+		scores := make([]float64, len(agents))
+		for _, i := range alive {
+			scores[i] = e.populationScore(episodes[i])
 		}
-		results[i] = env.Aggregate(episodes)
+		sort.SliceStable(alive, func(a, b int) bool { return scores[alive[a]] > scores[alive[b]] })
+		alive = alive[:int(math.Ceil(float64(len(alive))/eta))]
+		n = min(len(seeds), int(math.Ceil(float64(n)*eta)))
+	}
+}
+
+// rankByRung lowers the scores of agents knocked out of a race so that
+// every agent ranks below all agents that reached a later rung, whose
+// scores rest on more seeds. Each rung's scores are shifted down together,
+// keeping their order, until its best is a fitness point below the worst of
+// the rungs above.
+func rankByRung(scores []float64, rungs []int) {
+	top := 0
+	for _, r := range rungs {
+		top = max(top, r)
+	}
+	floor := math.Inf(1)
+	for r := top; r >= 0; r-- {
+		best, worst := math.Inf(-1), math.Inf(1)
+		for i, s := range scores {
+			if rungs[i] == r {
+				best, worst = max(best, s), min(worst, s)
+			}
+		}
+		if math.IsInf(best, -1) {
+			continue
+		}
+		if best >= floor {
+			shift := best - floor + 1
+			for i := range scores {
+				if rungs[i] == r {
+					scores[i] -= shift
+				}
+			}
+			worst -= shift
+		}
+		floor = min(floor, worst)
 	}
-	return results
 }
 
-// ComputeFitness computes the fitness score based on track mode
-func (e *Evaluator) ComputeFitness(stats env.EpisodeStats) float64 {
-	switch e.cfg.Fitness.Mode {
-	case "wall":
-		return e.fitnessWall(stats)
-	case "self":
-		return e.fitnessSelf(stats)
-	case "fruit":
-		return e.fitnessFruit(stats)
-	case "multi":
-		return e.fitnessMulti(stats)
-	default:
-		return e.fitnessWall(stats)
+// populationScore reduces an agent's episodes to its fitness with
+// eval.population_score
+func (e *Evaluator) populationScore(episodes []env.EpisodeStats) float64 {
+	switch e.cfg.Eval.PopulationScore {
+	case "robust":
+		return env.Aggregate(episodes).RobustnessScore(e.cfg.Eval.RobustnessLambda)
+	case "cvar":
+		return env.CVaR(episodes, e.cfg.Eval.CVaRAlpha)
 	}
+	return env.Aggregate(episodes).ScoreMean
 }
 
-func (e *Evaluator) fitnessWall(stats env.EpisodeStats) float64 {
-	score := float64(stats.Ticks)
-	if stats.Death == env.DeathWall {
-		score -= e.cfg.Fitness.WallPenalty
+// EnvSteps returns the environment steps played by population evaluation,
+// the samples that drive selection. Candidate and benchmark evaluation is
+// not counted.
+func (e *Evaluator) EnvSteps() int64 {
+	return e.envSteps.Load()
+}
+
+// Usage is cumulative evaluation work, for monitoring throughput
+type Usage struct {
+	Episodes int64         // episodes played in batches, locally or remotely
+	Workers  int           // local worker pool size
+	Busy     time.Duration // total time local workers spent on jobs
+	Uptime   time.Duration // time since the pool started
+
+	CacheHits   int64 // episodes answered from the cache
+	CacheMisses int64 // episodes that had to be played
+}
+
+// Usage reports the evaluation work done so far
+func (e *Evaluator) Usage() Usage {
+	u := Usage{
+		Episodes: e.episodes.Load(),
+		Workers:  e.pool.size,
+		Busy:     time.Duration(e.pool.busy.Load()),
+		Uptime:   time.Since(e.pool.started),
 	}
-	return score
+	u.CacheHits, u.CacheMisses = e.cache.counts()
+	return u
+}
+
+// EvaluateMultiSeed evaluates an agent across multiple seeds
+func (e *Evaluator) EvaluateMultiSeed(agent *ga.Agent, baseSeed int, numSeeds int) env.AggregatedStats {
+	return e.evaluateSuites([]*ga.Agent{agent}, seedRange(baseSeed, numSeeds), Randomization(e.cfg.Env.Randomize))[0]
 }
 
-func (e *Evaluator) fitnessSelf(stats env.EpisodeStats) float64 {
-	score := float64(stats.Ticks)
-	switch stats.Death {
-	case env.DeathSelf:
-		score -= e.cfg.Fitness.SelfPenalty
-	case env.DeathWall:
-		score -= e.cfg.Fitness.WallPenalty * 0.33 // lighter wall penalty for self track
-	case env.DeathStall:
-		score -= e.cfg.Fitness.StallPenalty
+// EvaluateCandidatesMultiSeed evaluates top-K candidates with multiple seeds
+func (e *Evaluator) EvaluateCandidatesMultiSeed(candidates []*ga.Agent) {
+	seeds := seedRange(e.cfg.Eval.MultiseedBaseSeed, e.cfg.Eval.MultiseedRuns)
+	results := e.evaluateSuites(candidates, seeds, Randomization(e.cfg.Env.Randomize))
+	for i, a := range candidates {
+		a.AggStats = results[i]
+		a.RobustScore = results[i].RobustnessScore(e.cfg.Eval.RobustnessLambda)
 	}
-	return score
 }
 
-func (e *Evaluator) fitnessFruit(stats env.EpisodeStats) float64 {
-	score := e.cfg.Fitness.FruitReward * float64(stats.Fruits)
-	survivalTicks := math.Min(float64(stats.Ticks), float64(e.cfg.Fitness.SurvivalCap))
-	score += e.cfg.Fitness.SurvivalW * survivalTicks
-	score += e.cfg.Fitness.ProgressW * stats.ProgressSum
+// evaluateSuites plays every agent on every seed, parallelised at (agent,
+// seed) granularity, and aggregates each agent's episodes in seed order
+func (e *Evaluator) evaluateSuites(agents []*ga.Agent, seeds []uint32, rnd env.Randomization) []env.AggregatedStats {
+	tasks := make([]Task, 0, len(agents)*len(seeds))
+	for _, a := range agents {
+		for _, seed := range seeds {
+			tasks = append(tasks, Task{Genome: a.Genome, Seed: seed, Randomize: rnd})
+		}
+	}
+
+	episodes := e.EvaluateEpisodes(tasks)
+	results := make([]env.AggregatedStats, len(agents))
+	for i := range agents {
+		results[i] = env.Aggregate(episodes[i*len(seeds) : (i+1)*len(seeds)])
+	}
+	return results
+}
 
-	switch stats.Death {
-	case env.DeathWall, env.DeathSelf:
-		score -= 300
-	case env.DeathStall, env.DeathTimeout:
-		score -= 150
+// seedRange returns n consecutive seeds starting at base
+func seedRange(base, n int) []uint32 {
+	seeds := make([]uint32, n)
+	for i := range seeds {
+		seeds[i] = uint32(base + i)
 	}
-	return score
+	return seeds
 }
 
-func (e *Evaluator) fitnessMulti(stats env.EpisodeStats) float64 {
-	score := 8000 * float64(stats.Fruits)
-	survivalTicks := math.Min(float64(stats.Ticks), 60)
-	score += 2 * survivalTicks
-	score += e.cfg.Fitness.ProgressW * stats.ProgressSum
+// RunBenchmark evaluates agents on the fixed benchmark seed suite
+func (e *Evaluator) RunBenchmark(agents []*ga.Agent) []env.AggregatedStats {
+	return e.runBenchmarkSuite(agents, Randomization(e.cfg.Env.Randomize))
+}
 
-	switch stats.Death {
-	case env.DeathWall, env.DeathSelf:
-		score -= 300
-	case env.DeathStall, env.DeathTimeout:
-		score -= 150
+// RunGeneralisationBenchmark evaluates agents on the benchmark seeds twice:
+// once from the fixed centre start and once under the configured benchmark
+// randomisation, so the two can be compared for a generalisation gap
+func (e *Evaluator) RunGeneralisationBenchmark(agents []*ga.Agent) (fixed, randomized []env.AggregatedStats) {
+	fixed = e.runBenchmarkSuite(agents, env.Randomization{})
+	randomized = e.runBenchmarkSuite(agents, Randomization(e.cfg.Eval.BenchmarkRandomize))
+	return fixed, randomized
+}
+
+// runBenchmarkSuite plays every benchmark seed for each agent
+func (e *Evaluator) runBenchmarkSuite(agents []*ga.Agent, rnd env.Randomization) []env.AggregatedStats {
+	return e.evaluateSuites(agents, e.BenchmarkSeeds(), rnd)
+}
+
+// BenchmarkSeeds returns the configured benchmark seed suite
+func (e *Evaluator) BenchmarkSeeds() []uint32 {
+	seeds := make([]uint32, len(e.cfg.Eval.BenchmarkSeeds))
+	for i, seed := range e.cfg.Eval.BenchmarkSeeds {
+		seeds[i] = uint32(seed)
+	}
+	return seeds
+}
+
+// EvaluatePolicy plays a policy once on each seed and returns the scored
+// episodes in seed order. Policies may keep state, so episodes run one after
+// another rather than on the worker pool.
+func (e *Evaluator) EvaluatePolicy(p policy.Policy, seeds []uint32, rnd env.Randomization) []env.EpisodeStats {
+	results := make([]env.EpisodeStats, len(seeds))
+	for i, seed := range seeds {
+		game := NewGame(e.cfg.Env, rnd, seed)
+		policy.Reset(p, seed)
+		for game.Alive {
+			game.Step(p.Act(game))
+		}
+		results[i] = game.Stats(seed)
+		e.Score(&results[i])
 	}
-	return score
+	return results
 }
 
 // EvaluateWithReplay runs an episode and records actions for replay
 func (e *Evaluator) EvaluateWithReplay(agent *ga.Agent, seed uint32) (*env.Replay, env.EpisodeStats) {
-	game := env.NewGame(
-		e.cfg.Env.Width,
-		e.cfg.Env.Height,
-		e.cfg.Env.StartLength,
-		e.cfg.Env.TickCap,
-		e.cfg.Env.StallWindow,
-		e.cfg.Env.FruitEnabled,
-		seed,
-	)
-
-	replayCfg := env.ReplayConfig{
-		Width:        e.cfg.Env.Width,
-		Height:       e.cfg.Env.Height,
-		StartLength:  e.cfg.Env.StartLength,
-		TickCap:      e.cfg.Env.TickCap,
-		StallWindow:  e.cfg.Env.StallWindow,
-		FruitEnabled: e.cfg.Env.FruitEnabled,
-	}
-	replay := env.NewReplay(seed, replayCfg)
+	rnd := Randomization(e.cfg.Env.Randomize)
+	game := NewGame(e.cfg.Env, rnd, seed)
+	replay := NewReplay(e.cfg.Env, rnd, seed)
 
 	mlp := nn.NewMLP(e.cfg.ObsDim(), e.cfg.NN.Hidden1, e.cfg.NN.Hidden2, 3)
 	mlp.SetWeights(agent.Genome)
@@ -228,9 +524,88 @@ func (e *Evaluator) EvaluateWithReplay(agent *ga.Agent, seed uint32) (*env.Repla
 	}
 
 	stats := game.Stats(seed)
-	stats.Score = e.ComputeFitness(stats)
+	e.Score(&stats)
 	replay.SetFinalStats(stats)
 
 	return replay, stats
 }
 
+// EvaluateWithTrajectory runs an episode and records observations, actions,
+// raw network outputs and rewards for every tick
+func (e *Evaluator) EvaluateWithTrajectory(agent *ga.Agent, seed uint32) (*env.Trajectory, env.EpisodeStats) {
+	game := NewGame(e.cfg.Env, Randomization(e.cfg.Env.Randomize), seed)
+	traj := env.NewTrajectory(seed, e.cfg.ObsDim(), 3)
+
+	mlp := nn.NewMLP(e.cfg.ObsDim(), e.cfg.NN.Hidden1, e.cfg.NN.Hidden2, 3)
+	mlp.SetWeights(agent.Genome)
+	features := env.NewFeatureExtractor(e.cfg.Track.Obs)
+
+	for game.Alive {
+		tick := game.Tick
+		obs := features.Extract(game)
+		action := env.Action(mlp.Forward(obs))
+		res := game.Step(action)
+		traj.Record(tick, obs, action, mlp.Outputs(), res)
+	}
+
+	stats := game.Stats(seed)
+	e.Score(&stats)
+	return traj, stats
+}
+
+// NewGame creates a game for the given environment config and randomisation
+func NewGame(cfg config.EnvConfig, rnd env.Randomization, seed uint32) *env.Game {
+	g := env.NewRandomizedGame(
+		cfg.Width,
+		cfg.Height,
+		cfg.StartLength,
+		cfg.TickCap,
+		cfg.StallWindow,
+		cfg.FruitEnabled,
+		rnd,
+		seed,
+	)
+	g.Reward = RewardScheme(cfg.Reward)
+	return g
+}
+
+// NewReplay creates an empty replay for a game built by NewGame with the
+// same arguments
+func NewReplay(cfg config.EnvConfig, rnd env.Randomization, seed uint32) *env.Replay {
+	return env.NewReplay(seed, env.ReplayConfig{
+		Width:        cfg.Width,
+		Height:       cfg.Height,
+		StartLength:  cfg.StartLength,
+		TickCap:      cfg.TickCap,
+		StallWindow:  cfg.StallWindow,
+		FruitEnabled: cfg.FruitEnabled,
+		Randomize:    rnd,
+	})
+}
+
+// RewardScheme converts a reward config into its env form
+func RewardScheme(rc config.RewardConfig) env.RewardScheme {
+	return env.RewardScheme{
+		Fruit:   rc.Fruit,
+		Step:    rc.Step,
+		Closer:  rc.Closer,
+		Farther: rc.Farther,
+		Wall:    rc.Wall,
+		Self:    rc.Self,
+		Stall:   rc.Stall,
+		Timeout: rc.Timeout,
+	}
+}
+
+// Randomization converts a randomisation config into its env form
+func Randomization(rc config.RandomizeConfig) env.Randomization {
+	return env.Randomization{
+		Position:  rc.Position,
+		Heading:   rc.Heading,
+		Shape:     rc.Shape,
+		MinWidth:  rc.MinWidth,
+		MaxWidth:  rc.MaxWidth,
+		MinHeight: rc.MinHeight,
+		MaxHeight: rc.MaxHeight,
+	}
+}
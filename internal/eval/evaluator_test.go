@@ -0,0 +1,131 @@
+package eval
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"snakeai/internal/config"
+	"snakeai/internal/ga"
+	"snakeai/internal/nn"
+)
+
+// fruitConfig loads the fruit track with randomised starts, so that every
+// episode depends on its seed
+func fruitConfig(t *testing.T) *config.Config {
+	t.Helper()
+	cfg, err := config.Load("../../configs/fruit.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Env.Randomize = config.RandomizeConfig{Position: true, Heading: true, Shape: true}
+	return cfg
+}
+
+// randomPopulation returns n random agents for cfg, the same for a given seed
+func randomPopulation(cfg *config.Config, n int, seed int64) *ga.Population {
+	size := nn.NewMLP(cfg.ObsDim(), cfg.NN.Hidden1, cfg.NN.Hidden2, 3).GenomeSize()
+	return ga.NewPopulation(n, size, rand.New(rand.NewSource(seed)))
+}
+
+func TestPopulationEvaluationIsDeterministic(t *testing.T) {
+	seeds := []uint32{11, 12, 13}
+	var want *ga.Population
+	for _, c := range []struct{ workers, batch int }{{1, 1}, {1, 32}, {4, 1}, {4, 32}, {3, 7}} {
+		cfg := fruitConfig(t)
+		cfg.Eval.Workers = c.workers
+		cfg.Eval.BatchSize = c.batch
+		cfg.Eval.CacheSize = -1
+		cfg.Eval.PopulationSeeds = len(seeds)
+		e := NewEvaluator(cfg)
+		pop := randomPopulation(cfg, 40, 1)
+		e.EvaluatePopulation(pop, seeds)
+		e.Close()
+
+		if want == nil {
+			want = pop
+			continue
+		}
+		for i, a := range pop.Agents {
+			w := want.Agents[i]
+			if a.Fitness != w.Fitness || !reflect.DeepEqual(a.Stats, w.Stats) {
+				t.Fatalf("workers %d, batch %d: agent %d got fitness %v, stats %+v; want %v, %+v",
+					c.workers, c.batch, i, a.Fitness, a.Stats, w.Fitness, w.Stats)
+			}
+		}
+	}
+}
+
+func TestRankByRung(t *testing.T) {
+	scores := []float64{10, 50, 30, 100, 5}
+	rungs := []int{2, 1, 2, 0, 0}
+	rankByRung(scores, rungs)
+	want := []float64{10, 9, 30, 8, -87}
+	for i := range scores {
+		if scores[i] != want[i] {
+			t.Errorf("agent %d: score %v, want %v", i, scores[i], want[i])
+		}
+	}
+}
+
+func TestRacedOutAgentsRankBelowSurvivors(t *testing.T) {
+	seeds := []uint32{31, 32, 33, 34, 35, 36, 37, 38}
+	cfg := fruitConfig(t)
+	cfg.Eval.PopulationSeeds = len(seeds)
+	cfg.Eval.Racing = true
+	cfg.Eval.RacingEta = 2
+	cfg.Eval.RacingMinSeeds = 1
+	cfg.Eval.CacheSize = -1
+	e := NewEvaluator(cfg)
+	defer e.Close()
+
+	pop := randomPopulation(cfg, 30, 5)
+	// Racing is deterministic, so a dry run gives the rungs the agents reach
+	episodes, rungs, _ := e.race(pop.Agents, seeds)
+	reached := make([]int, 4)
+	for i, r := range rungs {
+		for k := 0; k <= r; k++ {
+			reached[k]++
+		}
+		if want := []int{1, 2, 4, 8}[r]; len(episodes[i]) != want {
+			t.Errorf("agent %d out at rung %d after %d seeds, want %d", i, r, len(episodes[i]), want)
+		}
+	}
+	if want := []int{30, 15, 8, 4}; !reflect.DeepEqual(reached, want) {
+		t.Fatalf("agents reaching each rung %v, want %v", reached, want)
+	}
+
+	e.EvaluatePopulation(pop, seeds)
+	for i, a := range pop.Agents {
+		for j, b := range pop.Agents {
+			if rungs[i] < rungs[j] && a.Fitness >= b.Fitness {
+				t.Errorf("agent %d out at rung %d has fitness %v, not below agent %d at rung %d with %v",
+					i, rungs[i], a.Fitness, j, rungs[j], b.Fitness)
+			}
+		}
+	}
+}
+
+func TestCVaRAtAlphaOneIsTheMean(t *testing.T) {
+	seeds := []uint32{41, 42, 43, 44}
+	var fitness [2][]float64
+	for k, score := range []string{"mean", "cvar"} {
+		cfg := fruitConfig(t)
+		cfg.Eval.PopulationSeeds = len(seeds)
+		cfg.Eval.PopulationScore = score
+		cfg.Eval.CVaRAlpha = 1
+		e := NewEvaluator(cfg)
+		pop := randomPopulation(cfg, 20, 6)
+		e.EvaluatePopulation(pop, seeds)
+		e.Close()
+		for _, a := range pop.Agents {
+			fitness[k] = append(fitness[k], a.Fitness)
+		}
+	}
+	for i := range fitness[0] {
+		if math.Abs(fitness[0][i]-fitness[1][i]) > 1e-9 {
+			t.Errorf("agent %d: mean %v, cvar at alpha 1 %v", i, fitness[0][i], fitness[1][i])
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package eval
+
+import (
+	"math"
+
+	"snakeai/internal/config"
+	"snakeai/internal/env"
+)
+
+// termValues maps each fitness term name to the raw episode measure it weighs
+var termValues = map[string]func(env.EpisodeStats) float64{
+	"fruits":        func(s env.EpisodeStats) float64 { return float64(s.Fruits) },
+	"ticks":         func(s env.EpisodeStats) float64 { return float64(s.Ticks) },
+	"survival":      func(s env.EpisodeStats) float64 { return float64(s.Ticks) },
+	"progress":      func(s env.EpisodeStats) float64 { return s.ProgressSum },
+	"death_wall":    deathIs(env.DeathWall),
+	"death_self":    deathIs(env.DeathSelf),
+	"death_stall":   deathIs(env.DeathStall),
+	"death_timeout": deathIs(env.DeathTimeout),
+	"efficiency":    ticksPerFruit,
+	"length":        func(s env.EpisodeStats) float64 { return float64(s.Length) },
+	"coverage":      func(s env.EpisodeStats) float64 { return s.Coverage },
+}
+
+func deathIs(reason env.DeathReason) func(env.EpisodeStats) float64 {
+	return func(s env.EpisodeStats) float64 {
+		if s.Death == reason {
+			return 1
+		}
+		return 0
+	}
+}
+
+func ticksPerFruit(s env.EpisodeStats) float64 {
+	if s.Fruits == 0 {
+		return float64(s.Ticks)
+	}
+	return float64(s.Ticks) / float64(s.Fruits)
+}
+
+// TermValue returns the raw (unweighted, capped) value of a fitness term
+func TermValue(term config.FitnessTerm, stats env.EpisodeStats) float64 {
+	value, ok := termValues[term.Name]
+	if !ok {
+		return 0
+	}
+	v := value(stats)
+	if term.Cap > 0 {
+		v = math.Min(v, term.Cap)
+	}
+	return v
+}
+
+// ComputeFitness returns the weighted sum of the configured fitness terms
+func (e *Evaluator) ComputeFitness(stats env.EpisodeStats) float64 {
+	score := 0.0
+	for _, term := range e.cfg.Fitness.Terms {
+		score += term.Weight * TermValue(term, stats)
+	}
+	return score
+}
+
+// Score sets the episode's fitness and its per-term breakdown
+func (e *Evaluator) Score(stats *env.EpisodeStats) {
+	stats.Score = 0
+	stats.Terms = make(map[string]float64, len(e.cfg.Fitness.Terms))
+	for _, term := range e.cfg.Fitness.Terms {
+		contribution := term.Weight * TermValue(term, *stats)
+		stats.Score += contribution
+		stats.Terms[term.Name] += contribution
+	}
+}
@@ -0,0 +1,123 @@
+package eval
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"snakeai/internal/env"
+	"snakeai/internal/nn"
+)
+
+// Task is one episode to evaluate: a genome played on a seed under a
+// start-state randomisation
+type Task struct {
+	Genome    []float32
+	Seed      uint32
+	Randomize env.Randomization
+}
+
+// worker owns the environment and inference buffers reused across jobs
+type worker struct {
+	vec     *env.VecEnv
+	batch   *nn.BatchMLP
+	genomes [][]float32
+	seeds   []uint32
+	actions []int
+}
+
+// pool is a fixed set of long-lived workers. Jobs carry their output index,
+// so results never depend on how many workers there are or which one ran a
+// job.
+type pool struct {
+	jobs    chan func(*worker)
+	done    sync.WaitGroup
+	size    int
+	started time.Time
+	busy    atomic.Int64 // nanoseconds workers spent running jobs
+}
+
+// newPool starts n workers, each with buffers from newWorker
+func newPool(n int, newWorker func() *worker) *pool {
+	p := &pool{jobs: make(chan func(*worker)), size: n, started: time.Now()}
+	p.done.Add(n)
+	for i := 0; i < n; i++ {
+		go func(w *worker) {
+			defer p.done.Done()
+			for job := range p.jobs {
+				start := time.Now()
+				job(w)
+				p.busy.Add(int64(time.Since(start)))
+			}
+		}(newWorker())
+	}
+	return p
+}
+
+// run calls fn(w, i) for every i in [0, n) on the pool and waits for all
+func (p *pool) run(n int, fn func(w *worker, i int)) {
+	var wg sync.WaitGroup
+	wg.Add(n)
+	for i := 0; i < n; i++ {
+		i := i
+		p.jobs <- func(w *worker) {
+			defer wg.Done()
+			fn(w, i)
+		}
+	}
+	wg.Wait()
+}
+
+// close stops the workers once queued jobs finish
+func (p *pool) close() {
+	close(p.jobs)
+	p.done.Wait()
+}
+
+// play runs tasks in lockstep on the worker's vectorised environment. All
+// tasks must share one randomisation. Results are written to out[i].
+func (w *worker) play(tasks []Task, out []env.EpisodeStats) {
+	w.genomes = w.genomes[:0]
+	w.seeds = w.seeds[:0]
+	for _, t := range tasks {
+		w.genomes = append(w.genomes, t.Genome)
+		w.seeds = append(w.seeds, t.Seed)
+	}
+	if cap(w.actions) < len(tasks) {
+		w.actions = make([]int, len(tasks))
+	}
+	w.actions = w.actions[:len(tasks)]
+
+	if len(tasks) > 0 && tasks[0].Randomize != w.vec.Randomization() {
+		w.vec.SetRandomization(tasks[0].Randomize)
+	}
+	w.vec.Reset(w.seeds)
+	for !w.vec.Done() {
+		obs := w.vec.Observe()
+		w.batch.Forward(w.genomes, obs, w.vec.Active(), w.actions)
+		w.vec.Step(w.actions)
+	}
+
+	for i := range tasks {
+		out[i] = w.vec.Stats(i)
+	}
+}
+
+// chunk is a contiguous range of tasks sharing one randomisation
+type chunk struct {
+	start, end int
+}
+
+// splitTasks cuts tasks into chunks of at most size tasks, breaking wherever
+// the randomisation changes. The split depends only on the task list.
+func splitTasks(tasks []Task, size int) []chunk {
+	var chunks []chunk
+	start := 0
+	for i := 1; i <= len(tasks); i++ {
+		if i == len(tasks) || i-start == size || tasks[i].Randomize != tasks[start].Randomize {
+			chunks = append(chunks, chunk{start: start, end: i})
+			start = i
+		}
+	}
+	return chunks
+}
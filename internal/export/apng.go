@@ -0,0 +1,144 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"io"
+	"time"
+)
+
+// pngSignature starts every PNG file
+const pngSignature = "\x89PNG\r\n\x1a\n"
+
+// writeAPNG encodes the recorder's frames as a looping animated PNG,
+// streaming each frame as it is drawn. image/png only writes still images,
+// so the chunks are assembled here: the first frame is the default image
+// (IDAT), later frames are fdAT chunks holding just the region that
+// changed, drawn over the previous frame.
+func writeAPNG(w io.Writer, r *Recorder, delay time.Duration) error {
+	bw := bufio.NewWriter(w)
+	enc := &apngEncoder{w: bw}
+	frames := len(r.frames)
+	b := image.Rect(0, 0, r.width*r.cell, r.height*r.cell)
+
+	enc.raw([]byte(pngSignature))
+
+	var ihdr [13]byte
+	binary.BigEndian.PutUint32(ihdr[0:], uint32(b.Dx()))
+	binary.BigEndian.PutUint32(ihdr[4:], uint32(b.Dy()))
+	ihdr[8] = 8 // bit depth
+	ihdr[9] = 3 // colour type: indexed
+	enc.chunk("IHDR", ihdr[:])
+
+	plte := make([]byte, 0, 3*len(palette))
+	for _, c := range palette {
+		r, g, b, _ := c.RGBA()
+		plte = append(plte, byte(r>>8), byte(g>>8), byte(b>>8))
+	}
+	enc.chunk("PLTE", plte)
+
+	var actl [8]byte
+	binary.BigEndian.PutUint32(actl[0:], uint32(frames))
+	binary.BigEndian.PutUint32(actl[4:], 0) // loop forever
+	enc.chunk("acTL", actl[:])
+
+	ms := max(delay.Milliseconds(), 1)
+	err := r.eachFrame(func(i int, f *image.Paletted, rect image.Rectangle) error {
+		d := ms
+		if i == frames-1 {
+			d *= holdFrames
+		}
+
+		var fctl [26]byte
+		binary.BigEndian.PutUint32(fctl[0:], enc.nextSeq())
+		binary.BigEndian.PutUint32(fctl[4:], uint32(rect.Dx()))
+		binary.BigEndian.PutUint32(fctl[8:], uint32(rect.Dy()))
+		binary.BigEndian.PutUint32(fctl[12:], uint32(rect.Min.X))
+		binary.BigEndian.PutUint32(fctl[16:], uint32(rect.Min.Y))
+		binary.BigEndian.PutUint16(fctl[20:], uint16(min(d, 65535)))
+		binary.BigEndian.PutUint16(fctl[22:], 1000)
+		fctl[24] = 0 // dispose: none
+		fctl[25] = 0 // blend: source
+		enc.chunk("fcTL", fctl[:])
+
+		data, err := compressRows(f, rect)
+		if err != nil {
+			return err
+		}
+		if i == 0 {
+			enc.chunk("IDAT", data)
+		} else {
+			var seq [4]byte
+			binary.BigEndian.PutUint32(seq[:], enc.nextSeq())
+			enc.chunk("fdAT", append(seq[:], data...))
+		}
+		return enc.err
+	})
+	if err != nil {
+		return err
+	}
+
+	enc.chunk("IEND", nil)
+	if enc.err != nil {
+		return enc.err
+	}
+	return bw.Flush()
+}
+
+// apngEncoder writes PNG chunks and numbers the animation chunks, keeping
+// the first write error
+type apngEncoder struct {
+	w   io.Writer
+	seq uint32
+	err error
+}
+
+func (e *apngEncoder) nextSeq() uint32 {
+	e.seq++
+	return e.seq - 1
+}
+
+func (e *apngEncoder) raw(b []byte) {
+	if e.err == nil {
+		_, e.err = e.w.Write(b)
+	}
+}
+
+// chunk writes length, type, data and the CRC over type and data
+func (e *apngEncoder) chunk(typ string, data []byte) {
+	var hdr [8]byte
+	binary.BigEndian.PutUint32(hdr[0:], uint32(len(data)))
+	copy(hdr[4:], typ)
+	crc := crc32.NewIEEE()
+	crc.Write(hdr[4:])
+	crc.Write(data)
+	var sum [4]byte
+	binary.BigEndian.PutUint32(sum[:], crc.Sum32())
+
+	e.raw(hdr[:])
+	e.raw(data)
+	e.raw(sum[:])
+}
+
+// compressRows zlib-compresses the rows of rect, each prefixed with filter
+// type 0 (none)
+func compressRows(img *image.Paletted, rect image.Rectangle) ([]byte, error) {
+	var buf bytes.Buffer
+	zw, err := zlib.NewWriterLevel(&buf, zlib.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		off := img.PixOffset(rect.Min.X, y)
+		zw.Write([]byte{0})
+		zw.Write(img.Pix[off : off+rect.Dx()])
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,147 @@
+package export
+
+import (
+	"image"
+	"image/color"
+	"image/gif"
+	"io"
+	"time"
+
+	"snakeai/internal/env"
+)
+
+// Palette indices
+const (
+	colBackground = iota
+	colGrid
+	colBody
+	colHead
+	colFruit
+	colDead
+)
+
+// palette is shared by every frame so GIF and APNG need a single table
+var palette = color.Palette{
+	colBackground: color.RGBA{0x0d, 0x11, 0x17, 0xff},
+	colGrid:       color.RGBA{0x16, 0x1b, 0x22, 0xff},
+	colBody:       color.RGBA{0x2e, 0xa0, 0x43, 0xff},
+	colHead:       color.RGBA{0x7e, 0xe7, 0x87, 0xff},
+	colFruit:      color.RGBA{0xf8, 0x51, 0x49, 0xff},
+	colDead:       color.RGBA{0xff, 0xa6, 0x57, 0xff},
+}
+
+// eachFrame draws the captured frames in order and calls fn with each one
+// and the rectangle that changed since the previous frame (the whole image
+// for the first). Frames are drawn into two reused buffers, so encoders
+// never hold more than two full frames; fn must not keep img.
+func (r *Recorder) eachFrame(fn func(i int, img *image.Paletted, rect image.Rectangle) error) error {
+	bounds := image.Rect(0, 0, r.width*r.cell, r.height*r.cell)
+	prev, cur := image.NewPaletted(bounds, palette), image.NewPaletted(bounds, palette)
+	for i, s := range r.frames {
+		r.draw(cur, s)
+		rect := bounds
+		if i > 0 {
+			rect = changed(prev, cur)
+		}
+		if err := fn(i, cur, rect); err != nil {
+			return err
+		}
+		prev, cur = cur, prev
+	}
+	return nil
+}
+
+// draw renders one board state over img. Cells are inset by one pixel so
+// the background shows through as grid lines.
+func (r *Recorder) draw(img *image.Paletted, s snapshot) {
+	for i := range img.Pix {
+		img.Pix[i] = colBackground
+	}
+	for y := 0; y < r.height; y++ {
+		for x := 0; x < r.width; x++ {
+			r.fill(img, env.Point{X: x, Y: y}, colGrid)
+		}
+	}
+	if s.fruitOn {
+		r.fill(img, s.fruit, colFruit)
+	}
+	for i := len(s.body) - 1; i >= 0; i-- {
+		c := uint8(colBody)
+		if i == 0 {
+			c = colHead
+			if !s.alive {
+				c = colDead
+			}
+		}
+		r.fill(img, s.body[i], c)
+	}
+}
+
+// fill paints the inside of one board cell
+func (r *Recorder) fill(img *image.Paletted, p env.Point, c uint8) {
+	if p.X < 0 || p.X >= r.width || p.Y < 0 || p.Y >= r.height {
+		return
+	}
+	x0, y0 := p.X*r.cell+1, p.Y*r.cell+1
+	for y := y0; y < y0+r.cell-1; y++ {
+		row := img.Pix[y*img.Stride:]
+		for x := x0; x < x0+r.cell-1; x++ {
+			row[x] = c
+		}
+	}
+}
+
+// changed returns the smallest rectangle holding every pixel that differs
+// between prev and cur, at least one pixel so every frame can be encoded
+func changed(prev, cur *image.Paletted) image.Rectangle {
+	b := cur.Bounds()
+	minX, minY, maxX, maxY := b.Max.X, b.Max.Y, -1, -1
+	for y := 0; y < b.Dy(); y++ {
+		p := prev.Pix[y*prev.Stride : y*prev.Stride+b.Dx()]
+		c := cur.Pix[y*cur.Stride : y*cur.Stride+b.Dx()]
+		for x := range c {
+			if p[x] != c[x] {
+				minX, maxX = min(minX, x), max(maxX, x)
+				minY, maxY = min(minY, y), max(maxY, y)
+			}
+		}
+	}
+	if maxX < 0 {
+		return image.Rect(0, 0, 1, 1)
+	}
+	return image.Rect(minX, minY, maxX+1, maxY+1)
+}
+
+// holdFrames is how many frame delays the final frame stays up before
+// the animation loops
+const holdFrames = 15
+
+// writeGIF encodes the recorder's frames as a looping GIF. Frames after
+// the first only carry the region that changed, copied out of the drawing
+// buffers, since image/gif encodes the whole animation at once.
+func writeGIF(w io.Writer, r *Recorder, delay time.Duration) error {
+	centis := max(int(delay/(10*time.Millisecond)), 2)
+	anim := &gif.GIF{}
+	err := r.eachFrame(func(i int, img *image.Paletted, rect image.Rectangle) error {
+		d := centis
+		if i == len(r.frames)-1 {
+			d *= holdFrames
+		}
+		anim.Image = append(anim.Image, crop(img, rect))
+		anim.Delay = append(anim.Delay, d)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return gif.EncodeAll(w, anim)
+}
+
+// crop copies rect out of img into an image of its own
+func crop(img *image.Paletted, rect image.Rectangle) *image.Paletted {
+	out := image.NewPaletted(rect, palette)
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		copy(out.Pix[out.PixOffset(rect.Min.X, y):out.PixOffset(rect.Max.X, y)], img.Pix[img.PixOffset(rect.Min.X, y):])
+	}
+	return out
+}
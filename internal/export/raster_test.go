@@ -0,0 +1,162 @@
+package export
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/gif"
+	"image/png"
+	"io"
+	"testing"
+	"time"
+
+	"snakeai/internal/env"
+)
+
+// testRecorder captures a short episode that turns, eats and dies
+func testRecorder() *Recorder {
+	g := env.NewGame(10, 8, 3, 200, 60, true, 7)
+	rec := NewRecorder(g.Width, g.Height, 6)
+	rec.Capture(g)
+	actions := []env.Action{
+		env.ActionStraight, env.ActionStraight, env.ActionRight, env.ActionStraight,
+		env.ActionStraight, env.ActionLeft, env.ActionStraight, env.ActionLeft,
+	}
+	for i := 0; g.Alive && i < 200; i++ {
+		g.Step(actions[i%len(actions)])
+		rec.Capture(g)
+	}
+	return rec
+}
+
+// drawn returns every frame of rec as drawn directly
+func drawn(rec *Recorder) []*image.Paletted {
+	var frames []*image.Paletted
+	rec.eachFrame(func(i int, img *image.Paletted, rect image.Rectangle) error {
+		frames = append(frames, crop(img, img.Bounds()))
+		return nil
+	})
+	return frames
+}
+
+func samePixels(a, b *image.Paletted) bool {
+	return a.Bounds() == b.Bounds() && bytes.Equal(a.Pix, b.Pix)
+}
+
+func TestGIFDecodesToEveryFrame(t *testing.T) {
+	rec := testRecorder()
+	var buf bytes.Buffer
+	if err := writeGIF(&buf, rec, 50*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	anim, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := drawn(rec)
+	if len(anim.Image) != len(want) || len(want) < 5 {
+		t.Fatalf("decoded %d frames, captured %d", len(anim.Image), len(want))
+	}
+	if anim.LoopCount != 0 || anim.Delay[0] != 5 || anim.Delay[len(want)-1] != 5*holdFrames {
+		t.Errorf("loop count %d, delays %d and %d", anim.LoopCount, anim.Delay[0], anim.Delay[len(want)-1])
+	}
+
+	// Each frame is drawn over the previous one
+	canvas := image.NewPaletted(want[0].Bounds(), palette)
+	for i, f := range anim.Image {
+		for y := f.Rect.Min.Y; y < f.Rect.Max.Y; y++ {
+			copy(canvas.Pix[canvas.PixOffset(f.Rect.Min.X, y):canvas.PixOffset(f.Rect.Max.X, y)], f.Pix[f.PixOffset(f.Rect.Min.X, y):])
+		}
+		if !samePixels(canvas, want[i]) {
+			t.Fatalf("frame %d differs from the captured board", i)
+		}
+	}
+}
+
+func TestAPNGDecodesToEveryFrame(t *testing.T) {
+	rec := testRecorder()
+	var buf bytes.Buffer
+	if err := writeAPNG(&buf, rec, 50*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	want := drawn(rec)
+
+	// image/png shows the default image, the first frame
+	still, err := png.Decode(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := want[0]
+	for y := 0; y < first.Rect.Dy(); y++ {
+		for x := 0; x < first.Rect.Dx(); x++ {
+			r1, g1, b1, _ := still.At(x, y).RGBA()
+			r2, g2, b2, _ := first.At(x, y).RGBA()
+			if r1 != r2 || g1 != g2 || b1 != b2 {
+				t.Fatalf("still image pixel (%d, %d) differs from the first frame", x, y)
+			}
+		}
+	}
+
+	// Walk the chunks, checking CRCs and sequence numbers, and composite
+	// every frame the way an APNG viewer would
+	data := buf.Bytes()[len(pngSignature):]
+	canvas := image.NewPaletted(first.Bounds(), palette)
+	var seq uint32
+	var frames, declared int
+	var rect image.Rectangle
+	for len(data) > 0 {
+		n := binary.BigEndian.Uint32(data)
+		typ, body := string(data[4:8]), data[8:8+n]
+		if crc32.ChecksumIEEE(data[4:8+n]) != binary.BigEndian.Uint32(data[8+n:]) {
+			t.Fatalf("bad CRC on %s chunk", typ)
+		}
+		data = data[12+n:]
+
+		switch typ {
+		case "acTL":
+			declared = int(binary.BigEndian.Uint32(body))
+		case "fcTL", "fdAT":
+			if got := binary.BigEndian.Uint32(body); got != seq {
+				t.Fatalf("%s sequence number %d, want %d", typ, got, seq)
+			}
+			seq++
+		}
+		switch typ {
+		case "fcTL":
+			x, y := int(binary.BigEndian.Uint32(body[12:])), int(binary.BigEndian.Uint32(body[16:]))
+			w, h := int(binary.BigEndian.Uint32(body[4:])), int(binary.BigEndian.Uint32(body[8:]))
+			rect = image.Rect(x, y, x+w, y+h)
+			if !rect.In(canvas.Bounds()) {
+				t.Fatalf("frame %d region %v outside the image", frames, rect)
+			}
+		case "IDAT", "fdAT":
+			if typ == "fdAT" {
+				body = body[4:]
+			}
+			zr, err := zlib.NewReader(bytes.NewReader(body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			rows, err := io.ReadAll(zr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(rows) != rect.Dy()*(rect.Dx()+1) {
+				t.Fatalf("frame %d has %d bytes of rows for region %v", frames, len(rows), rect)
+			}
+			for y := rect.Min.Y; y < rect.Max.Y; y++ {
+				row := rows[(y-rect.Min.Y)*(rect.Dx()+1):]
+				copy(canvas.Pix[canvas.PixOffset(rect.Min.X, y):canvas.PixOffset(rect.Max.X, y)], row[1:])
+			}
+			if !samePixels(canvas, want[frames]) {
+				t.Fatalf("frame %d differs from the captured board", frames)
+			}
+			frames++
+		}
+	}
+	if frames != len(want) || declared != len(want) {
+		t.Errorf("decoded %d frames, acTL declares %d, captured %d", frames, declared, len(want))
+	}
+}
@@ -0,0 +1,111 @@
+// Package export renders episodes to animated GIF/APNG images and SVG
+// trail plots for sharing outside the terminal.
+package export
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"snakeai/internal/env"
+)
+
+// snapshot is the drawable state of the board at one tick
+type snapshot struct {
+	body    []env.Point // head first
+	fruit   env.Point
+	fruitOn bool
+	alive   bool
+}
+
+// Recorder captures board states during play (live or from a replay) and
+// writes them out in the format chosen by the file extension
+type Recorder struct {
+	width  int
+	height int
+	cell   int // pixels per board cell
+
+	frames []snapshot
+	eaten  []env.Point // head position at each fruit eaten
+	fruits int
+	death  env.DeathReason
+}
+
+// NewRecorder creates a recorder for a width x height board drawn with
+// cell pixels per cell
+func NewRecorder(width, height, cell int) *Recorder {
+	if cell < 2 {
+		cell = 2
+	}
+	return &Recorder{width: width, height: height, cell: cell}
+}
+
+// FromReplay plays a replay back and captures every tick
+func FromReplay(r *env.Replay, cell int) *Recorder {
+	g := r.Playback()
+	rec := NewRecorder(g.Width, g.Height, cell)
+	rec.Capture(g)
+	for _, a := range r.Actions {
+		if !g.Alive {
+			break
+		}
+		g.Step(a)
+		rec.Capture(g)
+	}
+	return rec
+}
+
+// Capture records the current board. Call it once after reset and again
+// after every step.
+func (r *Recorder) Capture(g *env.Game) {
+	if g.FruitsEaten > r.fruits {
+		r.eaten = append(r.eaten, g.Head())
+	}
+	r.fruits = g.FruitsEaten
+	r.death = g.DeathReason
+	r.frames = append(r.frames, snapshot{
+		body:    g.Body(nil),
+		fruit:   g.Fruit,
+		fruitOn: g.FruitEnabled,
+		alive:   g.Alive,
+	})
+}
+
+// Frames returns the number of captured frames
+func (r *Recorder) Frames() int {
+	return len(r.frames)
+}
+
+// Save writes the capture to path: .gif for an animated GIF, .png or .apng
+// for an animated PNG, .svg for a static plot of the head's trail. delay is
+// the time each frame is shown.
+func (r *Recorder) Save(path string, delay time.Duration) error {
+	if len(r.frames) == 0 {
+		return fmt.Errorf("no frames captured")
+	}
+	ext := strings.ToLower(filepath.Ext(path))
+	switch ext {
+	case ".gif", ".png", ".apng", ".svg":
+	default:
+		return fmt.Errorf("unknown export format %q (want .gif, .png, .apng or .svg)", ext)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	switch ext {
+	case ".gif":
+		err = writeGIF(f, r, delay)
+	case ".svg":
+		err = r.writeSVG(f)
+	default:
+		err = writeAPNG(f, r, delay)
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
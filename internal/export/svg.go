@@ -0,0 +1,82 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+
+	"snakeai/internal/env"
+)
+
+// writeSVG draws a static plot of the whole episode: the head's path
+// coloured from blue (start) to red (end), the cells where fruit was
+// eaten, the start cell and the final head position
+func (r *Recorder) writeSVG(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	c := r.cell
+	width, height := r.width*c, r.height*c
+	footer := 24
+
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="monospace">`+"\n",
+		width, height+footer, width, height+footer)
+	fmt.Fprintf(bw, `<rect width="%d" height="%d" fill="#0d1117"/>`+"\n", width, height+footer)
+
+	// Grid
+	fmt.Fprintf(bw, `<g stroke="#21262d" stroke-width="1">`+"\n")
+	for x := 0; x <= r.width; x++ {
+		fmt.Fprintf(bw, `<line x1="%d" y1="0" x2="%d" y2="%d"/>`+"\n", x*c, x*c, height)
+	}
+	for y := 0; y <= r.height; y++ {
+		fmt.Fprintf(bw, `<line x1="0" y1="%d" x2="%d" y2="%d"/>`+"\n", y*c, width, y*c)
+	}
+	fmt.Fprintf(bw, "</g>\n")
+
+	// Head path, one segment per tick so the colour can follow time
+	heads := make([]env.Point, 0, len(r.frames))
+	for _, s := range r.frames {
+		if len(s.body) > 0 {
+			heads = append(heads, s.body[0])
+		}
+	}
+	fmt.Fprintf(bw, `<g stroke-width="%.1f" stroke-linecap="round" fill="none">`+"\n", float64(c)/4)
+	for i := 1; i < len(heads); i++ {
+		a, b := heads[i-1], heads[i]
+		if a == b {
+			continue
+		}
+		hue := 240 * (1 - float64(i)/float64(len(heads)-1))
+		x1, y1 := r.centre(a)
+		x2, y2 := r.centre(b)
+		fmt.Fprintf(bw, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="hsl(%.0f,80%%,55%%)"/>`+"\n",
+			x1, y1, x2, y2, hue)
+	}
+	fmt.Fprintf(bw, "</g>\n")
+
+	// Fruit eaten, numbered in order
+	for i, p := range r.eaten {
+		x, y := r.centre(p)
+		fmt.Fprintf(bw, `<circle cx="%.1f" cy="%.1f" r="%.1f" fill="#f85149"><title>fruit %d</title></circle>`+"\n",
+			x, y, float64(c)/4, i+1)
+	}
+
+	// Start and end markers
+	if len(heads) > 0 {
+		x, y := r.centre(heads[0])
+		fmt.Fprintf(bw, `<circle cx="%.1f" cy="%.1f" r="%.1f" fill="none" stroke="#7ee787" stroke-width="2"><title>start</title></circle>`+"\n",
+			x, y, float64(c)/3)
+		x, y = r.centre(heads[len(heads)-1])
+		d := float64(c) / 3
+		fmt.Fprintf(bw, `<path d="M%.1f %.1fL%.1f %.1fM%.1f %.1fL%.1f %.1f" stroke="#ffa657" stroke-width="2"><title>end: %s</title></path>`+"\n",
+			x-d, y-d, x+d, y+d, x-d, y+d, x+d, y-d, r.death)
+	}
+
+	fmt.Fprintf(bw, `<text x="4" y="%d" font-size="12" fill="#c9d1d9">ticks %d · fruits %d · %s</text>`+"\n",
+		height+16, len(r.frames)-1, r.fruits, r.death)
+	fmt.Fprintf(bw, "</svg>\n")
+	return bw.Flush()
+}
+
+// centre returns the pixel centre of a board cell
+func (r *Recorder) centre(p env.Point) (float64, float64) {
+	return (float64(p.X) + 0.5) * float64(r.cell), (float64(p.Y) + 0.5) * float64(r.cell)
+}
@@ -0,0 +1,182 @@
+package ga
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+)
+
+// MutationControls lists the step-size control methods
+var MutationControls = []string{"fixed", "one_fifth", "self_adaptive"}
+
+// MutationSchedules lists the annealing schedules for rate and sigma
+var MutationSchedules = []string{"constant", "linear", "exponential", "cosine"}
+
+// MutationParams configures a MutationController
+type MutationParams struct {
+	Control  string // fixed|one_fifth|self_adaptive
+	Schedule string // constant|linear|exponential|cosine
+
+	Rate, RateEnd   float64 // per-weight mutation probability at the first and last generation
+	Sigma, SigmaEnd float64 // Gaussian step size at the first and last generation
+
+	// Bounds on sigma under one_fifth and self_adaptive control
+	SigmaMin, SigmaMax float64
+
+	SuccessTarget float64 // one_fifth: success rate to hold sigma steady at
+	SuccessFactor float64 // one_fifth: sigma multiplier per generation below target, divisor above
+	Tau           float64 // self_adaptive: log-normal learning rate, 0 for 1/sqrt(genome size)
+}
+
+// Mutation is the effective mutation of one generation. Offspring mutate
+// each weight with probability min(1, Rate*Boost) by a Gaussian step of
+// Sigma*Boost, or of their own sigma times Boost under self-adaptation.
+type Mutation struct {
+	Rate        float64 `json:"rate"`
+	Sigma       float64 `json:"sigma"`        // mean per-agent sigma under self-adaptation
+	Boost       float64 `json:"boost"`        // multiplier from interventions such as diversity collapse
+	Offspring   int     `json:"offspring"`    // agents bred last generation and evaluated this one
+	SuccessRate float64 `json:"success_rate"` // offspring fitter than their fitter parent (see Next)
+}
+
+// MutationController sets the mutation rate and step size of each
+// generation from an annealing schedule and, optionally, the 1/5th success
+// rule or per-agent self-adaptation
+type MutationController struct {
+	params      MutationParams
+	generations int
+	scale       float64 // one_fifth multiplier on the scheduled sigma
+}
+
+// NewMutationController validates params for a run of the given number of
+// generations over genomes of genomeSize weights
+func NewMutationController(params MutationParams, genomeSize, generations int) (*MutationController, error) {
+	if !contains(MutationControls, params.Control) {
+		return nil, fmt.Errorf("unknown mutation control %q (want one of %s)", params.Control, strings.Join(MutationControls, ", "))
+	}
+	if !contains(MutationSchedules, params.Schedule) {
+		return nil, fmt.Errorf("unknown mutation schedule %q (want one of %s)", params.Schedule, strings.Join(MutationSchedules, ", "))
+	}
+	if params.Schedule == "exponential" && (params.Rate <= 0 || params.RateEnd <= 0 || params.Sigma <= 0 || params.SigmaEnd <= 0) {
+		return nil, fmt.Errorf("exponential mutation schedule needs positive start and end values")
+	}
+	if params.Control != "fixed" && params.SigmaMin > params.SigmaMax {
+		return nil, fmt.Errorf("mutation sigma bounds [%g, %g] are empty", params.SigmaMin, params.SigmaMax)
+	}
+	if params.Tau == 0 && genomeSize > 0 {
+		params.Tau = 1 / math.Sqrt(float64(genomeSize))
+	}
+	return &MutationController{params: params, generations: generations, scale: 1}, nil
+}
+
+// Next returns the mutation for breeding generation gen (from 1). agents are
+// the offspring just evaluated, whose lineage drives the 1/5th success rule
+// and whose step sizes are averaged under self-adaptation. An offspring
+// succeeds if it beats its ParentFitness, which compares like with like
+// only once the parents are re-scored on the offspring's seeds
+// (eval.Evaluator.ScoreParents, done under one_fifth); otherwise it is the
+// parent's score on its own generation's seeds.
+func (c *MutationController) Next(gen int, agents []*Agent) Mutation {
+	p := c.params
+	m := Mutation{Boost: 1}
+
+	var sigmas float64
+	for _, a := range agents {
+		sigmas += math.Log(c.agentSigma(a))
+		if !a.Bred {
+			continue
+		}
+		m.Offspring++
+		if a.Fitness > a.ParentFitness {
+			m.SuccessRate++
+		}
+	}
+	if m.Offspring > 0 {
+		m.SuccessRate /= float64(m.Offspring)
+	}
+
+	progress := 0.0
+	if c.generations > 1 {
+		progress = float64(gen-1) / float64(c.generations-1)
+	}
+	m.Rate = anneal(p.Schedule, p.Rate, p.RateEnd, progress)
+	m.Sigma = anneal(p.Schedule, p.Sigma, p.SigmaEnd, progress)
+
+	switch p.Control {
+	case "one_fifth":
+		// Rechenberg: widen the search while more than the target share of
+		// offspring improve on their parents, narrow it while fewer do
+		if m.Offspring > 0 {
+			if m.SuccessRate > p.SuccessTarget {
+				c.scale /= p.SuccessFactor
+			} else if m.SuccessRate < p.SuccessTarget {
+				c.scale *= p.SuccessFactor
+			}
+		}
+		sigma := clamp(m.Sigma*c.scale, p.SigmaMin, p.SigmaMax)
+		if m.Sigma > 0 {
+			// Keep the multiplier from running away while sigma is clamped
+			c.scale = sigma / m.Sigma
+		}
+		m.Sigma = sigma
+	case "self_adaptive":
+		if len(agents) > 0 {
+			m.Sigma = math.Exp(sigmas / float64(len(agents)))
+		}
+	}
+	return m
+}
+
+// Mutate records the child's lineage and mutates its genome with m. Under
+// self-adaptation the child first inherits the geometric mean of its
+// parents' step sizes and perturbs it log-normally, so step sizes that
+// produce fit offspring spread through the population.
+func (c *MutationController) Mutate(child, p1, p2 *Agent, m Mutation, resetP float64, rng *rand.Rand) {
+	child.Bred = true
+	child.ParentFitness = math.Max(p1.Fitness, p2.Fitness)
+	child.ParentGenomes = [][]float32{p1.Genome, p2.Genome}
+
+	sigma := m.Sigma
+	if c.params.Control == "self_adaptive" {
+		inherited := math.Sqrt(c.agentSigma(p1) * c.agentSigma(p2))
+		child.MutationSigma = clamp(inherited*math.Exp(c.params.Tau*rng.NormFloat64()), c.params.SigmaMin, c.params.SigmaMax)
+		sigma = child.MutationSigma
+	}
+	MutateAgent(child, math.Min(1, m.Rate*m.Boost), sigma*m.Boost, resetP, rng)
+}
+
+// agentSigma returns an agent's own step size, or the initial sigma for
+// agents that have not carried one yet
+func (c *MutationController) agentSigma(a *Agent) float64 {
+	if a.MutationSigma > 0 {
+		return a.MutationSigma
+	}
+	return c.params.Sigma
+}
+
+// anneal interpolates from start to end by progress in [0, 1]
+func anneal(schedule string, start, end, progress float64) float64 {
+	switch schedule {
+	case "linear":
+		return start + (end-start)*progress
+	case "exponential":
+		return start * math.Pow(end/start, progress)
+	case "cosine":
+		return end + (start-end)*(1+math.Cos(math.Pi*progress))/2
+	}
+	return start
+}
+
+func clamp(v, lo, hi float64) float64 {
+	return math.Max(lo, math.Min(hi, v))
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+package ga
+
+import (
+	"fmt"
+	"math/rand"
+
+	"snakeai/internal/config"
+	"snakeai/internal/nn"
+)
+
+// Breeder creates offspring with the configured selection scheme,
+// crossover operator and mutation control
+type Breeder struct {
+	Mutation *MutationController
+
+	cfg       config.GAConfig
+	selector  Selector
+	crossover Crossover
+}
+
+// NewBreeder creates a breeder for a run of the given number of
+// generations, checking the operator names in cfg
+func NewBreeder(cfg *config.Config, generations int) (*Breeder, error) {
+	neurons := nn.NeuronSpans(cfg.ObsDim(), cfg.NN.Hidden1, cfg.NN.Hidden2, 3)
+	genomeSize := neurons[len(neurons)-1][1]
+
+	selector, err := NewSelector(SelectionParams{
+		Scheme:       cfg.GA.Selection,
+		TournamentK:  cfg.GA.TournamentK,
+		RankPressure: cfg.GA.RankPressure,
+		RankBase:     cfg.GA.RankBase,
+		Temperature:  cfg.GA.BoltzmannTemperature,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if cfg.GA.Selection == "mu_plus_lambda" && cfg.GA.SelectionPool >= cfg.GA.Population {
+		return nil, fmt.Errorf("mu_plus_lambda needs selection_pool (mu, %d) below population (mu + lambda, %d)",
+			cfg.GA.SelectionPool, cfg.GA.Population)
+	}
+	cross, err := NewCrossover(CrossoverParams{
+		Operator: cfg.GA.Crossover,
+		Alpha:    cfg.GA.BLXAlpha,
+		Eta:      cfg.GA.SBXEta,
+		Neurons:  neurons,
+	})
+	if err != nil {
+		return nil, err
+	}
+	mutation, err := NewMutationController(MutationParams{
+		Control:       cfg.GA.MutationControl,
+		Schedule:      cfg.GA.MutationSchedule,
+		Rate:          cfg.GA.MutationRate,
+		RateEnd:       cfg.GA.MutationRateEnd,
+		Sigma:         cfg.GA.MutationSigma,
+		SigmaEnd:      cfg.GA.MutationSigmaEnd,
+		SigmaMin:      cfg.GA.MutationSigmaMin,
+		SigmaMax:      cfg.GA.MutationSigmaMax,
+		SuccessTarget: cfg.GA.SuccessTarget,
+		SuccessFactor: cfg.GA.SuccessFactor,
+		Tau:           cfg.GA.SelfAdaptTau,
+	}, genomeSize, generations)
+	if err != nil {
+		return nil, err
+	}
+	return &Breeder{Mutation: mutation, cfg: cfg.GA, selector: selector, crossover: cross}, nil
+}
+
+// Children recombines two parents and mutates both children with step
+func (b *Breeder) Children(p1, p2 *Agent, step Mutation, rng *rand.Rand) (*Agent, *Agent) {
+	c1, c2 := CreateChildren(p1, p2, b.cfg.CrossoverRate, b.crossover, rng)
+	b.Mutation.Mutate(c1, p1, p2, step, b.cfg.ResetMutationP, rng)
+	b.Mutation.Mutate(c2, p1, p2, step, b.cfg.ResetMutationP, rng)
+	return c1, c2
+}
+
+// NextGeneration keeps copies of the elites of pop and fills the rest of
+// the population with pairs of children of parents selected from the
+// selection pool. The (mu, lambda) schemes keep no elites: the pool is the
+// mu best, which under mu_plus_lambda survive as copies alongside their
+// offspring, to be evaluated again with them under eval.elite_policy.
+func (b *Breeder) NextGeneration(pop *Population, step Mutation, rng *rand.Rand) []*Agent {
+	next := make([]*Agent, 0, b.cfg.Population)
+
+	// 1. Keep elites
+	pop.SortByFitness()
+	if b.cfg.Selection != "mu_lambda" && b.cfg.Selection != "mu_plus_lambda" {
+		for i := 0; i < b.cfg.Elites && i < len(pop.Agents); i++ {
+			next = append(next, pop.Agents[i].Clone())
+		}
+	}
+
+	// 2. Create selection pool
+	pool := SelectionPool(pop, b.cfg.SelectionPool)
+	if b.cfg.Selection == "mu_plus_lambda" {
+		for _, a := range pool {
+			next = append(next, a.Clone())
+		}
+	}
+
+	// 3. Fill rest with offspring, dropping the second child of an odd slot
+	return append(next, b.Offspring(pool, b.cfg.Population-len(next), step, rng)...)
+}
+
+// Offspring breeds n children from parents the selection scheme draws from
+// pool, which must be sorted by descending fitness for fitness-based schemes
+func (b *Breeder) Offspring(pool []*Agent, n int, step Mutation, rng *rand.Rand) []*Agent {
+	parents := b.selector(pool, n+n%2, rng)
+	offspring := make([]*Agent, 0, n)
+	for i := 0; len(offspring) < n; i += 2 {
+		c1, c2 := b.Children(parents[i], parents[i+1], step, rng)
+		offspring = append(offspring, c1)
+		if len(offspring) < n {
+			offspring = append(offspring, c2)
+		}
+	}
+	return offspring
+}
@@ -0,0 +1,125 @@
+package ga
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"snakeai/internal/config"
+	"snakeai/internal/nn"
+)
+
+// breedingPopulation loads a config with the given ga block and returns a
+// breeder for it and a random population of distinct fitness
+func breedingPopulation(t *testing.T, ga string, rng *rand.Rand) (*Breeder, *Population) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("ga: "+ga+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := NewBreeder(cfg, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	size := nn.NewMLP(cfg.ObsDim(), cfg.NN.Hidden1, cfg.NN.Hidden2, 3).GenomeSize()
+	pop := NewPopulation(cfg.GA.Population, size, rng)
+	for _, a := range pop.Agents {
+		a.Fitness = 100 * rng.Float64()
+	}
+	return b, pop
+}
+
+// genomeOwners maps each genome's backing array to its agent, so children
+// can be traced back to the agents their parent genomes came from
+func genomeOwners(agents []*Agent) map[*float32]*Agent {
+	owners := make(map[*float32]*Agent, len(agents))
+	for _, a := range agents {
+		owners[&a.Genome[0]] = a
+	}
+	return owners
+}
+
+func TestExponentialRankPressureRisesAsBaseFalls(t *testing.T) {
+	rng := rand.New(rand.NewSource(6))
+	agents := normalPopulation(200, rng)
+	var last float64
+	for i, base := range []float64{0.99, 0.97, 0.93, 0.85} {
+		sel := mustSelector(t, SelectionParams{Scheme: "exponential_rank", RankBase: base})
+		p := MeasureSelectionPressure(sel, agents, len(agents), 200, rng)
+		if i > 0 && p.Intensity <= last {
+			t.Errorf("exponential_rank base %g intensity %.3f not above %.3f at a higher base", base, p.Intensity, last)
+		}
+		last = p.Intensity
+	}
+}
+
+func TestMuLambdaBreedsOnlyFromTheMuBest(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	b, pop := breedingPopulation(t, "{selection: mu_lambda, population: 60, selection_pool: 10}", rng)
+	mu := SelectionPool(pop, 10)
+	owners := genomeOwners(pop.Agents)
+	inPool := make(map[*Agent]bool)
+	for _, a := range mu {
+		inPool[a] = true
+	}
+
+	next := b.NextGeneration(pop, b.Mutation.Next(1, pop.Agents), rng)
+	if len(next) != 60 {
+		t.Fatalf("got %d agents, want 60", len(next))
+	}
+	for i, child := range next {
+		if !child.Bred {
+			t.Fatalf("agent %d survived unchanged under mu_lambda", i)
+		}
+		for _, g := range child.ParentGenomes {
+			if parent := owners[&g[0]]; !inPool[parent] {
+				t.Fatalf("agent %d has a parent outside the %d best", i, len(mu))
+			}
+		}
+	}
+}
+
+func TestMuPlusLambdaKeepsTheParents(t *testing.T) {
+	rng := rand.New(rand.NewSource(8))
+	b, pop := breedingPopulation(t, "{selection: mu_plus_lambda, population: 60, selection_pool: 10}", rng)
+	mu := append([]*Agent(nil), SelectionPool(pop, 10)...)
+	owners := genomeOwners(mu)
+
+	next := b.NextGeneration(pop, b.Mutation.Next(1, pop.Agents), rng)
+	if len(next) != 60 {
+		t.Fatalf("got %d agents, want 60", len(next))
+	}
+	for i, parent := range mu {
+		survivor := next[i]
+		if survivor.Bred || survivor.Fitness != parent.Fitness || !equalGenomes(survivor.Genome, parent.Genome) {
+			t.Fatalf("agent %d is not a copy of parent %d", i, i)
+		}
+	}
+	for i, child := range next[len(mu):] {
+		if !child.Bred {
+			t.Fatalf("offspring %d was not bred", i)
+		}
+		for _, g := range child.ParentGenomes {
+			if owners[&g[0]] == nil {
+				t.Fatalf("offspring %d has a parent outside the %d best", i, len(mu))
+			}
+		}
+	}
+}
+
+func equalGenomes(a, b []float32) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
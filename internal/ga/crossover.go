@@ -1,11 +1,60 @@
 package ga
 
 import (
+	"fmt"
+	"math"
 	"math/rand"
+	"strings"
 
 	"snakeai/internal/nn"
 )
 
+// CrossoverOperators lists the operators a Crossover can apply
+var CrossoverOperators = []string{"uniform", "single_point", "two_point", "arithmetic", "blx", "sbx", "neuron"}
+
+// Crossover recombines two parent genomes into two children
+type Crossover func(p1, p2 []float32, rng *rand.Rand) ([]float32, []float32)
+
+// CrossoverParams configures NewCrossover
+type CrossoverParams struct {
+	Operator string   // one of CrossoverOperators
+	Alpha    float64  // blx: interval extension on each side of the parents
+	Eta      float64  // sbx: distribution index; larger keeps children nearer their parents
+	Neurons  [][2]int // neuron: genome span of each neuron, from nn.NeuronSpans
+}
+
+// NewCrossover returns the named operator
+func NewCrossover(p CrossoverParams) (Crossover, error) {
+	switch p.Operator {
+	case "uniform":
+		return func(p1, p2 []float32, rng *rand.Rand) ([]float32, []float32) {
+			return UniformCrossover(p1, p2, 0.5, rng)
+		}, nil
+	case "single_point":
+		return SinglePointCrossover, nil
+	case "two_point":
+		return TwoPointCrossover, nil
+	case "arithmetic":
+		return ArithmeticCrossover, nil
+	case "blx":
+		return func(p1, p2 []float32, rng *rand.Rand) ([]float32, []float32) {
+			return BLXCrossover(p1, p2, p.Alpha, rng)
+		}, nil
+	case "sbx":
+		return func(p1, p2 []float32, rng *rand.Rand) ([]float32, []float32) {
+			return SBXCrossover(p1, p2, p.Eta, rng)
+		}, nil
+	case "neuron":
+		if len(p.Neurons) == 0 {
+			return nil, fmt.Errorf("neuron crossover needs the network layout")
+		}
+		return func(p1, p2 []float32, rng *rand.Rand) ([]float32, []float32) {
+			return NeuronCrossover(p1, p2, p.Neurons, rng)
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown crossover %q (want one of %s)", p.Operator, strings.Join(CrossoverOperators, ", "))
+}
+
 // UniformCrossover performs uniform crossover between two parents
 // Returns two children genomes
 func UniformCrossover(p1, p2 []float32, rate float64, rng *rand.Rand) ([]float32, []float32) {
@@ -31,6 +80,9 @@ func UniformCrossover(p1, p2 []float32, rate float64, rng *rand.Rand) ([]float32
 // SinglePointCrossover performs single-point crossover
 func SinglePointCrossover(p1, p2 []float32, rng *rand.Rand) ([]float32, []float32) {
 	size := len(p1)
+	if size == 0 {
+		return []float32{}, []float32{}
+	}
 	point := rng.Intn(size)
 
 	c1 := make([]float32, size)
@@ -44,18 +96,92 @@ func SinglePointCrossover(p1, p2 []float32, rng *rand.Rand) ([]float32, []float3
 	return c1, c2
 }
 
-// CreateChild creates a single child from two parents using crossover
-func CreateChild(p1, p2 *Agent, crossoverRate float64, rng *rand.Rand) *Agent {
-	if rng.Float64() > crossoverRate {
-		// No crossover, clone one parent
-		if rng.Float64() < 0.5 {
-			return &Agent{Genome: nn.CloneGenome(p1.Genome)}
+// TwoPointCrossover swaps the segment between two random cut points
+func TwoPointCrossover(p1, p2 []float32, rng *rand.Rand) ([]float32, []float32) {
+	size := len(p1)
+	a, b := rng.Intn(size+1), rng.Intn(size+1)
+	if a > b {
+		a, b = b, a
+	}
+
+	c1 := nn.CloneGenome(p1)
+	c2 := nn.CloneGenome(p2)
+	copy(c1[a:b], p2[a:b])
+	copy(c2[a:b], p1[a:b])
+
+	return c1, c2
+}
+
+// ArithmeticCrossover blends the parents with one random weight: the
+// children are w*p1 + (1-w)*p2 and (1-w)*p1 + w*p2
+func ArithmeticCrossover(p1, p2 []float32, rng *rand.Rand) ([]float32, []float32) {
+	w := float32(rng.Float64())
+	c1 := make([]float32, len(p1))
+	c2 := make([]float32, len(p1))
+	for i := range p1 {
+		c1[i] = w*p1[i] + (1-w)*p2[i]
+		c2[i] = (1-w)*p1[i] + w*p2[i]
+	}
+	return c1, c2
+}
+
+// BLXCrossover draws every child gene uniformly from the parents' interval
+// extended by alpha times its width on each side (BLX-alpha)
+func BLXCrossover(p1, p2 []float32, alpha float64, rng *rand.Rand) ([]float32, []float32) {
+	c1 := make([]float32, len(p1))
+	c2 := make([]float32, len(p1))
+	for i := range p1 {
+		lo, hi := float64(min(p1[i], p2[i])), float64(max(p1[i], p2[i]))
+		ext := alpha * (hi - lo)
+		lo, hi = lo-ext, hi+ext
+		c1[i] = float32(lo + rng.Float64()*(hi-lo))
+		c2[i] = float32(lo + rng.Float64()*(hi-lo))
+	}
+	return c1, c2
+}
+
+// SBXCrossover performs simulated binary crossover: the children spread
+// symmetrically about the parents' mean by a random factor whose
+// distribution mimics single-point crossover on binary strings
+func SBXCrossover(p1, p2 []float32, eta float64, rng *rand.Rand) ([]float32, []float32) {
+	c1 := make([]float32, len(p1))
+	c2 := make([]float32, len(p1))
+	for i := range p1 {
+		u := rng.Float64()
+		var beta float64
+		if u <= 0.5 {
+			beta = math.Pow(2*u, 1/(eta+1))
+		} else {
+			beta = math.Pow(1/(2*(1-u)), 1/(eta+1))
 		}
-		return &Agent{Genome: nn.CloneGenome(p2.Genome)}
+		x1, x2 := float64(p1[i]), float64(p2[i])
+		c1[i] = float32(0.5 * ((1+beta)*x1 + (1-beta)*x2))
+		c2[i] = float32(0.5 * ((1-beta)*x1 + (1+beta)*x2))
 	}
+	return c1, c2
+}
 
-	// Uniform crossover, take only first child
-	c1, _ := UniformCrossover(p1.Genome, p2.Genome, 0.5, rng)
-	return &Agent{Genome: c1}
+// NeuronCrossover swaps whole neurons between the parents: each neuron's
+// bias and incoming weights come from the same parent, so features a
+// parent's hidden units compute survive recombination intact
+func NeuronCrossover(p1, p2 []float32, neurons [][2]int, rng *rand.Rand) ([]float32, []float32) {
+	c1 := nn.CloneGenome(p1)
+	c2 := nn.CloneGenome(p2)
+	for _, span := range neurons {
+		if rng.Float64() < 0.5 {
+			copy(c1[span[0]:span[1]], p2[span[0]:span[1]])
+			copy(c2[span[0]:span[1]], p1[span[0]:span[1]])
+		}
+	}
+	return c1, c2
 }
 
+// CreateChildren creates two children from two parents, recombined by
+// cross with probability crossoverRate and otherwise copies of the parents
+func CreateChildren(p1, p2 *Agent, crossoverRate float64, cross Crossover, rng *rand.Rand) (*Agent, *Agent) {
+	if rng.Float64() > crossoverRate {
+		return &Agent{Genome: nn.CloneGenome(p1.Genome)}, &Agent{Genome: nn.CloneGenome(p2.Genome)}
+	}
+	c1, c2 := cross(p1.Genome, p2.Genome, rng)
+	return &Agent{Genome: c1}, &Agent{Genome: c2}
+}
@@ -0,0 +1,157 @@
+package ga
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"snakeai/internal/nn"
+)
+
+// crossoverParents returns two parent genomes with distinct genes
+func crossoverParents(size int, rng *rand.Rand) ([]float32, []float32) {
+	return nn.RandomGenome(size, rng), nn.RandomGenome(size, rng)
+}
+
+// testNeurons is a small layout: four neurons of uneven width
+var testNeurons = [][2]int{{0, 3}, {3, 8}, {8, 10}, {10, 16}}
+
+func mustCrossover(t *testing.T, op string) Crossover {
+	t.Helper()
+	cross, err := NewCrossover(CrossoverParams{Operator: op, Alpha: 0.5, Eta: 15, Neurons: testNeurons})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cross
+}
+
+func TestCrossoverHandlesEmptyGenomes(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, op := range CrossoverOperators {
+		cross, err := NewCrossover(CrossoverParams{Operator: op, Alpha: 0.5, Eta: 15, Neurons: [][2]int{{0, 0}}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		c1, c2 := cross(nil, nil, rng)
+		if len(c1) != 0 || len(c2) != 0 {
+			t.Errorf("%s: got children of length %d and %d from empty parents", op, len(c1), len(c2))
+		}
+	}
+}
+
+func TestSwappingCrossoversPreserveGenes(t *testing.T) {
+	// Operators that swap genes give the children the parents' pair of
+	// values at every position, one each
+	rng := rand.New(rand.NewSource(2))
+	for _, op := range []string{"uniform", "single_point", "two_point", "neuron"} {
+		cross := mustCrossover(t, op)
+		for round := 0; round < 50; round++ {
+			p1, p2 := crossoverParents(16, rng)
+			c1, c2 := cross(p1, p2, rng)
+			for i := range p1 {
+				if !(c1[i] == p1[i] && c2[i] == p2[i]) && !(c1[i] == p2[i] && c2[i] == p1[i]) {
+					t.Fatalf("%s: position %d has children %v, %v from parents %v, %v", op, i, c1[i], c2[i], p1[i], p2[i])
+				}
+			}
+		}
+	}
+}
+
+func TestNeuronCrossoverKeepsNeuronsWhole(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	cross := mustCrossover(t, "neuron")
+	swapped := make([]int, len(testNeurons))
+	for round := 0; round < 100; round++ {
+		p1, p2 := crossoverParents(16, rng)
+		c1, _ := cross(p1, p2, rng)
+		for n, span := range testNeurons {
+			from1, from2 := true, true
+			for i := span[0]; i < span[1]; i++ {
+				from1 = from1 && c1[i] == p1[i]
+				from2 = from2 && c1[i] == p2[i]
+			}
+			if !from1 && !from2 {
+				t.Fatalf("round %d: neuron %d mixes weights from both parents", round, n)
+			}
+			if from2 {
+				swapped[n]++
+			}
+		}
+	}
+	for n, count := range swapped {
+		if count < 30 || count > 70 {
+			t.Errorf("neuron %d swapped in %d of 100 rounds, want about half", n, count)
+		}
+	}
+}
+
+func TestBlendingCrossoverBounds(t *testing.T) {
+	const tol = 1e-5
+	rng := rand.New(rand.NewSource(4))
+	for round := 0; round < 50; round++ {
+		p1, p2 := crossoverParents(16, rng)
+
+		// Arithmetic children lie between the parents and sum to them
+		c1, c2 := ArithmeticCrossover(p1, p2, rng)
+		for i := range p1 {
+			lo, hi := float64(min(p1[i], p2[i])), float64(max(p1[i], p2[i]))
+			for _, c := range []float32{c1[i], c2[i]} {
+				if float64(c) < lo-tol || float64(c) > hi+tol {
+					t.Fatalf("arithmetic: gene %d child %v outside [%v, %v]", i, c, lo, hi)
+				}
+			}
+			if d := float64(c1[i]+c2[i]) - float64(p1[i]+p2[i]); math.Abs(d) > tol {
+				t.Fatalf("arithmetic: gene %d children do not sum to the parents", i)
+			}
+		}
+
+		// BLX children lie in the parents' interval extended by alpha of
+		// its width on each side
+		const alpha = 0.5
+		c1, c2 = BLXCrossover(p1, p2, alpha, rng)
+		for i := range p1 {
+			lo, hi := float64(min(p1[i], p2[i])), float64(max(p1[i], p2[i]))
+			ext := alpha * (hi - lo)
+			for _, c := range []float32{c1[i], c2[i]} {
+				if float64(c) < lo-ext-tol || float64(c) > hi+ext+tol {
+					t.Fatalf("blx: gene %d child %v outside [%v, %v]", i, c, lo-ext, hi+ext)
+				}
+			}
+		}
+
+		// SBX children are symmetric about the parents' mean
+		c1, c2 = SBXCrossover(p1, p2, 15, rng)
+		for i := range p1 {
+			if d := float64(c1[i]+c2[i]) - float64(p1[i]+p2[i]); math.Abs(d) > tol {
+				t.Fatalf("sbx: gene %d children %v, %v not centred on parents %v, %v", i, c1[i], c2[i], p1[i], p2[i])
+			}
+		}
+	}
+}
+
+func TestSBXSpreadShrinksWithEta(t *testing.T) {
+	// The children's spread relative to the parents' is beta, which
+	// concentrates at 1 as eta grows
+	rng := rand.New(rand.NewSource(5))
+	last := math.Inf(1)
+	for _, eta := range []float64{1, 5, 20, 100} {
+		dev := 0.0
+		n := 0
+		for round := 0; round < 200; round++ {
+			p1, p2 := crossoverParents(16, rng)
+			c1, c2 := SBXCrossover(p1, p2, eta, rng)
+			for i := range p1 {
+				if gap := float64(p2[i] - p1[i]); math.Abs(gap) > 1e-3 {
+					beta := float64(c2[i]-c1[i]) / gap
+					dev += math.Abs(beta - 1)
+					n++
+				}
+			}
+		}
+		dev /= float64(n)
+		if dev >= last {
+			t.Errorf("sbx eta %g: mean |beta-1| %.4f not below %.4f at a smaller eta", eta, dev, last)
+		}
+		last = dev
+	}
+}
@@ -0,0 +1,159 @@
+package ga
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Diversity summarises how spread out a population is, in genome space and
+// in behaviour. Behavioural measures read each agent's last episode stats,
+// so they are most meaningful after single-seed evaluation, where every
+// agent plays the same episode.
+type Diversity struct {
+	CentroidDistance  float64 `json:"centroid_distance"`  // mean Euclidean distance of genomes to the centroid
+	PairwiseDistance  float64 `json:"pairwise_distance"`  // mean Euclidean distance between pairs of genomes
+	WeightStd         float64 `json:"weight_std"`         // per-weight standard deviation, averaged over weights
+	WeightVariance    float64 `json:"weight_variance"`    // per-weight variance, averaged over weights
+	FitnessStd        float64 `json:"fitness_std"`        // standard deviation of fitness
+	BehaviourDistance float64 `json:"behaviour_distance"` // mean pairwise distance of behaviour descriptors, each in [0, 1]
+	DeathEntropy      float64 `json:"death_entropy"`      // Shannon entropy of death reasons in bits
+	UniqueActions     int     `json:"unique_actions"`     // distinct action sequences
+	UniqueFraction    float64 `json:"unique_fraction"`    // distinct action sequences per agent
+}
+
+// DiversityMeasures lists the measures a collapse trigger can watch
+var DiversityMeasures = []string{"pairwise_distance", "weight_std", "behaviour_distance", "death_entropy", "unique_fraction"}
+
+// Measure returns the named measure, one of DiversityMeasures
+func (d Diversity) Measure(name string) (float64, error) {
+	switch name {
+	case "pairwise_distance":
+		return d.PairwiseDistance, nil
+	case "weight_std":
+		return d.WeightStd, nil
+	case "behaviour_distance":
+		return d.BehaviourDistance, nil
+	case "death_entropy":
+		return d.DeathEntropy, nil
+	case "unique_fraction":
+		return d.UniqueFraction, nil
+	}
+	return 0, fmt.Errorf("unknown diversity measure %q (want one of %s)", name, strings.Join(DiversityMeasures, ", "))
+}
+
+// MeasureDiversity computes genome-space, fitness and behavioural spread of
+// the agents
+func MeasureDiversity(agents []*Agent) Diversity {
+	var d Diversity
+	if len(agents) == 0 || len(agents[0].Genome) == 0 {
+		return d
+	}
+	n := float64(len(agents))
+	size := len(agents[0].Genome)
+
+	// Per-weight mean and variance
+	centroid := make([]float64, size)
+	for _, a := range agents {
+		for j, w := range a.Genome {
+			centroid[j] += float64(w)
+		}
+	}
+	for j := range centroid {
+		centroid[j] /= n
+	}
+	variance := make([]float64, size)
+	var fitnessMean float64
+	for _, a := range agents {
+		var dist float64
+		for j, w := range a.Genome {
+			diff := float64(w) - centroid[j]
+			variance[j] += diff * diff
+			dist += diff * diff
+		}
+		d.CentroidDistance += math.Sqrt(dist)
+		fitnessMean += a.Fitness
+	}
+	d.CentroidDistance /= n
+	fitnessMean /= n
+
+	for _, v := range variance {
+		d.WeightStd += math.Sqrt(v / n)
+		d.WeightVariance += v / n
+	}
+	d.WeightStd /= float64(size)
+	d.WeightVariance /= float64(size)
+
+	for _, a := range agents {
+		diff := a.Fitness - fitnessMean
+		d.FitnessStd += diff * diff
+	}
+	d.FitnessStd = math.Sqrt(d.FitnessStd / n)
+
+	d.PairwiseDistance = meanPairwise(len(agents), func(i, j int) float64 {
+		var sum float64
+		for k, w := range agents[i].Genome {
+			diff := float64(w) - float64(agents[j].Genome[k])
+			sum += diff * diff
+		}
+		return math.Sqrt(sum)
+	})
+	measureBehaviour(agents, &d)
+	return d
+}
+
+// measureBehaviour fills in the behavioural measures. Each agent is
+// described by its coverage and its ticks and fruits relative to the
+// population maximum, so the descriptor distance is 0 when every agent
+// behaves alike whatever the scale of the episode.
+func measureBehaviour(agents []*Agent, d *Diversity) {
+	var maxTicks, maxFruits int
+	deaths := make(map[int]int)
+	hashes := make(map[uint64]bool)
+	for _, a := range agents {
+		maxTicks = max(maxTicks, a.Stats.Ticks)
+		maxFruits = max(maxFruits, a.Stats.Fruits)
+		deaths[int(a.Stats.Death)]++
+		hashes[a.Stats.ActionHash] = true
+	}
+	d.UniqueActions = len(hashes)
+	d.UniqueFraction = float64(len(hashes)) / float64(len(agents))
+
+	for _, c := range deaths {
+		p := float64(c) / float64(len(agents))
+		d.DeathEntropy -= p * math.Log2(p)
+	}
+
+	ratio := func(v, m int) float64 {
+		if m == 0 {
+			return 0
+		}
+		return float64(v) / float64(m)
+	}
+	desc := make([][3]float64, len(agents))
+	for i, a := range agents {
+		desc[i] = [3]float64{a.Stats.Coverage, ratio(a.Stats.Ticks, maxTicks), ratio(a.Stats.Fruits, maxFruits)}
+	}
+	d.BehaviourDistance = meanPairwise(len(agents), func(i, j int) float64 {
+		var sum float64
+		for k := range desc[i] {
+			diff := desc[i][k] - desc[j][k]
+			sum += diff * diff
+		}
+		return math.Sqrt(sum)
+	})
+}
+
+// meanPairwise averages dist over all unordered pairs of n items
+func meanPairwise(n int, dist func(i, j int) float64) float64 {
+	if n < 2 {
+		return 0
+	}
+	var sum float64
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			sum += dist(i, j)
+		}
+	}
+	return sum / float64(n*(n-1)/2)
+}
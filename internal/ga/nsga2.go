@@ -0,0 +1,285 @@
+package ga
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+
+	"snakeai/internal/env"
+)
+
+// ObjectiveNames lists the episode measures usable as NSGA-II objectives.
+// All objectives are maximised.
+var ObjectiveNames = []string{
+	"fitness",    // scalar fitness score
+	"fruits",     // fruits eaten
+	"ticks",      // ticks survived
+	"progress",   // cumulative distance closed toward fruit
+	"efficiency", // fruits per 100 ticks
+	"length",     // final snake length
+	"coverage",   // fraction of cells visited
+}
+
+// ObjectiveValue extracts a named objective from an agent's episode
+func ObjectiveValue(name string, stats env.EpisodeStats) float64 {
+	switch name {
+	case "fitness":
+		return stats.Score
+	case "fruits":
+		return float64(stats.Fruits)
+	case "ticks":
+		return float64(stats.Ticks)
+	case "progress":
+		return stats.ProgressSum
+	case "efficiency":
+		if stats.Ticks == 0 {
+			return 0
+		}
+		return 100 * float64(stats.Fruits) / float64(stats.Ticks)
+	case "length":
+		return float64(stats.Length)
+	case "coverage":
+		return stats.Coverage
+	}
+	return 0
+}
+
+// SetObjectives fills each agent's objective vector from its episode stats
+func SetObjectives(agents []*Agent, names []string) {
+	for _, a := range agents {
+		if cap(a.Objectives) < len(names) {
+			a.Objectives = make([]float64, len(names))
+		}
+		a.Objectives = a.Objectives[:len(names)]
+		for i, name := range names {
+			a.Objectives[i] = ObjectiveValue(name, a.Stats)
+		}
+	}
+}
+
+// Dominates reports whether a is at least as good as b on every objective
+// and strictly better on one
+func Dominates(a, b *Agent) bool {
+	better := false
+	for i := range a.Objectives {
+		if a.Objectives[i] < b.Objectives[i] {
+			return false
+		}
+		if a.Objectives[i] > b.Objectives[i] {
+			better = true
+		}
+	}
+	return better
+}
+
+// NonDominatedSort partitions agents into Pareto fronts (front 0 is
+// non-dominated) and sets each agent's Rank
+func NonDominatedSort(agents []*Agent) [][]*Agent {
+	n := len(agents)
+	dominatedBy := make([]int, n) // number of agents dominating i
+	dominates := make([][]int, n) // agents i dominates
+
+	var fronts [][]*Agent
+	var current []int
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			switch {
+			case Dominates(agents[i], agents[j]):
+				dominates[i] = append(dominates[i], j)
+				dominatedBy[j]++
+			case Dominates(agents[j], agents[i]):
+				dominates[j] = append(dominates[j], i)
+				dominatedBy[i]++
+			}
+		}
+	}
+	for i := 0; i < n; i++ {
+		if dominatedBy[i] == 0 {
+			current = append(current, i)
+		}
+	}
+
+	for rank := 0; len(current) > 0; rank++ {
+		front := make([]*Agent, len(current))
+		var next []int
+		for k, i := range current {
+			agents[i].Rank = rank
+			front[k] = agents[i]
+			for _, j := range dominates[i] {
+				dominatedBy[j]--
+				if dominatedBy[j] == 0 {
+					next = append(next, j)
+				}
+			}
+		}
+		fronts = append(fronts, front)
+		current = next
+	}
+	return fronts
+}
+
+// AssignCrowdingDistance sets the crowding distance of each agent in a front.
+// Boundary agents on any objective get +Inf so extremes are kept.
+func AssignCrowdingDistance(front []*Agent) {
+	for _, a := range front {
+		a.Crowding = 0
+	}
+	if len(front) == 0 {
+		return
+	}
+
+	sorted := make([]*Agent, len(front))
+	copy(sorted, front)
+	for m := range front[0].Objectives {
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return sorted[i].Objectives[m] < sorted[j].Objectives[m]
+		})
+		lo := sorted[0].Objectives[m]
+		hi := sorted[len(sorted)-1].Objectives[m]
+		sorted[0].Crowding = math.Inf(1)
+		sorted[len(sorted)-1].Crowding = math.Inf(1)
+		if hi == lo {
+			continue
+		}
+		for i := 1; i < len(sorted)-1; i++ {
+			sorted[i].Crowding += (sorted[i+1].Objectives[m] - sorted[i-1].Objectives[m]) / (hi - lo)
+		}
+	}
+}
+
+// CrowdedLess reports whether a is preferred over b: lower rank first, then
+// larger crowding distance
+func CrowdedLess(a, b *Agent) bool {
+	if a.Rank != b.Rank {
+		return a.Rank < b.Rank
+	}
+	return a.Crowding > b.Crowding
+}
+
+// CrowdedTournamentSelect selects an agent by tournament on the crowded
+// comparison operator
+func CrowdedTournamentSelect(agents []*Agent, k int, rng *rand.Rand) *Agent {
+	if len(agents) == 0 {
+		return nil
+	}
+	if k > len(agents) {
+		k = len(agents)
+	}
+
+	best := agents[rng.Intn(len(agents))]
+	for i := 1; i < k; i++ {
+		candidate := agents[rng.Intn(len(agents))]
+		if CrowdedLess(candidate, best) {
+			best = candidate
+		}
+	}
+	return best
+}
+
+// EnvironmentalSelection keeps the best n agents of a combined parent and
+// offspring population: whole fronts in rank order, with the last front cut
+// by crowding distance. Ranks and crowding distances are left set.
+func EnvironmentalSelection(combined []*Agent, n int) []*Agent {
+	fronts := NonDominatedSort(combined)
+	selected := make([]*Agent, 0, n)
+	for _, front := range fronts {
+		AssignCrowdingDistance(front)
+		if len(selected)+len(front) <= n {
+			selected = append(selected, front...)
+			continue
+		}
+		sort.SliceStable(front, func(i, j int) bool {
+			return front[i].Crowding > front[j].Crowding
+		})
+		selected = append(selected, front[:n-len(selected)]...)
+		break
+	}
+	return selected
+}
+
+// ParetoFront returns the rank-0 agents, dropping duplicate objective vectors
+func ParetoFront(agents []*Agent) []*Agent {
+	var front []*Agent
+	for _, a := range NonDominatedSort(agents)[0] {
+		duplicate := false
+		for _, f := range front {
+			if equalObjectives(a.Objectives, f.Objectives) {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			front = append(front, a)
+		}
+	}
+	return front
+}
+
+func equalObjectives(a, b []float64) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Hypervolume returns the volume of objective space dominated by the agents
+// and bounded below by the reference point (all objectives maximised).
+// Agents that do not strictly dominate the reference point are ignored.
+func Hypervolume(agents []*Agent, ref []float64) float64 {
+	var points [][]float64
+	for _, a := range agents {
+		inside := true
+		for i, r := range ref {
+			if a.Objectives[i] <= r {
+				inside = false
+				break
+			}
+		}
+		if inside {
+			points = append(points, a.Objectives)
+		}
+	}
+	return hypervolume(points, ref)
+}
+
+// hypervolume computes the dominated volume by slicing along the last
+// objective and recursing on the projections (HSO)
+func hypervolume(points [][]float64, ref []float64) float64 {
+	if len(points) == 0 {
+		return 0
+	}
+	d := len(ref)
+	if d == 1 {
+		best := ref[0]
+		for _, p := range points {
+			best = math.Max(best, p[0])
+		}
+		return best - ref[0]
+	}
+
+	sorted := make([][]float64, len(points))
+	copy(sorted, points)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i][d-1] > sorted[j][d-1]
+	})
+
+	volume := 0.0
+	for i := range sorted {
+		next := ref[d-1]
+		if i+1 < len(sorted) {
+			next = sorted[i+1][d-1]
+		}
+		height := sorted[i][d-1] - next
+		if height <= 0 {
+			continue
+		}
+		projected := make([][]float64, i+1)
+		for k := 0; k <= i; k++ {
+			projected[k] = sorted[k][:d-1]
+		}
+		volume += height * hypervolume(projected, ref[:d-1])
+	}
+	return volume
+}
@@ -0,0 +1,141 @@
+package ga
+
+import (
+	"math"
+	"testing"
+)
+
+// objectiveAgents returns one agent per objective vector
+func objectiveAgents(points ...[]float64) []*Agent {
+	agents := make([]*Agent, len(points))
+	for i, p := range points {
+		agents[i] = &Agent{Objectives: p}
+	}
+	return agents
+}
+
+func TestNonDominatedSort(t *testing.T) {
+	// a, b and c trade off; d and f are each dominated by part of that
+	// front but not by each other; e is dominated by d and f
+	agents := objectiveAgents(
+		[]float64{3, 1}, // a
+		[]float64{2, 2}, // b
+		[]float64{1, 3}, // c
+		[]float64{2, 1}, // d
+		[]float64{1, 1}, // e
+		[]float64{1, 2}, // f
+	)
+	fronts := NonDominatedSort(agents)
+	if len(fronts) != 3 || len(fronts[0]) != 3 || len(fronts[1]) != 2 || len(fronts[2]) != 1 {
+		t.Fatalf("got front sizes %v, want 3, 2, 1", frontSizes(fronts))
+	}
+	for i, want := range []int{0, 0, 0, 1, 2, 1} {
+		if agents[i].Rank != want {
+			t.Errorf("agent %d rank %d, want %d", i, agents[i].Rank, want)
+		}
+	}
+
+	// In three dimensions a point on the diagonal is not dominated by the
+	// points either side of it
+	agents = objectiveAgents(
+		[]float64{1, 2, 3},
+		[]float64{3, 2, 1},
+		[]float64{2, 2, 2},
+		[]float64{1, 1, 1},
+		[]float64{2, 2, 2},
+	)
+	NonDominatedSort(agents)
+	for i, want := range []int{0, 0, 0, 1, 0} {
+		if agents[i].Rank != want {
+			t.Errorf("3-D agent %d rank %d, want %d", i, agents[i].Rank, want)
+		}
+	}
+}
+
+func frontSizes(fronts [][]*Agent) []int {
+	sizes := make([]int, len(fronts))
+	for i, f := range fronts {
+		sizes[i] = len(f)
+	}
+	return sizes
+}
+
+func TestAssignCrowdingDistance(t *testing.T) {
+	// Both objectives span 6: (1,5) has neighbours 2 apart on the first and
+	// 3 apart on the second, (2,3) has 5 and 5
+	front := objectiveAgents(
+		[]float64{0, 6},
+		[]float64{1, 5},
+		[]float64{2, 3},
+		[]float64{6, 0},
+	)
+	AssignCrowdingDistance(front)
+	want := []float64{math.Inf(1), 5.0 / 6, 10.0 / 6, math.Inf(1)}
+	for i, a := range front {
+		if math.Abs(a.Crowding-want[i]) > 1e-12 && !(math.IsInf(want[i], 1) && math.IsInf(a.Crowding, 1)) {
+			t.Errorf("agent %d crowding %v, want %v", i, a.Crowding, want[i])
+		}
+	}
+
+	// An objective on which the whole front ties adds nothing
+	front = objectiveAgents([]float64{0, 1}, []float64{1, 1}, []float64{3, 1})
+	AssignCrowdingDistance(front)
+	if front[1].Crowding != 1 {
+		t.Errorf("middle agent crowding %v with a tied objective, want 1", front[1].Crowding)
+	}
+}
+
+func TestEnvironmentalSelection(t *testing.T) {
+	agents := objectiveAgents(
+		[]float64{0, 6},
+		[]float64{1, 5},
+		[]float64{2, 3},
+		[]float64{6, 0},
+		[]float64{1, 1},
+	)
+	for _, c := range []struct {
+		n    int
+		want []int
+	}{
+		{5, []int{0, 1, 2, 3, 4}},
+		{4, []int{0, 1, 2, 3}},
+		{3, []int{0, 2, 3}}, // the last front is cut by crowding: (1,5) is most crowded
+		{2, []int{0, 3}},
+	} {
+		kept := make(map[*Agent]bool)
+		for _, a := range EnvironmentalSelection(agents, c.n) {
+			kept[a] = true
+		}
+		if len(kept) != len(c.want) {
+			t.Errorf("n=%d: kept %d agents, want %d", c.n, len(kept), len(c.want))
+		}
+		for _, i := range c.want {
+			if !kept[agents[i]] {
+				t.Errorf("n=%d: agent %d not kept", c.n, i)
+			}
+		}
+	}
+}
+
+func TestHypervolume(t *testing.T) {
+	cases := []struct {
+		name   string
+		points [][]float64
+		ref    []float64
+		want   float64
+	}{
+		{"2-D staircase", [][]float64{{3, 1}, {2, 2}, {1, 3}}, []float64{0, 0}, 6},
+		{"2-D dominated point adds nothing", [][]float64{{3, 1}, {2, 2}, {1, 3}, {1, 1}}, []float64{0, 0}, 6},
+		{"2-D points on the reference are ignored", [][]float64{{3, 1}, {2, 2}, {1, 3}}, []float64{1, 1}, 1},
+		{"3-D box", [][]float64{{2, 3, 4}}, []float64{0, 0, 0}, 24},
+		{"3-D two boxes", [][]float64{{2, 2, 2}, {1, 1, 4}}, []float64{0, 0, 0}, 10},
+		{"3-D three boxes", [][]float64{{3, 1, 1}, {1, 3, 1}, {1, 1, 3}}, []float64{0, 0, 0}, 7},
+		{"3-D shifted reference", [][]float64{{3, 1, 1}, {1, 3, 1}, {1, 1, 3}}, []float64{0.5, 0.5, 0.5}, 1.625},
+		{"empty", nil, []float64{0, 0}, 0},
+	}
+	for _, c := range cases {
+		if got := Hypervolume(objectiveAgents(c.points...), c.ref); math.Abs(got-c.want) > 1e-9 {
+			t.Errorf("%s: got %v, want %v", c.name, got, c.want)
+		}
+	}
+}
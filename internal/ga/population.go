@@ -15,6 +15,19 @@ type Agent struct {
 	Stats   env.EpisodeStats
 	AggStats env.AggregatedStats // for multi-seed evaluation
 	RobustScore float64 // mean - lambda*std
+	Evaluations int     // population evaluations averaged into Fitness, 0 before the first
+
+	// Multi-objective (NSGA-II) state
+	Objectives []float64 // maximised objective values
+	Rank       int       // Pareto front index (0 = non-dominated)
+	Crowding   float64   // crowding distance within the front
+
+	// Mutation state: the self-adaptive step size, carried by clones, and
+	// the lineage of fresh offspring for success-based step-size control
+	MutationSigma float64 // 0 until self-adaptation assigns one
+	ParentFitness float64     // fitness of the fitter parent
+	ParentGenomes [][]float32 // genomes of the parents until they are re-scored
+	Bred          bool        // created by crossover and mutation, not cloned
 }
 
 // Population manages the collection of agents
@@ -41,6 +54,29 @@ func NewPopulation(size, genomeSize int, rng *rand.Rand) *Population {
 	return p
 }
 
+// NewSeededPopulation creates a population around a seed genome, such as a
+// behaviour-cloned policy: one exact copy, the rest perturbed with Gaussian
+// noise of the given sigma on every weight
+func NewSeededPopulation(size int, seed []float32, sigma float64, rng *rand.Rand) *Population {
+	p := &Population{
+		Agents:     make([]*Agent, size),
+		GenomeSize: len(seed),
+		rng:        rng,
+	}
+
+	for i := 0; i < size; i++ {
+		genome := nn.CloneGenome(seed)
+		if i > 0 {
+			for j := range genome {
+				genome[j] += float32(rng.NormFloat64() * sigma)
+			}
+		}
+		p.Agents[i] = &Agent{Genome: genome}
+	}
+
+	return p
+}
+
 // Size returns the population size
 func (p *Population) Size() int {
 	return len(p.Agents)
@@ -105,6 +141,12 @@ func (a *Agent) Clone() *Agent {
 		Stats:       a.Stats,
 		AggStats:    a.AggStats,
 		RobustScore: a.RobustScore,
+		Evaluations: a.Evaluations,
+		Objectives:  append([]float64(nil), a.Objectives...),
+		Rank:        a.Rank,
+		Crowding:    a.Crowding,
+
+		MutationSigma: a.MutationSigma,
 	}
 }
 
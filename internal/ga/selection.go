@@ -1,7 +1,11 @@
 package ga
 
 import (
+	"fmt"
+	"math"
 	"math/rand"
+	"sort"
+	"strings"
 )
 
 // TournamentSelect selects an agent using tournament selection
@@ -44,3 +48,240 @@ func SelectParents(pool []*Agent, k int, rng *rand.Rand) (*Agent, *Agent) {
 	return p1, p2
 }
 
+// SelectionSchemes lists the parent selection schemes. nsga2 selects by
+// Pareto rank and crowding instead of fitness; mu_lambda and mu_plus_lambda
+// pick uniformly among the selection_pool best (the mu), taken from the
+// offspring alone or from the offspring and the previous mu.
+var SelectionSchemes = []string{"tournament", "roulette", "sus", "linear_rank", "exponential_rank", "boltzmann", "mu_lambda", "mu_plus_lambda", "nsga2"}
+
+// SelectionParams configures NewSelector
+type SelectionParams struct {
+	Scheme       string  // one of SelectionSchemes
+	TournamentK  int     // tournament and nsga2: contestants per tournament
+	RankPressure float64 // linear_rank: expected selections of the best agent per draw, in [1, 2]
+	RankBase     float64 // exponential_rank: weight ratio between consecutive ranks, in (0, 1)
+	Temperature  float64 // boltzmann: temperature in standard deviations of fitness
+}
+
+// Selector draws n parents from a mating pool sorted by descending fitness
+type Selector func(pool []*Agent, n int, rng *rand.Rand) []*Agent
+
+// NewSelector returns the named selection scheme
+func NewSelector(p SelectionParams) (Selector, error) {
+	switch p.Scheme {
+	case "tournament":
+		return func(pool []*Agent, n int, rng *rand.Rand) []*Agent {
+			return draw(n, func() *Agent { return TournamentSelect(pool, p.TournamentK, rng) })
+		}, nil
+	case "nsga2":
+		return func(pool []*Agent, n int, rng *rand.Rand) []*Agent {
+			return draw(n, func() *Agent { return CrowdedTournamentSelect(pool, p.TournamentK, rng) })
+		}, nil
+	case "roulette":
+		return func(pool []*Agent, n int, rng *rand.Rand) []*Agent {
+			return rouletteSample(pool, proportionalWeights(pool), n, rng)
+		}, nil
+	case "sus":
+		return func(pool []*Agent, n int, rng *rand.Rand) []*Agent {
+			return universalSample(pool, proportionalWeights(pool), n, rng)
+		}, nil
+	case "linear_rank":
+		if p.RankPressure < 1 || p.RankPressure > 2 {
+			return nil, fmt.Errorf("rank_pressure %g outside [1, 2]", p.RankPressure)
+		}
+		return func(pool []*Agent, n int, rng *rand.Rand) []*Agent {
+			return rouletteSample(pool, linearRankWeights(len(pool), p.RankPressure), n, rng)
+		}, nil
+	case "exponential_rank":
+		if p.RankBase <= 0 || p.RankBase >= 1 {
+			return nil, fmt.Errorf("rank_base %g outside (0, 1)", p.RankBase)
+		}
+		return func(pool []*Agent, n int, rng *rand.Rand) []*Agent {
+			return rouletteSample(pool, exponentialRankWeights(len(pool), p.RankBase), n, rng)
+		}, nil
+	case "boltzmann":
+		if p.Temperature <= 0 {
+			return nil, fmt.Errorf("boltzmann_temperature %g must be positive", p.Temperature)
+		}
+		return func(pool []*Agent, n int, rng *rand.Rand) []*Agent {
+			return rouletteSample(pool, boltzmannWeights(pool, p.Temperature), n, rng)
+		}, nil
+	case "mu_lambda", "mu_plus_lambda":
+		return func(pool []*Agent, n int, rng *rand.Rand) []*Agent {
+			return draw(n, func() *Agent { return pool[rng.Intn(len(pool))] })
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown selection %q (want one of %s)", p.Scheme, strings.Join(SelectionSchemes, ", "))
+}
+
+// draw collects n independent picks
+func draw(n int, pick func() *Agent) []*Agent {
+	out := make([]*Agent, n)
+	for i := range out {
+		out[i] = pick()
+	}
+	return out
+}
+
+// proportionalWeights weights agents by fitness above the worst in the
+// pool, since fitness may be negative; a pool of equals is uniform
+func proportionalWeights(pool []*Agent) []float64 {
+	worst := math.Inf(1)
+	for _, a := range pool {
+		worst = math.Min(worst, a.Fitness)
+	}
+	w := make([]float64, len(pool))
+	for i, a := range pool {
+		w[i] = a.Fitness - worst
+	}
+	return w
+}
+
+// linearRankWeights gives the best of n agents weight s and the worst 2-s,
+// falling linearly with rank (Baker)
+func linearRankWeights(n int, s float64) []float64 {
+	w := make([]float64, n)
+	for i := range w {
+		w[i] = s
+		if n > 1 {
+			w[i] -= (2*s - 2) * float64(i) / float64(n-1)
+		}
+	}
+	return w
+}
+
+// exponentialRankWeights gives rank i (0 = best) weight c^i
+func exponentialRankWeights(n int, c float64) []float64 {
+	w := make([]float64, n)
+	for i := range w {
+		w[i] = math.Pow(c, float64(i))
+	}
+	return w
+}
+
+// boltzmannWeights weights agents by exp(z/T) for their fitness z in
+// standard deviations from the pool mean, so the temperature does not
+// depend on the fitness scale
+func boltzmannWeights(pool []*Agent, temperature float64) []float64 {
+	var mean, std float64
+	for _, a := range pool {
+		mean += a.Fitness
+	}
+	mean /= float64(len(pool))
+	for _, a := range pool {
+		std += (a.Fitness - mean) * (a.Fitness - mean)
+	}
+	std = math.Sqrt(std / float64(len(pool)))
+	if std == 0 {
+		std = 1
+	}
+	w := make([]float64, len(pool))
+	for i, a := range pool {
+		w[i] = math.Exp((a.Fitness - mean) / std / temperature)
+	}
+	return w
+}
+
+// cumulative returns running sums of weights, made uniform when they are
+// all zero
+func cumulative(weights []float64) []float64 {
+	cum := make([]float64, len(weights))
+	var total float64
+	for i, w := range weights {
+		total += w
+		cum[i] = total
+	}
+	if total == 0 {
+		for i := range cum {
+			cum[i] = float64(i + 1)
+		}
+	}
+	return cum
+}
+
+// rouletteSample draws n agents independently with probability
+// proportional to weight
+func rouletteSample(pool []*Agent, weights []float64, n int, rng *rand.Rand) []*Agent {
+	cum := cumulative(weights)
+	total := cum[len(cum)-1]
+	return draw(n, func() *Agent {
+		x := rng.Float64() * total
+		i := sort.Search(len(cum), func(i int) bool { return cum[i] > x })
+		return pool[min(i, len(pool)-1)]
+	})
+}
+
+// universalSample draws n agents with one spin of a wheel carrying n evenly
+// spaced pointers (Baker's stochastic universal sampling): every agent is
+// picked within one of its expected count. The picks are shuffled so
+// consecutive parents are not neighbours in rank.
+func universalSample(pool []*Agent, weights []float64, n int, rng *rand.Rand) []*Agent {
+	cum := cumulative(weights)
+	step := cum[len(cum)-1] / float64(n)
+	offset := rng.Float64() * step
+	out := make([]*Agent, n)
+	i := 0
+	for k := range out {
+		pointer := offset + float64(k)*step
+		for i < len(cum)-1 && cum[i] <= pointer {
+			i++
+		}
+		out[k] = pool[i]
+	}
+	rng.Shuffle(n, func(a, b int) { out[a], out[b] = out[b], out[a] })
+	return out
+}
+
+// SelectionPressure summarises how strongly a selector favours fit agents
+type SelectionPressure struct {
+	Intensity       float64 // mean fitness of the selected minus the pool mean, in pool standard deviations
+	LossOfDiversity float64 // fraction of the pool never selected
+	BestCopies      float64 // selections of the best agent
+	Distinct        float64 // agents selected at least once
+}
+
+// MeasureSelectionPressure draws len(agents) parents from the poolSize best
+// of agents, sorted by descending fitness, rounds times and averages the
+// pressure relative to all of agents, so truncation to the pool counts
+func MeasureSelectionPressure(sel Selector, agents []*Agent, poolSize, rounds int, rng *rand.Rand) SelectionPressure {
+	pool := agents[:min(poolSize, len(agents))]
+	var out SelectionPressure
+	for r := 0; r < rounds; r++ {
+		p := selectionPressure(agents, sel(pool, len(agents), rng))
+		out.Intensity += p.Intensity / float64(rounds)
+		out.LossOfDiversity += p.LossOfDiversity / float64(rounds)
+		out.BestCopies += p.BestCopies / float64(rounds)
+		out.Distinct += p.Distinct / float64(rounds)
+	}
+	return out
+}
+
+// selectionPressure measures one draw of parents against the agents they
+// were drawn from, best first
+func selectionPressure(pool, selected []*Agent) SelectionPressure {
+	var p SelectionPressure
+	var mean, std, chosen float64
+	for _, a := range pool {
+		mean += a.Fitness
+	}
+	mean /= float64(len(pool))
+	for _, a := range pool {
+		std += (a.Fitness - mean) * (a.Fitness - mean)
+	}
+	std = math.Sqrt(std / float64(len(pool)))
+
+	seen := make(map[*Agent]bool)
+	for _, a := range selected {
+		chosen += a.Fitness
+		seen[a] = true
+		if a == pool[0] {
+			p.BestCopies++
+		}
+	}
+	if std > 0 {
+		p.Intensity = (chosen/float64(len(selected)) - mean) / std
+	}
+	p.Distinct = float64(len(seen))
+	p.LossOfDiversity = 1 - float64(len(seen))/float64(len(pool))
+	return p
+}
@@ -0,0 +1,116 @@
+package ga
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// normalPopulation returns n agents with normally distributed fitness,
+// sorted by descending fitness
+func normalPopulation(n int, rng *rand.Rand) []*Agent {
+	pop := &Population{Agents: make([]*Agent, n)}
+	for i := range pop.Agents {
+		pop.Agents[i] = &Agent{Fitness: 100 + 10*rng.NormFloat64()}
+	}
+	pop.SortByFitness()
+	return pop.Agents
+}
+
+func mustSelector(t *testing.T, p SelectionParams) Selector {
+	t.Helper()
+	sel, err := NewSelector(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sel
+}
+
+func TestTournamentPressureRisesWithK(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	agents := normalPopulation(200, rng)
+	var last float64
+	for _, k := range []int{2, 3, 5, 7} {
+		sel := mustSelector(t, SelectionParams{Scheme: "tournament", TournamentK: k})
+		p := MeasureSelectionPressure(sel, agents, len(agents), 200, rng)
+		if k > 2 && p.Intensity <= last {
+			t.Errorf("tournament k=%d intensity %.3f not above %.3f for smaller k", k, p.Intensity, last)
+		}
+		last = p.Intensity
+	}
+}
+
+func TestBoltzmannPressureRisesAsTemperatureFalls(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	agents := normalPopulation(200, rng)
+	last := math.Inf(-1)
+	for _, temp := range []float64{4, 2, 1, 0.5} {
+		sel := mustSelector(t, SelectionParams{Scheme: "boltzmann", Temperature: temp})
+		p := MeasureSelectionPressure(sel, agents, len(agents), 200, rng)
+		if p.Intensity <= last {
+			t.Errorf("boltzmann T=%g intensity %.3f not above %.3f at higher T", temp, p.Intensity, last)
+		}
+		last = p.Intensity
+	}
+}
+
+func TestLinearRankBestCopies(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	agents := normalPopulation(100, rng)
+	for _, s := range []float64{1, 1.5, 2} {
+		sel := mustSelector(t, SelectionParams{Scheme: "linear_rank", RankPressure: s})
+		p := MeasureSelectionPressure(sel, agents, len(agents), 2000, rng)
+		if math.Abs(p.BestCopies-s) > 0.1 {
+			t.Errorf("linear_rank s=%g: best agent selected %.3f times per generation, want about %g", s, p.BestCopies, s)
+		}
+	}
+}
+
+func TestSUSHasNoSpread(t *testing.T) {
+	rng := rand.New(rand.NewSource(4))
+	agents := normalPopulation(50, rng)
+	sel := mustSelector(t, SelectionParams{Scheme: "sus"})
+	w := proportionalWeights(agents)
+	var total float64
+	for _, x := range w {
+		total += x
+	}
+	for round := 0; round < 100; round++ {
+		counts := make(map[*Agent]int)
+		for _, a := range sel(agents, len(agents), rng) {
+			counts[a]++
+		}
+		for i, a := range agents {
+			want := float64(len(agents)) * w[i] / total
+			if got := float64(counts[a]); got < math.Floor(want) || got > math.Ceil(want) {
+				t.Fatalf("round %d: agent %d selected %v times, want %.3f rounded either way", round, i, got, want)
+			}
+		}
+	}
+}
+
+func TestRouletteIsNotSUS(t *testing.T) {
+	// Roulette draws independently, so some agent strays from its expected
+	// count by a whole selection or more in some round
+	rng := rand.New(rand.NewSource(5))
+	agents := normalPopulation(50, rng)
+	sel := mustSelector(t, SelectionParams{Scheme: "roulette"})
+	w := proportionalWeights(agents)
+	var total float64
+	for _, x := range w {
+		total += x
+	}
+	for round := 0; round < 100; round++ {
+		counts := make(map[*Agent]int)
+		for _, a := range sel(agents, len(agents), rng) {
+			counts[a]++
+		}
+		for i, a := range agents {
+			want := float64(len(agents)) * w[i] / total
+			if got := float64(counts[a]); got < math.Floor(want) || got > math.Ceil(want) {
+				return
+			}
+		}
+	}
+	t.Error("roulette never strayed from its expected counts, like SUS")
+}
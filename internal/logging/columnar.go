@@ -0,0 +1,255 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+
+	"snakeai/internal/env"
+)
+
+// The columnar log is a small self-describing file for analysis tools:
+//
+//	magic   "SNKCOL1\n"
+//	blocks  any number of:
+//	  uint32  header length (little-endian)
+//	  header  JSON {"rows": n, "columns": [names...]}
+//	  data    n float64 values (little-endian) per column, in header order
+//
+// Storing each column contiguously lets a reader load a single curve
+// without parsing every row, like a stripped-down Parquet file. During a
+// run every generation is appended as a block of its own, so writing stays
+// cheap however long the run; Close compacts the blocks into one. Blocks
+// stack their rows, and values a block does not have are NaN.
+const columnarMagic = "SNKCOL1\n"
+
+// scalar is one named value of a summary, shared by the columnar and
+// TensorBoard sinks
+type scalar struct {
+	name  string
+	value float64
+}
+
+// scalars flattens a summary into named values. Optional results are only
+// included when present; map entries are sorted by key. Step counts and
+// times of zero mean they were not measured.
+func scalars(s GenerationSummary) []scalar {
+	out := []scalar{
+		{"fitness/best", s.BestFitness},
+		{"fitness/mean", s.MeanFitness},
+		{"ticks/best", float64(s.BestTicks)},
+		{"ticks/mean", s.MeanTicks},
+		{"fruits/best", float64(s.BestFruits)},
+		{"fruits/mean", s.MeanFruits},
+	}
+	for _, reason := range []env.DeathReason{env.DeathWall, env.DeathSelf, env.DeathStall, env.DeathTimeout} {
+		out = append(out, scalar{"deaths/" + reason.String(), float64(s.DeathCounts[reason.String()])})
+	}
+	add := func(name string, v float64) {
+		if v != 0 {
+			out = append(out, scalar{name, v})
+		}
+	}
+	add("env_steps", float64(s.EnvSteps))
+	for _, v := range []struct {
+		name  string
+		value *float64
+	}{{"robust_score", s.RobustScore}, {"benchmark/ticks", s.BenchmarkTicks}, {"benchmark/fruits", s.BenchmarkFruits}} {
+		if v.value != nil {
+			out = append(out, scalar{v.name, *v.value})
+		}
+	}
+	if g := s.Generalisation; g != nil {
+		out = append(out,
+			scalar{"generalisation/fixed_ticks", g.FixedTicks},
+			scalar{"generalisation/fixed_fruits", g.FixedFruits},
+			scalar{"generalisation/random_ticks", g.RandomTicks},
+			scalar{"generalisation/random_fruits", g.RandomFruits})
+	}
+	if d := s.Diversity; d != nil {
+		out = append(out,
+			scalar{"diversity/centroid_distance", d.CentroidDistance},
+			scalar{"diversity/pairwise_distance", d.PairwiseDistance},
+			scalar{"diversity/weight_std", d.WeightStd},
+			scalar{"diversity/weight_variance", d.WeightVariance},
+			scalar{"diversity/fitness_std", d.FitnessStd},
+			scalar{"diversity/behaviour_distance", d.BehaviourDistance},
+			scalar{"diversity/death_entropy", d.DeathEntropy},
+			scalar{"diversity/unique_actions", float64(d.UniqueActions)},
+			scalar{"diversity/unique_fraction", d.UniqueFraction})
+	}
+	if m := s.Mutation; m != nil {
+		out = append(out,
+			scalar{"mutation/rate", m.Rate},
+			scalar{"mutation/sigma", m.Sigma},
+			scalar{"mutation/boost", m.Boost},
+			scalar{"mutation/success_rate", m.SuccessRate})
+	}
+	if s.ParetoSize > 0 {
+		out = append(out, scalar{"pareto/size", float64(s.ParetoSize)}, scalar{"pareto/hypervolume", s.Hypervolume})
+	}
+	for _, name := range sortedKeys(s.MeanTerms) {
+		out = append(out, scalar{"terms/" + name, s.MeanTerms[name]})
+	}
+	for _, name := range sortedKeys(s.Diagnostics) {
+		out = append(out, scalar{"diagnostics/" + name, s.Diagnostics[name]})
+	}
+	for _, name := range sortedKeys(s.PhaseSeconds) {
+		out = append(out, scalar{"time/" + name, s.PhaseSeconds[name]})
+	}
+	add("time/total", s.Seconds)
+	return out
+}
+
+// ColumnarSink appends a one-row block per summary and compacts the file
+// into a single block on Close
+type ColumnarSink struct {
+	path string
+	file *os.File
+}
+
+// NewColumnarSink creates a columnar log at path, with its directory
+func NewColumnarSink(path string) (*ColumnarSink, error) {
+	f, err := create(path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := f.WriteString(columnarMagic); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &ColumnarSink{path: path, file: f}, nil
+}
+
+func (c *ColumnarSink) WriteSummary(s GenerationSummary) error {
+	t := &Table{
+		Rows:    1,
+		Columns: []string{"generation"},
+		Data:    map[string][]float64{"generation": {float64(s.Generation)}},
+	}
+	for _, v := range scalars(s) {
+		t.Columns = append(t.Columns, v.name)
+		t.Data[v.name] = []float64{v.value}
+	}
+	if err := writeBlock(c.file, t); err != nil {
+		return fmt.Errorf("columnar log: %w", err)
+	}
+	return nil
+}
+
+// Close compacts the log into a single block, written to a temporary file
+// and renamed over path so readers never see a partial file
+func (c *ColumnarSink) Close() error {
+	if err := c.file.Close(); err != nil {
+		return err
+	}
+	t, err := ReadColumnar(c.path)
+	if err != nil {
+		return fmt.Errorf("columnar log: %w", err)
+	}
+	tmp := c.path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	_, werr := f.WriteString(columnarMagic)
+	if werr == nil {
+		werr = writeBlock(f, t)
+	}
+	if err := f.Close(); werr == nil {
+		werr = err
+	}
+	if werr != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("columnar log: %w", werr)
+	}
+	return os.Rename(tmp, c.path)
+}
+
+// Table is a columnar log loaded into memory
+type Table struct {
+	Rows    int
+	Columns []string             // in file order
+	Data    map[string][]float64 // Rows values per column, NaN where absent
+}
+
+type columnarHeader struct {
+	Rows    int      `json:"rows"`
+	Columns []string `json:"columns"`
+}
+
+// writeBlock writes t as one block with a single Write, so a reader never
+// sees half of a block that was written completely
+func writeBlock(w io.Writer, t *Table) error {
+	header, err := json.Marshal(columnarHeader{Rows: t.Rows, Columns: t.Columns})
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, uint32(len(header)))
+	buf.Write(header)
+	for _, name := range t.Columns {
+		binary.Write(&buf, binary.LittleEndian, t.Data[name])
+	}
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// ReadColumnar loads a columnar log written by ColumnarSink, stacking its
+// blocks. An incomplete last block, such as one still being written by a
+// running sink, is ignored.
+func ReadColumnar(path string) (*Table, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < len(columnarMagic) || string(data[:len(columnarMagic)]) != columnarMagic {
+		return nil, fmt.Errorf("%s: not a columnar log", path)
+	}
+	data = data[len(columnarMagic):]
+
+	t := &Table{Data: make(map[string][]float64)}
+	for len(data) >= 4 {
+		n := binary.LittleEndian.Uint32(data)
+		if uint64(n) > uint64(len(data)-4) {
+			break
+		}
+		var h columnarHeader
+		if err := json.Unmarshal(data[4:4+n], &h); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		size := 8 * h.Rows * len(h.Columns)
+		if h.Rows < 0 || size > len(data)-4-int(n) {
+			break
+		}
+		block := data[4+int(n) : 4+int(n)+size]
+		data = data[4+int(n)+size:]
+
+		for _, name := range h.Columns {
+			if _, ok := t.Data[name]; !ok {
+				// A new column is NaN in every earlier row
+				col := make([]float64, t.Rows)
+				for i := range col {
+					col[i] = math.NaN()
+				}
+				t.Data[name] = col
+				t.Columns = append(t.Columns, name)
+			}
+			for i := 0; i < h.Rows; i++ {
+				t.Data[name] = append(t.Data[name], math.Float64frombits(binary.LittleEndian.Uint64(block)))
+				block = block[8:]
+			}
+		}
+		t.Rows += h.Rows
+		for _, name := range t.Columns {
+			for len(t.Data[name]) < t.Rows {
+				t.Data[name] = append(t.Data[name], math.NaN())
+			}
+		}
+	}
+	return t, nil
+}
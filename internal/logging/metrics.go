@@ -1,193 +1,340 @@
 package logging
 
 import (
-	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
-	"strconv"
+	"sort"
+	"strings"
+	"time"
 
+	"snakeai/internal/config"
 	"snakeai/internal/env"
 	"snakeai/internal/ga"
 )
 
-// Logger handles all training output and artifact saving
+// Logger turns training progress into generation summaries and hands each
+// one to its sinks. Results that arrive during a generation (robust score,
+// benchmarks, optimiser diagnostics, phase timings) are staged and included
+// in the next summary, so every sink sees the same complete record.
 type Logger struct {
-	csvPath     string
-	jsonPath    string
-	csvFile     *os.File
-	csvWriter   *csv.Writer
-	jsonFile    *os.File
-	initialized bool
+	sinks    []Sink
+	console  *ConsoleSink      // nil if no console sink is attached
+	hvRef    []float64         // hypervolume reference point, nil if not tracked
+	envSteps func() int64      // cumulative environment step counter, nil if not tracked
+	next     GenerationSummary // staged fields of the pending summary
+	last     time.Time         // when the previous summary was written
 }
 
-// NewLogger creates a new logger
-func NewLogger(csvPath, jsonPath string) (*Logger, error) {
-	l := &Logger{
-		csvPath:  csvPath,
-		jsonPath: jsonPath,
+// NewLogger creates a logger writing to sinks
+func NewLogger(sinks ...Sink) *Logger {
+	l := &Logger{last: time.Now()}
+	for _, s := range sinks {
+		l.AddSink(s)
 	}
+	return l
+}
 
-	// Ensure directories exist
-	if err := os.MkdirAll(filepath.Dir(csvPath), 0755); err != nil {
+// Open creates a logger with the sinks and verbosity named in cfg
+func Open(cfg config.LogConfig) (*Logger, error) {
+	verbosity, err := ParseVerbosity(cfg.Verbosity)
+	if err != nil {
 		return nil, err
 	}
-	if err := os.MkdirAll(filepath.Dir(jsonPath), 0755); err != nil {
-		return nil, err
+	l := NewLogger()
+	for _, name := range cfg.Sinks {
+		var sink Sink
+		switch name {
+		case "console":
+			sink = NewConsoleSink(os.Stdout, verbosity)
+		case "csv":
+			sink, err = NewCSVSink(cfg.CSVPath)
+		case "jsonl":
+			sink, err = NewJSONLSink(cfg.JSONPath)
+		case "columnar":
+			sink, err = NewColumnarSink(cfg.ColumnarPath)
+		case "tensorboard":
+			sink, err = NewTensorBoardSink(cfg.TensorBoardDir)
+		default:
+			err = fmt.Errorf("unknown sink %q (want console, csv, jsonl, columnar or tensorboard)", name)
+		}
+		if err != nil {
+			l.Close()
+			return nil, fmt.Errorf("logging: %w", err)
+		}
+		l.AddSink(sink)
 	}
-
 	return l, nil
 }
 
-// Init initializes the log files
-func (l *Logger) Init() error {
-	var err error
+// ConfigSnapshotPath is where a run's configuration is saved beside its
+// JSONL log: run.jsonl gets run.config.yaml
+func ConfigSnapshotPath(jsonPath string) string {
+	return strings.TrimSuffix(jsonPath, filepath.Ext(jsonPath)) + ".config.yaml"
+}
 
-	// Open CSV file
-	l.csvFile, err = os.Create(l.csvPath)
-	if err != nil {
-		return err
+// AddSink attaches a sink that receives every subsequent summary
+func (l *Logger) AddSink(s Sink) {
+	if c, ok := s.(*ConsoleSink); ok && l.console == nil {
+		l.console = c
 	}
-	l.csvWriter = csv.NewWriter(l.csvFile)
+	l.sinks = append(l.sinks, s)
+}
 
-	// Write CSV header
-	header := []string{
-		"generation", "best_fitness", "mean_fitness", "best_ticks", "mean_ticks",
-		"best_fruits", "mean_fruits", "deaths_wall", "deaths_self", "deaths_stall", "deaths_timeout",
+// AddListener registers fn to receive every summary after it is written,
+// for live consumers such as the training dashboard
+func (l *Logger) AddListener(fn func(GenerationSummary)) {
+	l.AddSink(SinkFunc(fn))
+}
+
+// Close flushes and closes all sinks
+func (l *Logger) Close() error {
+	var errs []error
+	for _, s := range l.sinks {
+		errs = append(errs, s.Close())
 	}
-	if err := l.csvWriter.Write(header); err != nil {
-		return err
+	return errors.Join(errs...)
+}
+
+// GenerationSummary holds per-generation statistics. Fields after the death
+// counts are only set when the matching result was logged that generation;
+// the optional scores are pointers so that a logged zero is kept.
+type GenerationSummary struct {
+	Generation      int                `json:"generation"`
+	BestFitness     float64            `json:"best_fitness"`
+	MeanFitness     float64            `json:"mean_fitness"`
+	BestTicks       int                `json:"best_ticks"`
+	MeanTicks       float64            `json:"mean_ticks"`
+	BestFruits      int                `json:"best_fruits"`
+	MeanFruits      float64            `json:"mean_fruits"`
+	DeathCounts     map[string]int     `json:"death_counts"`
+	BestTerms       map[string]float64 `json:"best_terms,omitempty"`
+	MeanTerms       map[string]float64 `json:"mean_terms,omitempty"`
+	RobustScore     *float64           `json:"robust_score,omitempty"`
+	BenchmarkTicks  *float64           `json:"benchmark_ticks,omitempty"`
+	BenchmarkFruits *float64           `json:"benchmark_fruits,omitempty"`
+	Generalisation  *Generalisation    `json:"generalisation,omitempty"`
+	Hypervolume     float64            `json:"hypervolume,omitempty"`
+	ParetoSize      int                `json:"pareto_size,omitempty"`
+	EnvSteps        int64              `json:"env_steps,omitempty"`
+	Diagnostics     map[string]float64 `json:"diagnostics,omitempty"`
+	Diversity       *ga.Diversity      `json:"diversity,omitempty"`
+	Interventions   []string           `json:"interventions,omitempty"`
+	Mutation        *ga.Mutation       `json:"mutation,omitempty"`
+	PhaseSeconds    map[string]float64 `json:"phase_seconds,omitempty"`
+	Seconds         float64            `json:"seconds,omitempty"`
+}
+
+// Generalisation compares benchmark results from the fixed start against
+// the randomised start distribution
+type Generalisation struct {
+	FixedTicks   float64 `json:"fixed_ticks"`
+	FixedFruits  float64 `json:"fixed_fruits"`
+	RandomTicks  float64 `json:"random_ticks"`
+	RandomFruits float64 `json:"random_fruits"`
+}
+
+// TrackHypervolume makes LogGeneration report the hypervolume of the
+// population's Pareto front against ref. Agents must carry objectives.
+func (l *Logger) TrackHypervolume(ref []float64) {
+	l.hvRef = ref
+}
+
+// TrackEnvSteps makes generation summaries report the cumulative number of
+// environment steps returned by steps, for sample-efficiency comparisons
+func (l *Logger) TrackEnvSteps(steps func() int64) {
+	l.envSteps = steps
+}
+
+// Time starts timing a phase; call the returned function when the phase
+// ends. The time is reported in the next summary, and repeated phases
+// accumulate.
+func (l *Logger) Time(phase string) func() {
+	start := time.Now()
+	return func() {
+		if l.next.PhaseSeconds == nil {
+			l.next.PhaseSeconds = make(map[string]float64)
+		}
+		l.next.PhaseSeconds[phase] += time.Since(start).Seconds()
 	}
+}
 
-	// Open JSON file
-	l.jsonFile, err = os.OpenFile(l.jsonPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
-	if err != nil {
-		return err
+// LogRobustScore stages the robust score of the generation's most robust
+// candidate
+func (l *Logger) LogRobustScore(score float64) {
+	l.next.RobustScore = &score
+}
+
+// LogDiagnostic stages a named optimiser diagnostic, such as a loss
+func (l *Logger) LogDiagnostic(name string, value float64) {
+	if l.next.Diagnostics == nil {
+		l.next.Diagnostics = make(map[string]float64)
 	}
+	l.next.Diagnostics[name] = value
+}
+
+// LogDiversity stages the population's diversity measures
+func (l *Logger) LogDiversity(d ga.Diversity) {
+	l.next.Diversity = &d
+}
+
+// LogIntervention stages a note that training changed course this
+// generation, such as a reset triggered by diversity collapse
+func (l *Logger) LogIntervention(msg string) {
+	l.next.Interventions = append(l.next.Interventions, msg)
+}
 
-	l.initialized = true
-	return nil
+// LogMutation stages the mutation rate and step size used to breed the
+// next generation
+func (l *Logger) LogMutation(m ga.Mutation) {
+	l.next.Mutation = &m
 }
 
-// Close closes all log files
-func (l *Logger) Close() {
-	if l.csvWriter != nil {
-		l.csvWriter.Flush()
+// LogBenchmark stages benchmark results averaged across agents
+func (l *Logger) LogBenchmark(results []env.AggregatedStats) {
+	if len(results) == 0 {
+		return
+	}
+	ticks, fruits := benchmarkMeans(results)
+	l.next.BenchmarkTicks, l.next.BenchmarkFruits = &ticks, &fruits
+}
+
+// LogGeneralisation stages benchmark results from the fixed start against
+// the randomised start distribution
+func (l *Logger) LogGeneralisation(fixed, randomized []env.AggregatedStats) {
+	if len(fixed) == 0 || len(randomized) == 0 {
+		return
 	}
-	if l.csvFile != nil {
-		l.csvFile.Close()
+	g := &Generalisation{}
+	g.FixedTicks, g.FixedFruits = benchmarkMeans(fixed)
+	g.RandomTicks, g.RandomFruits = benchmarkMeans(randomized)
+	l.next.Generalisation = g
+}
+
+// LogGeneration writes the summary of an evaluated population with the
+// results staged since the previous summary
+func (l *Logger) LogGeneration(gen int, pop *ga.Population) error {
+	episodes := make([]env.EpisodeStats, len(pop.Agents))
+	for i, a := range pop.Agents {
+		episodes[i] = a.Stats
 	}
-	if l.jsonFile != nil {
-		l.jsonFile.Close()
+	summary := l.summarize(gen, episodes)
+
+	if l.hvRef != nil {
+		front := ga.ParetoFront(pop.Agents)
+		summary.Hypervolume = ga.Hypervolume(front, l.hvRef)
+		summary.ParetoSize = len(front)
 	}
+
+	return l.write(summary)
 }
 
-// GenerationSummary holds per-generation statistics
-type GenerationSummary struct {
-	Generation    int                    `json:"generation"`
-	BestFitness   float64                `json:"best_fitness"`
-	MeanFitness   float64                `json:"mean_fitness"`
-	BestTicks     int                    `json:"best_ticks"`
-	MeanTicks     float64                `json:"mean_ticks"`
-	BestFruits    int                    `json:"best_fruits"`
-	MeanFruits    float64                `json:"mean_fruits"`
-	DeathCounts   map[string]int         `json:"death_counts"`
-	RobustScore   float64                `json:"robust_score,omitempty"`
-	BenchmarkTicks float64               `json:"benchmark_ticks,omitempty"`
-}
-
-// LogGeneration logs a generation summary
-func (l *Logger) LogGeneration(gen int, pop *ga.Population) {
-	if !l.initialized {
-		return
+// LogEpisodes writes a summary of a batch of scored episodes, such as the
+// rollouts of one policy-gradient iteration, in the generation format
+func (l *Logger) LogEpisodes(iter int, episodes []env.EpisodeStats) error {
+	if len(episodes) == 0 {
+		return nil
 	}
+	return l.write(l.summarize(iter, episodes))
+}
 
-	// Compute statistics
+// summarize computes the generation statistics of a set of episodes and
+// takes over the staged results
+func (l *Logger) summarize(gen int, episodes []env.EpisodeStats) GenerationSummary {
 	var sumFitness, sumTicks, sumFruits float64
-	deathCounts := make(map[env.DeathReason]int)
-	best := pop.Best()
-
-	for _, a := range pop.Agents {
-		sumFitness += a.Fitness
-		sumTicks += float64(a.Stats.Ticks)
-		sumFruits += float64(a.Stats.Fruits)
-		deathCounts[a.Stats.Death]++
-	}
-
-	n := float64(len(pop.Agents))
-	summary := GenerationSummary{
-		Generation:  gen,
-		BestFitness: best.Fitness,
-		MeanFitness: sumFitness / n,
-		BestTicks:   best.Stats.Ticks,
-		MeanTicks:   sumTicks / n,
-		BestFruits:  best.Stats.Fruits,
-		MeanFruits:  sumFruits / n,
-		DeathCounts: make(map[string]int),
-	}
-
-	for reason, count := range deathCounts {
-		summary.DeathCounts[reason.String()] = count
-	}
-
-	// Write CSV row
-	row := []string{
-		strconv.Itoa(gen),
-		fmt.Sprintf("%.2f", summary.BestFitness),
-		fmt.Sprintf("%.2f", summary.MeanFitness),
-		strconv.Itoa(summary.BestTicks),
-		fmt.Sprintf("%.2f", summary.MeanTicks),
-		strconv.Itoa(summary.BestFruits),
-		fmt.Sprintf("%.2f", summary.MeanFruits),
-		strconv.Itoa(deathCounts[env.DeathWall]),
-		strconv.Itoa(deathCounts[env.DeathSelf]),
-		strconv.Itoa(deathCounts[env.DeathStall]),
-		strconv.Itoa(deathCounts[env.DeathTimeout]),
-	}
-	l.csvWriter.Write(row)
-	l.csvWriter.Flush()
-
-	// Write JSON line
-	jsonLine, _ := json.Marshal(summary)
-	l.jsonFile.WriteString(string(jsonLine) + "\n")
-
-	// Print to console
-	fmt.Printf("Gen %4d | Best: %8.1f | Mean: %8.1f | Ticks: %4d | Fruits: %d | Deaths: W=%d S=%d St=%d T=%d\n",
-		gen, summary.BestFitness, summary.MeanFitness, summary.BestTicks, summary.BestFruits,
-		deathCounts[env.DeathWall], deathCounts[env.DeathSelf],
-		deathCounts[env.DeathStall], deathCounts[env.DeathTimeout])
-}
-
-// LogBenchmark logs benchmark results
-func (l *Logger) LogBenchmark(gen int, results []env.AggregatedStats) {
-	if len(results) == 0 {
-		return
+	best := episodes[0]
+	summary := l.next
+	l.next = GenerationSummary{}
+	summary.Generation = gen
+	summary.DeathCounts = make(map[string]int)
+	summary.MeanTerms = env.MeanTerms(episodes)
+
+	for _, ep := range episodes {
+		sumFitness += ep.Score
+		sumTicks += float64(ep.Ticks)
+		sumFruits += float64(ep.Fruits)
+		summary.DeathCounts[ep.Death.String()]++
+		if ep.Score > best.Score {
+			best = ep
+		}
 	}
 
-	// Average across all benchmarked agents
+	n := float64(len(episodes))
+	summary.BestFitness = best.Score
+	summary.MeanFitness = sumFitness / n
+	summary.BestTicks = best.Ticks
+	summary.MeanTicks = sumTicks / n
+	summary.BestFruits = best.Fruits
+	summary.MeanFruits = sumFruits / n
+	summary.BestTerms = best.Terms
+	if l.envSteps != nil {
+		summary.EnvSteps = l.envSteps()
+	}
+	now := time.Now()
+	summary.Seconds = now.Sub(l.last).Seconds()
+	l.last = now
+	return summary
+}
+
+// write hands a summary to every sink. A failing sink does not stop the
+// others; all errors are returned together.
+func (l *Logger) write(summary GenerationSummary) error {
+	var errs []error
+	for _, s := range l.sinks {
+		if err := s.WriteSummary(summary); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// benchmarkMeans averages ticks and fruits across benchmarked agents
+func benchmarkMeans(results []env.AggregatedStats) (float64, float64) {
 	var avgTicks, avgFruits float64
 	for _, r := range results {
 		avgTicks += r.TicksMean
 		avgFruits += r.FruitsMean
 	}
-	avgTicks /= float64(len(results))
-	avgFruits /= float64(len(results))
-
-	fmt.Printf("  [Benchmark] Gen %d: Avg Ticks=%.1f, Avg Fruits=%.2f\n", gen, avgTicks, avgFruits)
+	n := float64(len(results))
+	return avgTicks / n, avgFruits / n
 }
 
-// LogTopK logs debug info for top K agents
+// LogTopK prints debug info for the top K agents on the console sink, unless
+// it is quiet
 func (l *Logger) LogTopK(agents []*ga.Agent, k int) {
+	c := l.console
+	if c == nil || c.verbosity < VerbosityNormal {
+		return
+	}
 	if k > len(agents) {
 		k = len(agents)
 	}
-	fmt.Printf("  Top %d agents:\n", k)
+	fmt.Fprintf(c.out, "  Top %d agents:\n", k)
 	for i := 0; i < k; i++ {
 		a := agents[i]
-		fmt.Printf("    #%d: Fitness=%.1f, Ticks=%d, Fruits=%d, Death=%s\n",
+		fmt.Fprintf(c.out, "    #%d: Fitness=%.1f, Ticks=%d, Fruits=%d, Death=%s\n",
 			i+1, a.Fitness, a.Stats.Ticks, a.Stats.Fruits, a.Stats.Death)
 	}
+	if k > 0 && len(agents[0].Stats.Terms) > 0 {
+		fmt.Fprintf(c.out, "    #1 terms: %s\n", formatTerms(agents[0].Stats.Terms))
+	}
+}
+
+// formatTerms renders a fitness breakdown as name=value pairs sorted by name
+func formatTerms(terms map[string]float64) string {
+	names := make([]string, 0, len(terms))
+	for name := range terms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s=%.1f", name, terms[name])
+	}
+	return strings.Join(parts, " ")
 }
 
 // SaveChampion saves the champion genome to a file
@@ -201,12 +348,14 @@ func SaveChampion(path string, agent *ga.Agent, gen int) error {
 		Fitness    float64   `json:"fitness"`
 		Ticks      int       `json:"ticks"`
 		Fruits     int       `json:"fruits"`
+		Objectives []float64 `json:"objectives,omitempty"`
 		Genome     []float32 `json:"genome"`
 	}{
 		Generation: gen,
 		Fitness:    agent.Fitness,
 		Ticks:      agent.Stats.Ticks,
 		Fruits:     agent.Stats.Fruits,
+		Objectives: agent.Objectives,
 		Genome:     agent.Genome,
 	}
 
@@ -234,4 +383,3 @@ func LoadChampion(path string) ([]float32, error) {
 
 	return saved.Genome, nil
 }
-
@@ -0,0 +1,272 @@
+package logging
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"snakeai/internal/env"
+)
+
+// Sink receives generation summaries. WriteSummary should persist the
+// summary before returning so a crash loses at most the current generation.
+type Sink interface {
+	WriteSummary(s GenerationSummary) error
+	Close() error
+}
+
+// SinkFunc adapts a function to a Sink that never fails
+type SinkFunc func(GenerationSummary)
+
+func (f SinkFunc) WriteSummary(s GenerationSummary) error {
+	f(s)
+	return nil
+}
+
+func (f SinkFunc) Close() error { return nil }
+
+// Verbosity controls how much the console sink prints
+type Verbosity int
+
+const (
+	VerbosityQuiet   Verbosity = iota // nothing per generation
+	VerbosityNormal                   // one line per generation plus staged results
+	VerbosityVerbose                  // also robust score, mean fitness terms and phase timings
+)
+
+// ParseVerbosity parses quiet, normal or verbose; empty means normal
+func ParseVerbosity(s string) (Verbosity, error) {
+	switch s {
+	case "quiet":
+		return VerbosityQuiet, nil
+	case "", "normal":
+		return VerbosityNormal, nil
+	case "verbose":
+		return VerbosityVerbose, nil
+	}
+	return 0, fmt.Errorf("logging: unknown verbosity %q (want quiet, normal or verbose)", s)
+}
+
+// ConsoleSink prints summaries for a human watching the run
+type ConsoleSink struct {
+	out       io.Writer
+	verbosity Verbosity
+}
+
+// NewConsoleSink creates a console sink writing to out
+func NewConsoleSink(out io.Writer, verbosity Verbosity) *ConsoleSink {
+	return &ConsoleSink{out: out, verbosity: verbosity}
+}
+
+func (c *ConsoleSink) WriteSummary(s GenerationSummary) error {
+	if c.verbosity < VerbosityNormal {
+		return nil
+	}
+	deaths := s.DeathCounts
+	w := bufio.NewWriter(c.out)
+	fmt.Fprintf(w, "Gen %4d | Best: %8.1f | Mean: %8.1f | Ticks: %4d | Fruits: %d | Deaths: W=%d S=%d St=%d T=%d\n",
+		s.Generation, s.BestFitness, s.MeanFitness, s.BestTicks, s.BestFruits,
+		deaths[env.DeathWall.String()], deaths[env.DeathSelf.String()],
+		deaths[env.DeathStall.String()], deaths[env.DeathTimeout.String()])
+	if s.EnvSteps > 0 {
+		fmt.Fprintf(w, "         | Env steps: %d\n", s.EnvSteps)
+	}
+	if s.ParetoSize > 0 {
+		fmt.Fprintf(w, "         | Pareto front: %d agents | Hypervolume: %.2f\n", s.ParetoSize, s.Hypervolume)
+	}
+	if len(s.Diagnostics) > 0 {
+		fmt.Fprintf(w, "         | %s\n", formatDiagnostics(s.Diagnostics))
+	}
+	for _, msg := range s.Interventions {
+		fmt.Fprintf(w, "         | Intervention: %s\n", msg)
+	}
+	if c.verbosity >= VerbosityVerbose {
+		if d := s.Diversity; d != nil {
+			fmt.Fprintf(w, "         | Diversity: pairwise %.3f | weight std %.4f | behaviour %.3f | deaths %.2f bits | unique actions %d (%.0f%%)\n",
+				d.PairwiseDistance, d.WeightStd, d.BehaviourDistance, d.DeathEntropy, d.UniqueActions, 100*d.UniqueFraction)
+		}
+		if m := s.Mutation; m != nil {
+			fmt.Fprintf(w, "         | Mutation: rate %.3f | sigma %.4f | boost x%g | success %.0f%% of %d offspring\n",
+				m.Rate, m.Sigma, m.Boost, 100*m.SuccessRate, m.Offspring)
+		}
+		if s.RobustScore != nil {
+			fmt.Fprintf(w, "         | Robust score: %.1f\n", *s.RobustScore)
+		}
+		if len(s.MeanTerms) > 0 {
+			fmt.Fprintf(w, "         | Mean terms: %s\n", formatTerms(s.MeanTerms))
+		}
+		fmt.Fprintf(w, "         | Time: %.2fs%s\n", s.Seconds, formatPhases(s.PhaseSeconds))
+	}
+	if s.BenchmarkTicks != nil && s.BenchmarkFruits != nil {
+		fmt.Fprintf(w, "  [Benchmark] Gen %d: Avg Ticks=%.1f, Avg Fruits=%.2f\n", s.Generation, *s.BenchmarkTicks, *s.BenchmarkFruits)
+	}
+	if g := s.Generalisation; g != nil {
+		fmt.Fprintf(w, "  [Generalisation] Gen %d: Fixed Ticks=%.1f Fruits=%.2f | Randomized Ticks=%.1f Fruits=%.2f | Gap Ticks=%.1f Fruits=%.2f\n",
+			s.Generation, g.FixedTicks, g.FixedFruits, g.RandomTicks, g.RandomFruits,
+			g.FixedTicks-g.RandomTicks, g.FixedFruits-g.RandomFruits)
+	}
+	return w.Flush()
+}
+
+func (c *ConsoleSink) Close() error { return nil }
+
+// formatDiagnostics renders diagnostics as "name: value" pairs sorted by name
+func formatDiagnostics(diag map[string]float64) string {
+	names := sortedKeys(diag)
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s: %.4g", name, diag[name])
+	}
+	return strings.Join(parts, " | ")
+}
+
+// formatPhases renders phase timings as " (name 1.23s, ...)" sorted by name
+func formatPhases(phases map[string]float64) string {
+	if len(phases) == 0 {
+		return ""
+	}
+	names := sortedKeys(phases)
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s %.2fs", name, phases[name])
+	}
+	return " (" + strings.Join(parts, ", ") + ")"
+}
+
+func sortedKeys(m map[string]float64) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// csvHeader lists the fixed CSV columns. Optional results are left empty in
+// generations that did not produce them.
+var csvHeader = []string{
+	"generation", "best_fitness", "mean_fitness", "best_ticks", "mean_ticks",
+	"best_fruits", "mean_fruits", "deaths_wall", "deaths_self", "deaths_stall", "deaths_timeout",
+	"env_steps", "robust_score", "benchmark_ticks", "benchmark_fruits", "seconds",
+	"pairwise_distance", "behaviour_distance", "unique_actions",
+	"mutation_rate", "mutation_sigma", "mutation_success",
+}
+
+// CSVSink writes one row per summary with the columns of csvHeader
+type CSVSink struct {
+	file   *os.File
+	writer *csv.Writer
+}
+
+// NewCSVSink creates the CSV file at path, with its directory, and writes
+// the header
+func NewCSVSink(path string) (*CSVSink, error) {
+	f, err := create(path)
+	if err != nil {
+		return nil, err
+	}
+	c := &CSVSink{file: f, writer: csv.NewWriter(f)}
+	c.writer.Write(csvHeader)
+	c.writer.Flush()
+	if err := c.writer.Error(); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *CSVSink) WriteSummary(s GenerationSummary) error {
+	deaths := s.DeathCounts
+	row := []string{
+		strconv.Itoa(s.Generation),
+		fmt.Sprintf("%.2f", s.BestFitness),
+		fmt.Sprintf("%.2f", s.MeanFitness),
+		strconv.Itoa(s.BestTicks),
+		fmt.Sprintf("%.2f", s.MeanTicks),
+		strconv.Itoa(s.BestFruits),
+		fmt.Sprintf("%.2f", s.MeanFruits),
+		strconv.Itoa(deaths[env.DeathWall.String()]),
+		strconv.Itoa(deaths[env.DeathSelf.String()]),
+		strconv.Itoa(deaths[env.DeathStall.String()]),
+		strconv.Itoa(deaths[env.DeathTimeout.String()]),
+		strconv.FormatInt(s.EnvSteps, 10),
+		optional(s.RobustScore),
+		optional(s.BenchmarkTicks),
+		optional(s.BenchmarkFruits),
+		fmt.Sprintf("%.3f", s.Seconds),
+		"", "", "",
+		"", "", "",
+	}
+	if d := s.Diversity; d != nil {
+		row[len(row)-6] = fmt.Sprintf("%.4f", d.PairwiseDistance)
+		row[len(row)-5] = fmt.Sprintf("%.4f", d.BehaviourDistance)
+		row[len(row)-4] = strconv.Itoa(d.UniqueActions)
+	}
+	if m := s.Mutation; m != nil {
+		row[len(row)-3] = fmt.Sprintf("%.4f", m.Rate)
+		row[len(row)-2] = fmt.Sprintf("%.5f", m.Sigma)
+		row[len(row)-1] = fmt.Sprintf("%.4f", m.SuccessRate)
+	}
+	c.writer.Write(row)
+	c.writer.Flush()
+	if err := c.writer.Error(); err != nil {
+		return fmt.Errorf("csv log: %w", err)
+	}
+	return nil
+}
+
+func (c *CSVSink) Close() error {
+	c.writer.Flush()
+	return errors.Join(c.writer.Error(), c.file.Close())
+}
+
+// optional formats an absent value as an empty cell
+func optional(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprintf("%.2f", *v)
+}
+
+// JSONLSink writes each summary as one JSON line
+type JSONLSink struct {
+	file *os.File
+}
+
+// NewJSONLSink creates the JSONL file at path, with its directory
+func NewJSONLSink(path string) (*JSONLSink, error) {
+	f, err := create(path)
+	if err != nil {
+		return nil, err
+	}
+	return &JSONLSink{file: f}, nil
+}
+
+func (j *JSONLSink) WriteSummary(s GenerationSummary) error {
+	line, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	if _, err := j.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("jsonl log: %w", err)
+	}
+	return nil
+}
+
+func (j *JSONLSink) Close() error { return j.file.Close() }
+
+// create creates or truncates path after making its directory
+func create(path string) (*os.File, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	return os.Create(path)
+}
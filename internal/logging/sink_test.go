@@ -0,0 +1,266 @@
+package logging
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"snakeai/internal/ga"
+)
+
+// testSummaries returns three generations: the first without optional
+// results, the second with a benchmark and robust score of zero, the third
+// with diversity measures
+func testSummaries() []GenerationSummary {
+	zero, ticks, robust := 0.0, 42.5, -3.25
+	return []GenerationSummary{
+		{Generation: 1, BestFitness: 10, MeanFitness: 2.5, BestTicks: 20, MeanTicks: 12.5, BestFruits: 1,
+			DeathCounts: map[string]int{"wall": 3, "stall": 1}},
+		{Generation: 2, BestFitness: 11, MeanFitness: 3, BestTicks: 21, MeanTicks: 13,
+			DeathCounts: map[string]int{"self": 4}, RobustScore: &zero, BenchmarkTicks: &ticks, BenchmarkFruits: &zero},
+		{Generation: 3, BestFitness: 12, MeanFitness: 3.5, BestTicks: 22, MeanTicks: 14, BestFruits: 2,
+			DeathCounts: map[string]int{"timeout": 4}, RobustScore: &robust,
+			Diversity: &ga.Diversity{PairwiseDistance: 1.5, UniqueActions: 3}},
+	}
+}
+
+func writeAll(t *testing.T, sink Sink) {
+	t.Helper()
+	for _, s := range testSummaries() {
+		if err := sink.WriteSummary(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestCSVSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "run.csv")
+	sink, err := NewCSVSink(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeAll(t, sink)
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	rows, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 4 || !reflect.DeepEqual(rows[0], csvHeader) {
+		t.Fatalf("got %d rows with header %v", len(rows), rows[0])
+	}
+	cell := func(row int, name string) string {
+		for i, h := range csvHeader {
+			if h == name {
+				return rows[row][i]
+			}
+		}
+		t.Fatalf("no column %s", name)
+		return ""
+	}
+	for _, c := range []struct {
+		row        int
+		name, want string
+	}{
+		{1, "generation", "1"},
+		{1, "deaths_wall", "3"},
+		{1, "robust_score", ""},
+		{1, "benchmark_fruits", ""},
+		{1, "pairwise_distance", ""},
+		{2, "robust_score", "0.00"},
+		{2, "benchmark_ticks", "42.50"},
+		{2, "benchmark_fruits", "0.00"},
+		{3, "robust_score", "-3.25"},
+		{3, "benchmark_ticks", ""},
+		{3, "pairwise_distance", "1.5000"},
+		{3, "unique_actions", "3"},
+	} {
+		if got := cell(c.row, c.name); got != c.want {
+			t.Errorf("row %d %s = %q, want %q", c.row, c.name, got, c.want)
+		}
+	}
+}
+
+func TestJSONLSinkRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run.jsonl")
+	sink, err := NewJSONLSink(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeAll(t, sink)
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var got []GenerationSummary
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var s GenerationSummary
+		if err := json.Unmarshal(sc.Bytes(), &s); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, s)
+	}
+	if want := testSummaries(); !reflect.DeepEqual(got, want) {
+		t.Errorf("JSONL round trip got %+v, want %+v", got, want)
+	}
+}
+
+func TestColumnarSinkRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run.col")
+	sink, err := NewColumnarSink(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeAll(t, sink)
+
+	check := func(stage string) *Table {
+		table, err := ReadColumnar(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if table.Rows != 3 || table.Columns[0] != "generation" {
+			t.Fatalf("%s: got %d rows and columns %v", stage, table.Rows, table.Columns)
+		}
+		nan := math.NaN()
+		for name, want := range map[string][]float64{
+			"generation":                  {1, 2, 3},
+			"fitness/best":                {10, 11, 12},
+			"deaths/wall":                 {3, 0, 0},
+			"robust_score":                {nan, 0, -3.25},
+			"benchmark/ticks":             {nan, 42.5, nan},
+			"benchmark/fruits":            {nan, 0, nan},
+			"diversity/pairwise_distance": {nan, nan, 1.5},
+		} {
+			got := table.Data[name]
+			if len(got) != len(want) {
+				t.Fatalf("%s: column %s has %d values, want %d", stage, name, len(got), len(want))
+			}
+			for i := range want {
+				if got[i] != want[i] && !(math.IsNaN(got[i]) && math.IsNaN(want[i])) {
+					t.Errorf("%s: %s row %d = %v, want %v", stage, name, i, got[i], want[i])
+				}
+			}
+		}
+		return table
+	}
+
+	// Readers can load the log while the run appends to it, ignoring a
+	// block that is only partly written
+	running := check("running")
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Write([]byte{200, 0, 0, 0, '{'})
+	f.Close()
+	check("partial block")
+	if err := os.Truncate(path, fileSize(t, path)-5); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+	closed := check("closed")
+	if !reflect.DeepEqual(closed.Columns, running.Columns) {
+		t.Errorf("compaction reordered columns: %v, was %v", closed.Columns, running.Columns)
+	}
+	// Compaction leaves a single block: the magic, a header and the values
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := len(columnarMagic) + 4 + int(binary.LittleEndian.Uint32(data[len(columnarMagic):]))
+	if want := n + 8*closed.Rows*len(closed.Columns); len(data) != want {
+		t.Errorf("closed log is %d bytes, want a single block of %d", len(data), want)
+	}
+}
+
+func fileSize(t *testing.T, path string) int64 {
+	t.Helper()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return info.Size()
+}
+
+func TestReadColumnarRejectsOtherFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run.col")
+	os.WriteFile(path, []byte("not a log"), 0o644)
+	if _, err := ReadColumnar(path); err == nil || !strings.Contains(err.Error(), "not a columnar log") {
+		t.Errorf("got error %v", err)
+	}
+}
+
+// failingSink rejects every summary
+type failingSink struct{ written int }
+
+func (f *failingSink) WriteSummary(GenerationSummary) error {
+	f.written++
+	return errors.New("disk full")
+}
+
+func (f *failingSink) Close() error { return nil }
+
+func TestSinkErrors(t *testing.T) {
+	dir := t.TempDir()
+	blocker := filepath.Join(dir, "file")
+	os.WriteFile(blocker, nil, 0o644)
+	if _, err := NewCSVSink(filepath.Join(blocker, "run.csv")); err == nil {
+		t.Error("NewCSVSink succeeded under a regular file")
+	}
+	if _, err := NewColumnarSink(filepath.Join(blocker, "run.col")); err == nil {
+		t.Error("NewColumnarSink succeeded under a regular file")
+	}
+
+	// Writes after the file is gone report which sink failed
+	jsonl, err := NewJSONLSink(filepath.Join(dir, "run.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	jsonl.file.Close()
+	if err := jsonl.WriteSummary(testSummaries()[0]); err == nil || !strings.HasPrefix(err.Error(), "jsonl log:") {
+		t.Errorf("JSONL write to a closed file: got error %v", err)
+	}
+	col, err := NewColumnarSink(filepath.Join(dir, "run.col"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	col.file.Close()
+	if err := col.WriteSummary(testSummaries()[0]); err == nil || !strings.HasPrefix(err.Error(), "columnar log:") {
+		t.Errorf("columnar write to a closed file: got error %v", err)
+	}
+
+	// A failing sink does not stop the others
+	failing := &failingSink{}
+	var seen []int
+	l := NewLogger(failing, SinkFunc(func(s GenerationSummary) { seen = append(seen, s.Generation) }))
+	if err := l.write(testSummaries()[0]); err == nil || !strings.Contains(err.Error(), "disk full") {
+		t.Errorf("got error %v, want the failing sink's", err)
+	}
+	if failing.written != 1 || len(seen) != 1 {
+		t.Errorf("failing sink saw %d summaries and the next sink %d, want 1 each", failing.written, len(seen))
+	}
+}
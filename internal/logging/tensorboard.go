@@ -0,0 +1,136 @@
+package logging
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// TensorBoardSink writes summaries as scalar events that TensorBoard reads
+// from a log directory. Event files are TFRecord streams of Event protocol
+// buffers; both layers are encoded by hand for the few fields scalars need.
+type TensorBoardSink struct {
+	file *os.File
+	w    *bufio.Writer
+}
+
+// NewTensorBoardSink creates a new event file in dir
+func NewTensorBoardSink(dir string) (*TensorBoardSink, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	host, err := os.Hostname()
+	if err != nil {
+		host = "localhost"
+	}
+	now := time.Now()
+	name := fmt.Sprintf("events.out.tfevents.%d.%s", now.Unix(), host)
+	f, err := os.Create(filepath.Join(dir, name))
+	if err != nil {
+		return nil, err
+	}
+	t := &TensorBoardSink{file: f, w: bufio.NewWriter(f)}
+
+	// Every event file starts with its format version
+	var event []byte
+	event = appendDouble(event, 1, wallTime(now))
+	event = appendBytes(event, 3, []byte("brain.Event:2"))
+	t.writeRecord(event)
+	if err := t.w.Flush(); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return t, nil
+}
+
+func (t *TensorBoardSink) WriteSummary(s GenerationSummary) error {
+	var summary []byte
+	for _, v := range scalars(s) {
+		var value []byte
+		value = appendBytes(value, 1, []byte(v.name))
+		value = appendFloat(value, 2, float32(v.value))
+		summary = appendBytes(summary, 1, value)
+	}
+	var event []byte
+	event = appendDouble(event, 1, wallTime(time.Now()))
+	event = appendVarintField(event, 2, uint64(s.Generation))
+	event = appendBytes(event, 5, summary)
+	t.writeRecord(event)
+	if err := t.w.Flush(); err != nil {
+		return fmt.Errorf("tensorboard log: %w", err)
+	}
+	return nil
+}
+
+func (t *TensorBoardSink) Close() error {
+	t.w.Flush()
+	return t.file.Close()
+}
+
+func wallTime(t time.Time) float64 {
+	return float64(t.UnixNano()) / 1e9
+}
+
+// writeRecord frames data as a TFRecord: length, masked CRC of the length,
+// data, masked CRC of the data
+func (t *TensorBoardSink) writeRecord(data []byte) {
+	var header [12]byte
+	binary.LittleEndian.PutUint64(header[:8], uint64(len(data)))
+	binary.LittleEndian.PutUint32(header[8:], maskedCRC(header[:8]))
+	var footer [4]byte
+	binary.LittleEndian.PutUint32(footer[:], maskedCRC(data))
+	t.w.Write(header[:])
+	t.w.Write(data)
+	t.w.Write(footer[:])
+}
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// maskedCRC is the CRC-32C of data, rotated and offset as TFRecord requires
+func maskedCRC(data []byte) uint32 {
+	crc := crc32.Checksum(data, castagnoli)
+	return (crc>>15 | crc<<17) + 0xa282ead8
+}
+
+// Protocol buffer wire format helpers
+
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+func appendVarint(b []byte, v uint64) []byte {
+	for v >= 0x80 {
+		b = append(b, byte(v)|0x80)
+		v >>= 7
+	}
+	return append(b, byte(v))
+}
+
+func appendTag(b []byte, field, wire int) []byte {
+	return appendVarint(b, uint64(field<<3|wire))
+}
+
+func appendVarintField(b []byte, field int, v uint64) []byte {
+	return appendVarint(appendTag(b, field, wireVarint), v)
+}
+
+func appendDouble(b []byte, field int, v float64) []byte {
+	return binary.LittleEndian.AppendUint64(appendTag(b, field, wireFixed64), math.Float64bits(v))
+}
+
+func appendFloat(b []byte, field int, v float32) []byte {
+	return binary.LittleEndian.AppendUint32(appendTag(b, field, wireFixed32), math.Float32bits(v))
+}
+
+func appendBytes(b []byte, field int, data []byte) []byte {
+	b = appendVarint(appendTag(b, field, wireBytes), uint64(len(data)))
+	return append(b, data...)
+}
@@ -0,0 +1,219 @@
+// Package metrics exposes training metrics in the Prometheus text format
+// and OpenMetrics, written by hand so scraping needs no client library and
+// the output can be checked with curl or any io.Writer.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Kind is the type of a metric family
+type Kind int
+
+const (
+	KindGauge Kind = iota
+	KindCounter
+)
+
+func (k Kind) String() string {
+	if k == KindCounter {
+		return "counter"
+	}
+	return "gauge"
+}
+
+// Content types of the two exposition formats
+const (
+	ContentTypeText        = "text/plain; version=0.0.4; charset=utf-8"
+	ContentTypeOpenMetrics = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+)
+
+// Registry holds metric families in registration order
+type Registry struct {
+	mu       sync.Mutex
+	families []*Family
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Family is a named metric with a fixed set of label names and one value
+// per combination of label values
+type Family struct {
+	reg    *Registry
+	name   string // without the _total suffix for counters
+	help   string
+	kind   Kind
+	labels []string
+	series map[string]*series
+}
+
+type series struct {
+	labelValues []string
+	value       float64
+}
+
+// Gauge registers a gauge family
+func (r *Registry) Gauge(name, help string, labels ...string) *Family {
+	return r.register(name, help, KindGauge, labels)
+}
+
+// Counter registers a counter family. name must not end in _total; the
+// suffix is added to samples as both formats require.
+func (r *Registry) Counter(name, help string, labels ...string) *Family {
+	return r.register(name, help, KindCounter, labels)
+}
+
+func (r *Registry) register(name, help string, kind Kind, labels []string) *Family {
+	f := &Family{
+		reg:    r,
+		name:   name,
+		help:   help,
+		kind:   kind,
+		labels: labels,
+		series: make(map[string]*series),
+	}
+	r.mu.Lock()
+	r.families = append(r.families, f)
+	r.mu.Unlock()
+	return f
+}
+
+// Set sets the value for the given label values. For counters the value
+// must never decrease; use it to mirror a cumulative count kept elsewhere.
+func (f *Family) Set(value float64, labelValues ...string) {
+	f.reg.mu.Lock()
+	f.get(labelValues).value = value
+	f.reg.mu.Unlock()
+}
+
+// Add adds delta to the value for the given label values
+func (f *Family) Add(delta float64, labelValues ...string) {
+	f.reg.mu.Lock()
+	f.get(labelValues).value += delta
+	f.reg.mu.Unlock()
+}
+
+// get returns the series for labelValues, creating it at zero. The caller
+// holds the registry lock.
+func (f *Family) get(labelValues []string) *series {
+	if len(labelValues) != len(f.labels) {
+		panic(fmt.Sprintf("metrics: %s takes %d label values, got %d", f.name, len(f.labels), len(labelValues)))
+	}
+	key := strings.Join(labelValues, "\xff")
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{labelValues: append([]string(nil), labelValues...)}
+		f.series[key] = s
+	}
+	return s
+}
+
+// WriteText writes every family with at least one series in the
+// Prometheus text format, or OpenMetrics (ending in # EOF) if openMetrics
+// is set. Series are sorted by label values so output is stable.
+func (r *Registry) WriteText(w io.Writer, openMetrics bool) error {
+	bw := bufio.NewWriter(w)
+	r.mu.Lock()
+	for _, f := range r.families {
+		if len(f.series) == 0 {
+			continue
+		}
+		sample := f.name
+		if f.kind == KindCounter {
+			sample += "_total"
+		}
+		// OpenMetrics names the counter family without its suffix
+		family := sample
+		if openMetrics {
+			family = f.name
+		}
+		fmt.Fprintf(bw, "# HELP %s %s\n", family, escapeHelp(f.help))
+		fmt.Fprintf(bw, "# TYPE %s %s\n", family, f.kind)
+
+		keys := make([]string, 0, len(f.series))
+		for k := range f.series {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			s := f.series[k]
+			bw.WriteString(sample)
+			if len(f.labels) > 0 {
+				bw.WriteByte('{')
+				for i, name := range f.labels {
+					if i > 0 {
+						bw.WriteByte(',')
+					}
+					fmt.Fprintf(bw, `%s="%s"`, name, escapeLabel(s.labelValues[i]))
+				}
+				bw.WriteByte('}')
+			}
+			bw.WriteByte(' ')
+			bw.WriteString(formatValue(s.value))
+			bw.WriteByte('\n')
+		}
+	}
+	r.mu.Unlock()
+	if openMetrics {
+		bw.WriteString("# EOF\n")
+	}
+	return bw.Flush()
+}
+
+// ServeHTTP writes the registry, in OpenMetrics if the scraper asks for it
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	openMetrics := strings.Contains(req.Header.Get("Accept"), "application/openmetrics-text")
+	if openMetrics {
+		w.Header().Set("Content-Type", ContentTypeOpenMetrics)
+	} else {
+		w.Header().Set("Content-Type", ContentTypeText)
+	}
+	r.WriteText(w, openMetrics)
+}
+
+// Serve exposes the registry at /metrics on addr in the background. The
+// returned server's Addr holds the bound address.
+func (r *Registry) Serve(addr string) (*http.Server, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", r)
+	srv := &http.Server{Addr: ln.Addr().String(), Handler: mux}
+	go srv.Serve(ln)
+	return srv, nil
+}
+
+// formatValue renders a sample value, spelling out infinities and NaN
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string  { return helpEscaper.Replace(s) }
+func escapeLabel(s string) string { return labelEscaper.Replace(s) }
@@ -0,0 +1,50 @@
+package metrics
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestWriteText(t *testing.T) {
+	r := NewRegistry()
+	g := r.Gauge("test_gauge", "A gauge.\nSecond line with \\ backslash.", "kind")
+	c := r.Counter("test_events", "Events seen.")
+	r.Gauge("test_unused", "Never set, so never written.")
+
+	g.Set(1.5, `quote " and \ and`+"\nnewline")
+	g.Set(math.Inf(1), "inf")
+	c.Add(2)
+	c.Add(3)
+
+	var buf bytes.Buffer
+	if err := r.WriteText(&buf, false); err != nil {
+		t.Fatal(err)
+	}
+	want := `# HELP test_gauge A gauge.\nSecond line with \\ backslash.
+# TYPE test_gauge gauge
+test_gauge{kind="inf"} +Inf
+test_gauge{kind="quote \" and \\ and\nnewline"} 1.5
+# HELP test_events_total Events seen.
+# TYPE test_events_total counter
+test_events_total 5
+`
+	if buf.String() != want {
+		t.Errorf("text format:\n%s\nwant:\n%s", buf.String(), want)
+	}
+
+	buf.Reset()
+	if err := r.WriteText(&buf, true); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, line := range []string{"# TYPE test_events counter\n", "test_events_total 5\n"} {
+		if !strings.Contains(out, line) {
+			t.Errorf("OpenMetrics output lacks %q:\n%s", line, out)
+		}
+	}
+	if !strings.HasSuffix(out, "# EOF\n") {
+		t.Errorf("OpenMetrics output does not end in # EOF:\n%s", out)
+	}
+}
@@ -0,0 +1,126 @@
+package metrics
+
+import (
+	"snakeai/internal/env"
+	"snakeai/internal/eval"
+	"snakeai/internal/ga"
+	"snakeai/internal/logging"
+)
+
+// deathReasons are pre-created so every reason is exported from the start
+var deathReasons = []env.DeathReason{env.DeathWall, env.DeathSelf, env.DeathStall, env.DeathTimeout}
+
+// Training holds the metrics of a training run. A nil *Training ignores
+// every Observe call, so callers need not check whether export is enabled.
+type Training struct {
+	Registry *Registry
+	usage    func() eval.Usage
+	last     eval.Usage // usage at the previous generation
+
+	generation  *Family
+	fitness     *Family
+	ticks       *Family
+	fruits      *Family
+	robustScore *Family
+	benchTicks  *Family
+	benchFruits *Family
+	deaths      *Family
+	diversity   *Family
+	mutation    *Family
+	envSteps    *Family
+	episodes    *Family
+	cache       *Family
+	evalRate    *Family
+	workers     *Family
+	busy        *Family
+	utilisation *Family
+}
+
+// NewTraining registers the training metrics. usage reports the
+// evaluator's cumulative work and may be nil.
+func NewTraining(usage func() eval.Usage) *Training {
+	r := NewRegistry()
+	t := &Training{
+		Registry: r,
+		usage:    usage,
+
+		generation:  r.Gauge("snake_generation", "Last completed generation or training iteration."),
+		fitness:     r.Gauge("snake_fitness", "Fitness of the last generation.", "stat"),
+		ticks:       r.Gauge("snake_ticks", "Episode length in ticks in the last generation.", "stat"),
+		fruits:      r.Gauge("snake_fruits", "Fruits eaten per episode in the last generation.", "stat"),
+		robustScore: r.Gauge("snake_robust_score", "Robust score (mean - lambda*std over seeds) of the generation's most robust candidate."),
+		benchTicks:  r.Gauge("snake_benchmark_ticks", "Mean ticks on the benchmark seeds at the last benchmark."),
+		benchFruits: r.Gauge("snake_benchmark_fruits", "Mean fruits on the benchmark seeds at the last benchmark."),
+		deaths:      r.Counter("snake_deaths", "Training episodes ended, by death reason.", "reason"),
+		diversity:   r.Gauge("snake_diversity", "Population diversity of the last generation, by measure.", "measure"),
+		mutation:    r.Gauge("snake_mutation", "Mutation settings used to breed the last generation: rate, sigma, boost and success_rate.", "param"),
+		envSteps:    r.Counter("snake_env_steps", "Environment steps that drove training."),
+		episodes:    r.Counter("snake_evaluations", "Episodes evaluated in batches, locally or remotely."),
+		cache:       r.Counter("snake_evaluation_cache", "Episode lookups in the evaluation cache, by result (hit or miss).", "result"),
+		evalRate:    r.Gauge("snake_evaluations_per_second", "Batched episode evaluations per second over the last generation."),
+		workers:     r.Gauge("snake_workers", "Local evaluation worker pool size."),
+		busy:        r.Counter("snake_worker_busy_seconds", "Time local workers spent evaluating."),
+		utilisation: r.Gauge("snake_worker_utilisation", "Fraction of local worker time spent evaluating over the last generation."),
+	}
+	for _, reason := range deathReasons {
+		t.deaths.Add(0, reason.String())
+	}
+	return t
+}
+
+// ObserveGeneration records a generation summary and the evaluator's work
+// since the previous one. It has the signature of a logging.Logger listener.
+func (t *Training) ObserveGeneration(s logging.GenerationSummary) {
+	if t == nil {
+		return
+	}
+	t.generation.Set(float64(s.Generation))
+	t.fitness.Set(s.BestFitness, "best")
+	t.fitness.Set(s.MeanFitness, "mean")
+	t.ticks.Set(float64(s.BestTicks), "best")
+	t.ticks.Set(s.MeanTicks, "mean")
+	t.fruits.Set(float64(s.BestFruits), "best")
+	t.fruits.Set(s.MeanFruits, "mean")
+	for reason, n := range s.DeathCounts {
+		t.deaths.Add(float64(n), reason)
+	}
+	if s.EnvSteps > 0 {
+		t.envSteps.Set(float64(s.EnvSteps))
+	}
+	if s.RobustScore != nil {
+		t.robustScore.Set(*s.RobustScore)
+	}
+	if s.BenchmarkTicks != nil && s.BenchmarkFruits != nil {
+		t.benchTicks.Set(*s.BenchmarkTicks)
+		t.benchFruits.Set(*s.BenchmarkFruits)
+	}
+	if s.Diversity != nil {
+		for _, name := range ga.DiversityMeasures {
+			v, _ := s.Diversity.Measure(name)
+			t.diversity.Set(v, name)
+		}
+	}
+	if m := s.Mutation; m != nil {
+		t.mutation.Set(m.Rate, "rate")
+		t.mutation.Set(m.Sigma, "sigma")
+		t.mutation.Set(m.Boost, "boost")
+		t.mutation.Set(m.SuccessRate, "success_rate")
+	}
+
+	if t.usage == nil {
+		return
+	}
+	u := t.usage()
+	t.episodes.Set(float64(u.Episodes))
+	t.cache.Set(float64(u.CacheHits), "hit")
+	t.cache.Set(float64(u.CacheMisses), "miss")
+	t.workers.Set(float64(u.Workers))
+	t.busy.Set(u.Busy.Seconds())
+	if wall := (u.Uptime - t.last.Uptime).Seconds(); wall > 0 {
+		t.evalRate.Set(float64(u.Episodes-t.last.Episodes) / wall)
+		if u.Workers > 0 {
+			t.utilisation.Set((u.Busy - t.last.Busy).Seconds() / (wall * float64(u.Workers)))
+		}
+	}
+	t.last = u
+}
@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"snakeai/internal/eval"
+	"snakeai/internal/logging"
+)
+
+func TestTrainingObserveGeneration(t *testing.T) {
+	usage := eval.Usage{Workers: 4}
+	tr := NewTraining(func() eval.Usage { return usage })
+
+	for gen := 1; gen <= 3; gen++ {
+		usage.Episodes += 100
+		usage.CacheHits += 10
+		usage.Busy += 2 * time.Second
+		usage.Uptime += time.Second
+		tr.ObserveGeneration(logging.GenerationSummary{
+			Generation:  gen,
+			BestFitness: float64(100 * gen),
+			MeanFitness: float64(10 * gen),
+			DeathCounts: map[string]int{"wall": 2, "self": 1},
+			EnvSteps:    int64(1000 * gen),
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := tr.Registry.WriteText(&buf, false); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, line := range []string{
+		"# TYPE snake_generation gauge",
+		"snake_generation 3",
+		`snake_fitness{stat="best"} 300`,
+		`snake_fitness{stat="mean"} 30`,
+		"# TYPE snake_deaths_total counter",
+		`snake_deaths_total{reason="wall"} 6`,
+		`snake_deaths_total{reason="self"} 3`,
+		`snake_deaths_total{reason="stall"} 0`,
+		"snake_env_steps_total 3000",
+		"snake_evaluations_total 300",
+		`snake_evaluation_cache_total{result="hit"} 30`,
+		"snake_evaluations_per_second 100",
+		"snake_workers 4",
+		"snake_worker_utilisation 0.5",
+	} {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("exposition lacks %q:\n%s", line, out)
+		}
+	}
+}
+
+func TestNilTrainingIgnoresObservations(t *testing.T) {
+	var tr *Training
+	tr.ObserveGeneration(logging.GenerationSummary{Generation: 1})
+}
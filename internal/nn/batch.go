@@ -0,0 +1,65 @@
+package nn
+
+// BatchMLP evaluates a matrix of observations against many genomes that share
+// one layout: row i of the observation matrix is fed through genomes[i].
+// Each row walks its genome's contiguous weights front to back, and all
+// activation buffers are reused across calls.
+type BatchMLP struct {
+	InputSize  int
+	Hidden1    int
+	Hidden2    int
+	OutputSize int
+
+	h1  []float32
+	h2  []float32
+	out []float32
+}
+
+// NewBatchMLP creates a batched evaluator for the given architecture
+func NewBatchMLP(inputSize, hidden1, hidden2, outputSize int) *BatchMLP {
+	return &BatchMLP{
+		InputSize:  inputSize,
+		Hidden1:    hidden1,
+		Hidden2:    hidden2,
+		OutputSize: outputSize,
+	}
+}
+
+// Forward runs every active row through its genome and writes the argmax
+// action to actions[i]. Inactive rows are skipped; a nil mask means all rows.
+// Results are bit-identical to MLP.Forward on the same genome and input.
+func (b *BatchMLP) Forward(genomes [][]float32, obs []float32, active []bool, actions []int) {
+	n := len(genomes)
+	b.grow(n)
+
+	for i := 0; i < n; i++ {
+		if active != nil && !active[i] {
+			continue
+		}
+		input := obs[i*b.InputSize : (i+1)*b.InputSize]
+		h1 := b.h1[i*b.Hidden1 : (i+1)*b.Hidden1]
+		var h2 []float32
+		if b.Hidden2 > 0 {
+			h2 = b.h2[i*b.Hidden2 : (i+1)*b.Hidden2]
+		}
+		out := b.out[i*b.OutputSize : (i+1)*b.OutputSize]
+
+		forward(genomes[i], input, b.InputSize, b.Hidden1, b.Hidden2, b.OutputSize, h1, h2, out)
+		actions[i] = argmax(out)
+	}
+}
+
+// Outputs returns the raw output row for batch entry i from the last Forward
+func (b *BatchMLP) Outputs(i int) []float32 {
+	return b.out[i*b.OutputSize : (i+1)*b.OutputSize]
+}
+
+// grow ensures activation buffers hold n rows
+func (b *BatchMLP) grow(n int) {
+	if len(b.out) >= n*b.OutputSize {
+		return
+	}
+	b.h1 = make([]float32, n*b.Hidden1)
+	b.h2 = make([]float32, n*b.Hidden2)
+	b.out = make([]float32, n*b.OutputSize)
+}
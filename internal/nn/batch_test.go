@@ -0,0 +1,53 @@
+package nn
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// TestBatchForwardMatchesMLP feeds random genomes and observations through
+// BatchMLP and MLP and requires identical outputs and actions, across
+// batch sizes that grow and shrink the reused buffers and with inactive rows
+// left untouched
+func TestBatchForwardMatchesMLP(t *testing.T) {
+	for _, layout := range []struct{ in, h1, h2, out int }{{9, 16, 0, 3}, {9, 16, 8, 3}} {
+		rng := rand.New(rand.NewSource(2))
+		m := NewMLP(layout.in, layout.h1, layout.h2, layout.out)
+		b := NewBatchMLP(layout.in, layout.h1, layout.h2, layout.out)
+
+		for _, n := range []int{1, 7, 64, 3, 64} {
+			genomes := make([][]float32, n)
+			obs := make([]float32, n*layout.in)
+			active := make([]bool, n)
+			actions := make([]int, n)
+			for i := range genomes {
+				genomes[i] = RandomGenome(m.GenomeSize(), rng)
+				active[i] = rng.Intn(4) > 0
+				actions[i] = -1
+			}
+			for i := range obs {
+				obs[i] = float32(rng.NormFloat64())
+			}
+
+			b.Forward(genomes, obs, active, actions)
+			for i := range genomes {
+				if !active[i] {
+					if actions[i] != -1 {
+						t.Fatalf("layout %v, batch %d: inactive row %d got action %d", layout, n, i, actions[i])
+					}
+					continue
+				}
+				m.SetWeights(genomes[i])
+				want := m.Forward(obs[i*layout.in : (i+1)*layout.in])
+				if actions[i] != want {
+					t.Fatalf("layout %v, batch %d: row %d action %d, want %d", layout, n, i, actions[i], want)
+				}
+				for k, o := range m.Outputs() {
+					if got := b.Outputs(i)[k]; got != o {
+						t.Fatalf("layout %v, batch %d: row %d output %d is %v, want %v", layout, n, i, k, got, o)
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package nn
+
+import (
+	"math"
+)
+
+// Backward accumulates into grad the gradient of a loss with respect to the
+// network weights. dOut is dLoss/dOutputs for the input most recently passed
+// to Forward, whose activations are still held in the network's buffers.
+// grad uses the same layout as Weights.
+func (m *MLP) Backward(input, dOut, grad []float32) {
+	if len(m.dh1) != m.Hidden1 {
+		m.dh1 = make([]float32, m.Hidden1)
+		m.dh2 = make([]float32, m.Hidden2)
+	}
+
+	offset1 := 0
+	offset2 := (m.InputSize + 1) * m.Hidden1
+	offsetOut := offset2
+	last, dLast := m.h1, m.dh1
+	if m.Hidden2 > 0 {
+		offsetOut += (m.Hidden1 + 1) * m.Hidden2
+		last, dLast = m.h2, m.dh2
+	}
+
+	// Last hidden -> Output
+	backLayer(m.Weights[offsetOut:], grad[offsetOut:], last, dOut, dLast)
+	reluBack(last, dLast)
+
+	if m.Hidden2 > 0 {
+		// Hidden1 -> Hidden2
+		backLayer(m.Weights[offset2:], grad[offset2:], m.h1, m.dh2, m.dh1)
+		reluBack(m.h1, m.dh1)
+	}
+
+	// Input -> Hidden1
+	backLayer(m.Weights[offset1:], grad[offset1:], input, m.dh1, nil)
+}
+
+// backLayer backpropagates through one dense layer stored as rows of
+// [bias, weights...]. It accumulates weight gradients and, if dIn is not
+// nil, overwrites dIn with the gradient with respect to the layer input.
+func backLayer(weights, grad, in, dOutLayer, dIn []float32) {
+	for i := range dIn {
+		dIn[i] = 0
+	}
+	stride := len(in) + 1
+	for j, d := range dOutLayer {
+		if d == 0 {
+			continue
+		}
+		row := j * stride
+		grad[row] += d
+		for i, x := range in {
+			grad[row+1+i] += d * x
+			if dIn != nil {
+				dIn[i] += d * weights[row+1+i]
+			}
+		}
+	}
+}
+
+// reluBack zeroes gradients of units whose ReLU output was not positive
+func reluBack(activations, grad []float32) {
+	for i, a := range activations {
+		if a <= 0 {
+			grad[i] = 0
+		}
+	}
+}
+
+// Adam is the Adam optimiser for a flat weight vector
+type Adam struct {
+	LR    float64
+	Beta1 float64
+	Beta2 float64
+	Eps   float64
+
+	m []float64
+	v []float64
+	t int
+}
+
+// NewAdam creates an Adam optimiser with the usual defaults
+func NewAdam(size int, lr float64) *Adam {
+	return &Adam{
+		LR:    lr,
+		Beta1: 0.9,
+		Beta2: 0.999,
+		Eps:   1e-8,
+		m:     make([]float64, size),
+		v:     make([]float64, size),
+	}
+}
+
+// Step applies one descent update of weights along grad
+func (a *Adam) Step(weights, grad []float32) {
+	a.t++
+	c1 := 1 - math.Pow(a.Beta1, float64(a.t))
+	c2 := 1 - math.Pow(a.Beta2, float64(a.t))
+	for i, g := range grad {
+		gf := float64(g)
+		a.m[i] = a.Beta1*a.m[i] + (1-a.Beta1)*gf
+		a.v[i] = a.Beta2*a.v[i] + (1-a.Beta2)*gf*gf
+		mHat := a.m[i] / c1
+		vHat := a.v[i] / c2
+		weights[i] -= float32(a.LR * mHat / (math.Sqrt(vHat) + a.Eps))
+	}
+}
+
+// ClipGradNorm rescales grad so its L2 norm is at most maxNorm and returns
+// the norm before clipping
+func ClipGradNorm(grad []float32, maxNorm float64) float64 {
+	sum := 0.0
+	for _, g := range grad {
+		sum += float64(g) * float64(g)
+	}
+	norm := math.Sqrt(sum)
+	if maxNorm > 0 && norm > maxNorm {
+		scale := float32(maxNorm / norm)
+		for i := range grad {
+			grad[i] *= scale
+		}
+	}
+	return norm
+}
+
+// Softmax writes the softmax of logits into probs
+func Softmax(logits []float32, probs []float64) {
+	maxLogit := float64(logits[0])
+	for _, l := range logits[1:] {
+		maxLogit = math.Max(maxLogit, float64(l))
+	}
+	sum := 0.0
+	for i, l := range logits {
+		probs[i] = math.Exp(float64(l) - maxLogit)
+		sum += probs[i]
+	}
+	for i := range probs {
+		probs[i] /= sum
+	}
+}
@@ -0,0 +1,54 @@
+package nn
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// TestBackwardMatchesFiniteDifferences checks the gradient of the loss
+// sum_k c_k*out_k against central differences. The network is piecewise
+// linear, so the differences are exact unless a step crosses a ReLU kink.
+func TestBackwardMatchesFiniteDifferences(t *testing.T) {
+	for _, layout := range []struct{ in, h1, h2, out int }{{6, 8, 0, 3}, {6, 8, 5, 3}} {
+		rng := rand.New(rand.NewSource(1))
+		m := NewMLP(layout.in, layout.h1, layout.h2, layout.out)
+		for i := range m.Weights {
+			m.Weights[i] = float32(rng.NormFloat64())
+		}
+		input := make([]float32, layout.in)
+		for i := range input {
+			input[i] = float32(rng.NormFloat64())
+		}
+		coef := make([]float32, layout.out)
+		for i := range coef {
+			coef[i] = float32(rng.NormFloat64())
+		}
+		loss := func() float64 {
+			m.Forward(input)
+			sum := 0.0
+			for k, o := range m.Outputs() {
+				sum += float64(coef[k]) * float64(o)
+			}
+			return sum
+		}
+
+		grad := make([]float32, len(m.Weights))
+		m.Forward(input)
+		m.Backward(input, coef, grad)
+
+		const eps = 1e-2
+		for i := range m.Weights {
+			w := m.Weights[i]
+			m.Weights[i] = w + eps
+			up := loss()
+			m.Weights[i] = w - eps
+			down := loss()
+			m.Weights[i] = w
+			numeric := (up - down) / (2 * eps)
+			if math.Abs(numeric-float64(grad[i])) > 1e-3*math.Max(1, math.Abs(numeric)) {
+				t.Errorf("hidden2=%d weight %d: backward %g, finite difference %g", layout.h2, i, grad[i], numeric)
+			}
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package nn
 
 import (
+	"encoding/binary"
+	"hash/fnv"
 	"math"
 	"math/rand"
 )
@@ -19,6 +21,10 @@ type MLP struct {
 	h1  []float32
 	h2  []float32
 	out []float32
+
+	// Backward pass buffers, allocated on first use
+	dh1 []float32
+	dh2 []float32
 }
 
 // NewMLP creates a new MLP with the given architecture
@@ -62,6 +68,27 @@ func (m *MLP) GenomeSize() int {
 	return size
 }
 
+// NeuronSpans returns the genome range [start, end) of every neuron's bias
+// and incoming weights, layer by layer, in the order forward reads them
+func NeuronSpans(inputSize, hidden1, hidden2, outputSize int) [][2]int {
+	var spans [][2]int
+	offset := 0
+	layer := func(fanIn, neurons int) {
+		for j := 0; j < neurons; j++ {
+			spans = append(spans, [2]int{offset, offset + fanIn + 1})
+			offset += fanIn + 1
+		}
+	}
+	layer(inputSize, hidden1)
+	last := hidden1
+	if hidden2 > 0 {
+		layer(hidden1, hidden2)
+		last = hidden2
+	}
+	layer(last, outputSize)
+	return spans
+}
+
 // SetWeights copies genome into the network weights
 func (m *MLP) SetWeights(genome []float32) {
 	copy(m.Weights, genome)
@@ -69,51 +96,63 @@ func (m *MLP) SetWeights(genome []float32) {
 
 // Forward performs a forward pass and returns the output index with max value
 func (m *MLP) Forward(input []float32) int {
+	forward(m.Weights, input, m.InputSize, m.Hidden1, m.Hidden2, m.OutputSize, m.h1, m.h2, m.out)
+
+	// Return argmax
+	return argmax(m.out)
+}
+
+// forward runs one forward pass of the layout described by the sizes,
+// writing activations into h1, h2 (unused when hidden2 is 0) and out
+func forward(weights, input []float32, inputSize, hidden1, hidden2, outputSize int, h1, h2, out []float32) {
 	offset := 0
 
 	// Input -> Hidden1
-	for j := 0; j < m.Hidden1; j++ {
-		sum := m.Weights[offset] // bias
+	for j := 0; j < hidden1; j++ {
+		sum := weights[offset] // bias
 		offset++
-		for i := 0; i < m.InputSize; i++ {
-			sum += input[i] * m.Weights[offset]
+		for i := 0; i < inputSize; i++ {
+			sum += input[i] * weights[offset]
 			offset++
 		}
-		m.h1[j] = relu(sum)
+		h1[j] = relu(sum)
 	}
 
 	var lastHidden []float32
 
-	if m.Hidden2 > 0 {
+	if hidden2 > 0 {
 		// Hidden1 -> Hidden2
-		for j := 0; j < m.Hidden2; j++ {
-			sum := m.Weights[offset] // bias
+		for j := 0; j < hidden2; j++ {
+			sum := weights[offset] // bias
 			offset++
-			for i := 0; i < m.Hidden1; i++ {
-				sum += m.h1[i] * m.Weights[offset]
+			for i := 0; i < hidden1; i++ {
+				sum += h1[i] * weights[offset]
 				offset++
 			}
-			m.h2[j] = relu(sum)
+			h2[j] = relu(sum)
 		}
-		lastHidden = m.h2
+		lastHidden = h2
 	} else {
-		lastHidden = m.h1
+		lastHidden = h1
 	}
 
 	// Last hidden -> Output
 	hiddenSize := len(lastHidden)
-	for j := 0; j < m.OutputSize; j++ {
-		sum := m.Weights[offset] // bias
+	for j := 0; j < outputSize; j++ {
+		sum := weights[offset] // bias
 		offset++
 		for i := 0; i < hiddenSize; i++ {
-			sum += lastHidden[i] * m.Weights[offset]
+			sum += lastHidden[i] * weights[offset]
 			offset++
 		}
-		m.out[j] = sum // no activation on output
+		out[j] = sum // no activation on output
 	}
+}
 
-	// Return argmax
-	return argmax(m.out)
+// Outputs returns the raw output values of the last Forward call.
+// The slice is an internal buffer and is overwritten by the next call.
+func (m *MLP) Outputs() []float32 {
+	return m.out
 }
 
 // ForwardRaw performs forward pass and returns raw output values
@@ -161,3 +200,16 @@ func CloneGenome(src []float32) []float32 {
 	return dst
 }
 
+// HashGenome returns a 128-bit FNV-1a hash of the genome's weights; equal
+// genomes hash equal
+func HashGenome(genome []float32) [16]byte {
+	h := fnv.New128a()
+	var buf [4]byte
+	for _, w := range genome {
+		binary.LittleEndian.PutUint32(buf[:], math.Float32bits(w))
+		h.Write(buf[:])
+	}
+	var sum [16]byte
+	h.Sum(sum[:0])
+	return sum
+}
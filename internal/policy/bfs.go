@@ -0,0 +1,176 @@
+package policy
+
+import (
+	"snakeai/internal/env"
+)
+
+// BFS follows a shortest path to the fruit when, after eating it, the head
+// could still reach the tail. Otherwise it chases its tail, and as a last
+// resort takes the safe move with the most reachable space. A BFS is not
+// safe for concurrent use.
+type BFS struct {
+	body    []env.Point
+	virtual []env.Point
+	blocked []bool
+	prev    []int
+	queue   []int
+}
+
+// NewBFS creates a shortest-path policy
+func NewBFS() *BFS {
+	return &BFS{}
+}
+
+// Name returns the policy name
+func (b *BFS) Name() string { return "bfs" }
+
+// Act chooses the next action
+func (b *BFS) Act(g *env.Game) env.Action {
+	b.body = g.Body(b.body[:0])
+
+	if g.FruitEnabled {
+		if path := b.path(g.Width, g.Height, b.body, g.Fruit); path != nil && b.tailReachable(g, path) {
+			if a, ok := stepAction(g, path[0]); ok {
+				return a
+			}
+		}
+	}
+
+	if len(b.body) > 1 {
+		if path := b.path(g.Width, g.Height, b.body, b.body[len(b.body)-1]); path != nil {
+			if a, ok := stepAction(g, path[0]); ok {
+				return a
+			}
+		}
+	}
+
+	return b.roomiest(g)
+}
+
+// tailReachable simulates following path to the fruit and reports whether
+// the grown snake's head then still has a path to its tail
+func (b *BFS) tailReachable(g *env.Game, path []env.Point) bool {
+	b.virtual = b.virtual[:0]
+	for i := len(path) - 1; i >= 0; i-- {
+		b.virtual = append(b.virtual, path[i])
+	}
+	b.virtual = append(b.virtual, b.body...)
+	if n := len(b.body) + 1; len(b.virtual) > n {
+		b.virtual = b.virtual[:n]
+	}
+	return b.path(g.Width, g.Height, b.virtual, b.virtual[len(b.virtual)-1]) != nil
+}
+
+// path returns the cells of a shortest path from body[0] to target,
+// excluding the start, avoiding every body cell except the tail. It returns
+// nil if the target is unreachable.
+func (b *BFS) path(w, h int, body []env.Point, target env.Point) []env.Point {
+	b.reset(w, h, body)
+	start := cellIndex(body[0], w)
+	goal := cellIndex(target, w)
+	b.prev[start] = start
+	b.queue = append(b.queue[:0], start)
+
+	for qi := 0; qi < len(b.queue); qi++ {
+		cur := b.queue[qi]
+		if cur == goal {
+			var path []env.Point
+			for c := cur; c != start; c = b.prev[c] {
+				path = append(path, env.Point{X: c % w, Y: c / w})
+			}
+			for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+				path[i], path[j] = path[j], path[i]
+			}
+			return path
+		}
+		b.expand(w, h, cur)
+	}
+	return nil
+}
+
+// roomiest returns the safe action whose target cell reaches the most free
+// cells, or straight if every action collides
+func (b *BFS) roomiest(g *env.Game) env.Action {
+	best, bestRoom := env.ActionStraight, -1
+	for _, a := range relativeActions {
+		if g.IsDanger(a) {
+			continue
+		}
+		if room := b.flood(g.Width, g.Height, b.body, g.NextHead(a)); room > bestRoom {
+			best, bestRoom = a, room
+		}
+	}
+	return best
+}
+
+// flood counts the cells reachable from p without crossing the body
+func (b *BFS) flood(w, h int, body []env.Point, p env.Point) int {
+	b.reset(w, h, body)
+	start := cellIndex(p, w)
+	b.prev[start] = start
+	b.queue = append(b.queue[:0], start)
+	for qi := 0; qi < len(b.queue); qi++ {
+		b.expand(w, h, b.queue[qi])
+	}
+	return len(b.queue)
+}
+
+// reset sizes the search buffers and blocks every body cell but the tail
+func (b *BFS) reset(w, h int, body []env.Point) {
+	if len(b.blocked) != w*h {
+		b.blocked = make([]bool, w*h)
+		b.prev = make([]int, w*h)
+	}
+	for i := range b.blocked {
+		b.blocked[i] = false
+		b.prev[i] = -1
+	}
+	for _, p := range body[:len(body)-1] {
+		b.blocked[cellIndex(p, w)] = true
+	}
+}
+
+// expand queues the unvisited, unblocked neighbours of cell cur
+func (b *BFS) expand(w, h, cur int) {
+	x, y := cur%w, cur/w
+	neighbours := [4][2]int{{x, y - 1}, {x + 1, y}, {x, y + 1}, {x - 1, y}}
+	for _, n := range neighbours {
+		if n[0] < 0 || n[0] >= w || n[1] < 0 || n[1] >= h {
+			continue
+		}
+		next := n[1]*w + n[0]
+		if b.blocked[next] || b.prev[next] >= 0 {
+			continue
+		}
+		b.prev[next] = cur
+		b.queue = append(b.queue, next)
+	}
+}
+
+// stepAction returns the action moving the head onto the adjacent cell p,
+// if that move is legal and does not collide
+func stepAction(g *env.Game, p env.Point) (env.Action, bool) {
+	head := g.Head()
+	var dir env.Direction
+	switch {
+	case p.X == head.X && p.Y == head.Y-1:
+		dir = env.DirUp
+	case p.X == head.X+1 && p.Y == head.Y:
+		dir = env.DirRight
+	case p.X == head.X && p.Y == head.Y+1:
+		dir = env.DirDown
+	case p.X == head.X-1 && p.Y == head.Y:
+		dir = env.DirLeft
+	default:
+		return env.ActionStraight, false
+	}
+	a, ok := g.ActionFor(dir)
+	if !ok || g.IsDanger(a) {
+		return a, false
+	}
+	return a, true
+}
+
+func cellIndex(p env.Point, w int) int {
+	return p.Y*w + p.X
+}
@@ -0,0 +1,59 @@
+package policy
+
+import (
+	"math/rand"
+
+	"snakeai/internal/env"
+	"snakeai/internal/nn"
+)
+
+// Network plays a policy network, such as a saved champion, greedily on its
+// feature observations
+type Network struct {
+	name     string
+	mlp      *nn.MLP
+	features *env.FeatureExtractor
+}
+
+// NewNetwork wraps a genome for the given observation type and hidden sizes
+func NewNetwork(name string, genome []float32, obsType string, hidden1, hidden2 int) *Network {
+	mlp := nn.NewMLP(env.ObsDim(obsType), hidden1, hidden2, 3)
+	mlp.SetWeights(genome)
+	return &Network{
+		name:     name,
+		mlp:      mlp,
+		features: env.NewFeatureExtractor(obsType),
+	}
+}
+
+// Name returns the policy name
+func (n *Network) Name() string { return n.name }
+
+// Act chooses the next action
+func (n *Network) Act(g *env.Game) env.Action {
+	return env.Action(n.mlp.Forward(n.features.Extract(g)))
+}
+
+// Random picks uniformly among the three actions, ignoring danger. It is
+// reseeded from each episode seed.
+type Random struct {
+	rng *rand.Rand
+}
+
+// NewRandom creates a uniform random policy
+func NewRandom() *Random {
+	return &Random{rng: rand.New(rand.NewSource(0))}
+}
+
+// Name returns the policy name
+func (r *Random) Name() string { return "random" }
+
+// Reset reseeds the policy for a new episode
+func (r *Random) Reset(seed uint32) {
+	r.rng.Seed(int64(seed))
+}
+
+// Act chooses the next action
+func (r *Random) Act(g *env.Game) env.Action {
+	return relativeActions[r.rng.Intn(len(relativeActions))]
+}
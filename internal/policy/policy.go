@@ -0,0 +1,68 @@
+// Package policy provides snake controllers behind one interface: trained
+// networks and hand-written baselines acting on the same relative action
+// space. The scripted ones serve as demonstrators for behaviour cloning and
+// as reference points for benchmarks.
+package policy
+
+import (
+	"fmt"
+	"strings"
+
+	"snakeai/internal/env"
+)
+
+// Policy chooses a relative action for the current game state
+type Policy interface {
+	Name() string
+	Act(g *env.Game) env.Action
+}
+
+// Resetter is implemented by policies with per-episode state. Reset is
+// called with the episode seed before the first action.
+type Resetter interface {
+	Reset(seed uint32)
+}
+
+// ScriptedNames lists the scripted policies available through New
+var ScriptedNames = []string{"random", "wall", "greedy", "bfs", "hamiltonian"}
+
+// New returns the scripted policy with the given name
+func New(name string) (Policy, error) {
+	switch name {
+	case "random":
+		return NewRandom(), nil
+	case "wall":
+		return WallFollower{}, nil
+	case "greedy":
+		return Greedy{}, nil
+	case "bfs":
+		return NewBFS(), nil
+	case "hamiltonian":
+		return NewHamiltonian(), nil
+	}
+	return nil, fmt.Errorf("policy: unknown policy %q (want one of %s)", name, strings.Join(ScriptedNames, ", "))
+}
+
+// Reset prepares p for an episode if it keeps per-episode state
+func Reset(p Policy, seed uint32) {
+	if r, ok := p.(Resetter); ok {
+		r.Reset(seed)
+	}
+}
+
+// relativeActions lists actions in tie-break order
+var relativeActions = []env.Action{env.ActionStraight, env.ActionLeft, env.ActionRight}
+
+// safeAction returns preferred if it does not collide, otherwise the first
+// action that does not, otherwise preferred
+func safeAction(g *env.Game, preferred env.Action) env.Action {
+	if !g.IsDanger(preferred) {
+		return preferred
+	}
+	for _, a := range relativeActions {
+		if !g.IsDanger(a) {
+			return a
+		}
+	}
+	return preferred
+}
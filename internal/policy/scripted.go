@@ -0,0 +1,162 @@
+package policy
+
+import (
+	"snakeai/internal/env"
+)
+
+// WallFollower goes straight and turns right, or else left, when a wall is
+// ahead. It ignores the body and the fruit.
+type WallFollower struct{}
+
+// Name returns the policy name
+func (WallFollower) Name() string { return "wall" }
+
+// Act chooses the next action
+func (WallFollower) Act(g *env.Game) env.Action {
+	if !g.IsDangerWall(env.ActionStraight) {
+		return env.ActionStraight
+	}
+	if !g.IsDangerWall(env.ActionRight) {
+		return env.ActionRight
+	}
+	if !g.IsDangerWall(env.ActionLeft) {
+		return env.ActionLeft
+	}
+	return env.ActionStraight
+}
+
+// Greedy moves to the safe neighbouring cell closest to the fruit by
+// Manhattan distance, with no lookahead
+type Greedy struct{}
+
+// Name returns the policy name
+func (Greedy) Name() string { return "greedy" }
+
+// Act chooses the next action
+func (Greedy) Act(g *env.Game) env.Action {
+	best := env.ActionStraight
+	bestDist := -1
+	for _, a := range relativeActions {
+		if g.IsDanger(a) {
+			continue
+		}
+		d := manhattan(g.NextHead(a), g.Fruit)
+		if bestDist < 0 || d < bestDist {
+			best, bestDist = a, d
+		}
+	}
+	return best
+}
+
+func manhattan(a, b env.Point) int {
+	dx, dy := a.X-b.X, a.Y-b.Y
+	if dx < 0 {
+		dx = -dx
+	}
+	if dy < 0 {
+		dy = -dy
+	}
+	return dx + dy
+}
+
+// Hamiltonian follows a fixed cycle through every board cell, which never
+// collides once the snake is on it. Boards with both sides odd have no such
+// cycle; there it falls back to Greedy. A Hamiltonian is not safe for
+// concurrent use.
+type Hamiltonian struct {
+	cycles map[env.Point][]env.Direction // per board size: direction to leave each cell
+}
+
+// NewHamiltonian creates a Hamiltonian-cycle policy
+func NewHamiltonian() *Hamiltonian {
+	return &Hamiltonian{cycles: make(map[env.Point][]env.Direction)}
+}
+
+// Name returns the policy name
+func (h *Hamiltonian) Name() string { return "hamiltonian" }
+
+// Act chooses the next action
+func (h *Hamiltonian) Act(g *env.Game) env.Action {
+	cycle := h.cycle(g.Width, g.Height)
+	if cycle == nil {
+		return Greedy{}.Act(g)
+	}
+	head := g.Head()
+	action, ok := g.ActionFor(cycle[head.Y*g.Width+head.X])
+	if !ok {
+		// Off-cycle start facing against the cycle direction
+		return safeAction(g, env.ActionRight)
+	}
+	return safeAction(g, action)
+}
+
+// cycle returns the exit direction of every cell on a Hamiltonian cycle of
+// a w x h board, or nil if none exists
+func (h *Hamiltonian) cycle(w, hgt int) []env.Direction {
+	key := env.Point{X: w, Y: hgt}
+	if c, ok := h.cycles[key]; ok {
+		return c
+	}
+	var c []env.Direction
+	switch {
+	case hgt%2 == 0 && w > 1:
+		c = rowCycle(w, hgt)
+	case w%2 == 0 && hgt > 1:
+		// Build on the transposed board and swap axes back
+		t := rowCycle(hgt, w)
+		c = make([]env.Direction, w*hgt)
+		for y := 0; y < hgt; y++ {
+			for x := 0; x < w; x++ {
+				c[y*w+x] = transpose(t[x*hgt+y])
+			}
+		}
+	}
+	h.cycles[key] = c
+	return c
+}
+
+// rowCycle builds a cycle for an even number of rows: right along row 0,
+// then zigzag through columns 1..w-1 of the remaining rows and back up
+// column 0
+func rowCycle(w, h int) []env.Direction {
+	c := make([]env.Direction, w*h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var d env.Direction
+			switch {
+			case x == 0 && y > 0:
+				d = env.DirUp
+			case y == 0:
+				d = env.DirRight
+				if x == w-1 {
+					d = env.DirDown
+				}
+			case y%2 == 1: // leftward rows
+				d = env.DirLeft
+				if x == 1 && y < h-1 {
+					d = env.DirDown
+				}
+			default: // rightward rows
+				d = env.DirRight
+				if x == w-1 {
+					d = env.DirDown
+				}
+			}
+			c[y*w+x] = d
+		}
+	}
+	return c
+}
+
+// transpose mirrors a direction across the main diagonal
+func transpose(d env.Direction) env.Direction {
+	switch d {
+	case env.DirUp:
+		return env.DirLeft
+	case env.DirLeft:
+		return env.DirUp
+	case env.DirDown:
+		return env.DirRight
+	}
+	return env.DirDown
+}
@@ -0,0 +1,221 @@
+package report
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"strings"
+)
+
+// Chart geometry in SVG user units
+const (
+	chartWidth  = 720
+	chartHeight = 280
+	marginLeft  = 64
+	marginRight = 16
+	marginTop   = 32
+	marginBot   = 36
+)
+
+// groupColors tell groups apart in every chart, in group order
+var groupColors = []string{"#1f6feb", "#d1242f", "#1a7f37", "#8250df", "#bf8700", "#0a7b83", "#cf222e", "#57606a"}
+
+// deathColors match the live dashboard
+var deathColors = map[string]string{
+	"wall":    "#f85149",
+	"self":    "#d2a8ff",
+	"stall":   "#e3b341",
+	"timeout": "#8b949e",
+}
+
+// series is one line of a chart
+type series struct {
+	name  string
+	color string
+	x, y  []float64
+}
+
+// groupColor returns the colour of the i-th group
+func groupColor(i int) string {
+	return groupColors[i%len(groupColors)]
+}
+
+// plotArea maps data coordinates into the chart's plot area
+type plotArea struct {
+	xMin, xMax, yMin, yMax float64
+}
+
+func (p plotArea) px(x float64) float64 {
+	return marginLeft + (x-p.xMin)/(p.xMax-p.xMin)*(chartWidth-marginLeft-marginRight)
+}
+
+func (p plotArea) py(y float64) float64 {
+	return marginTop + (1-(y-p.yMin)/(p.yMax-p.yMin))*(chartHeight-marginTop-marginBot)
+}
+
+// lineChart draws series sharing axes as a standalone SVG document
+func lineChart(title string, ss []series) string {
+	area := plotArea{math.Inf(1), math.Inf(-1), math.Inf(1), math.Inf(-1)}
+	for _, s := range ss {
+		for i := range s.x {
+			if math.IsNaN(s.y[i]) {
+				continue
+			}
+			area.xMin, area.xMax = math.Min(area.xMin, s.x[i]), math.Max(area.xMax, s.x[i])
+			area.yMin, area.yMax = math.Min(area.yMin, s.y[i]), math.Max(area.yMax, s.y[i])
+		}
+	}
+	var b strings.Builder
+	openChart(&b, title)
+	if math.IsInf(area.xMin, 1) {
+		fmt.Fprintf(&b, `<text x="%d" y="%d" fill="#57606a">no data</text>`, marginLeft, marginTop+20)
+		b.WriteString("</svg>")
+		return b.String()
+	}
+	area = padArea(area)
+	axes(&b, area)
+
+	for _, s := range ss {
+		var path strings.Builder
+		pen := false
+		for i := range s.x {
+			if math.IsNaN(s.y[i]) {
+				pen = false
+				continue
+			}
+			cmd := 'L'
+			if !pen {
+				cmd = 'M'
+			}
+			fmt.Fprintf(&path, "%c%.1f %.1f", cmd, area.px(s.x[i]), area.py(s.y[i]))
+			pen = true
+		}
+		fmt.Fprintf(&b, `<path d="%s" fill="none" stroke="%s" stroke-width="1.5"/>`, path.String(), s.color)
+		// A lone point would otherwise be invisible
+		if len(s.x) == 1 {
+			fmt.Fprintf(&b, `<circle cx="%.1f" cy="%.1f" r="3" fill="%s"/>`, area.px(s.x[0]), area.py(s.y[0]), s.color)
+		}
+	}
+	legend(&b, ss)
+	b.WriteString("</svg>")
+	return b.String()
+}
+
+// stackedArea draws layers stacked on top of each other over shared x
+// values, for shares that sum to one
+func stackedArea(title string, x []float64, layers []series) string {
+	var b strings.Builder
+	openChart(&b, title)
+	if len(x) == 0 {
+		fmt.Fprintf(&b, `<text x="%d" y="%d" fill="#57606a">no data</text>`, marginLeft, marginTop+20)
+		b.WriteString("</svg>")
+		return b.String()
+	}
+	area := plotArea{x[0], x[len(x)-1], 0, 1}
+	if area.xMax == area.xMin {
+		area.xMax = area.xMin + 1
+	}
+	axes(&b, area)
+
+	lower := make([]float64, len(x))
+	for _, l := range layers {
+		upper := make([]float64, len(x))
+		var pts strings.Builder
+		for i := range x {
+			upper[i] = lower[i] + l.y[i]
+			fmt.Fprintf(&pts, "%.1f,%.1f ", area.px(x[i]), area.py(upper[i]))
+		}
+		for i := len(x) - 1; i >= 0; i-- {
+			fmt.Fprintf(&pts, "%.1f,%.1f ", area.px(x[i]), area.py(lower[i]))
+		}
+		fmt.Fprintf(&b, `<polygon points="%s" fill="%s" fill-opacity="0.85"/>`, strings.TrimSpace(pts.String()), l.color)
+		lower = upper
+	}
+	legend(&b, layers)
+	b.WriteString("</svg>")
+	return b.String()
+}
+
+func openChart(b *strings.Builder, title string) {
+	fmt.Fprintf(b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="11">`,
+		chartWidth, chartHeight, chartWidth, chartHeight)
+	fmt.Fprintf(b, `<rect width="%d" height="%d" fill="#ffffff"/>`, chartWidth, chartHeight)
+	fmt.Fprintf(b, `<text x="%d" y="16" font-size="13" font-weight="bold" fill="#1f2328">%s</text>`, marginLeft, html.EscapeString(title))
+}
+
+// padArea widens degenerate ranges and adds headroom above and below
+func padArea(a plotArea) plotArea {
+	if a.xMax == a.xMin {
+		a.xMax = a.xMin + 1
+	}
+	if a.yMax == a.yMin {
+		a.yMin--
+		a.yMax++
+	}
+	pad := (a.yMax - a.yMin) * 0.05
+	a.yMin -= pad
+	a.yMax += pad
+	return a
+}
+
+// axes draws the horizontal grid with y labels and the x range
+func axes(b *strings.Builder, a plotArea) {
+	for _, y := range niceTicks(a.yMin, a.yMax, 5) {
+		py := a.py(y)
+		fmt.Fprintf(b, `<line x1="%d" y1="%.1f" x2="%d" y2="%.1f" stroke="#d0d7de"/>`, marginLeft, py, chartWidth-marginRight, py)
+		fmt.Fprintf(b, `<text x="%d" y="%.1f" text-anchor="end" fill="#57606a">%s</text>`, marginLeft-6, py+4, formatNumber(y))
+	}
+	for _, x := range niceTicks(a.xMin, a.xMax, 8) {
+		fmt.Fprintf(b, `<text x="%.1f" y="%d" text-anchor="middle" fill="#57606a">%s</text>`, a.px(x), chartHeight-marginBot+16, formatNumber(x))
+	}
+	fmt.Fprintf(b, `<text x="%d" y="%d" text-anchor="middle" fill="#57606a">generation</text>`,
+		(marginLeft+chartWidth-marginRight)/2, chartHeight-6)
+}
+
+// legend lists series names along the top right
+func legend(b *strings.Builder, ss []series) {
+	x := chartWidth - marginRight
+	for i := len(ss) - 1; i >= 0; i-- {
+		x -= 7*len(ss[i].name) + 26
+		fmt.Fprintf(b, `<rect x="%d" y="8" width="10" height="10" fill="%s"/>`, x, ss[i].color)
+		fmt.Fprintf(b, `<text x="%d" y="17" fill="#1f2328">%s</text>`, x+14, html.EscapeString(ss[i].name))
+	}
+}
+
+// niceTicks returns round values covering [lo, hi] with about n steps
+func niceTicks(lo, hi float64, n int) []float64 {
+	span := hi - lo
+	if span <= 0 {
+		return []float64{lo}
+	}
+	step := math.Pow(10, math.Floor(math.Log10(span/float64(n))))
+	for _, m := range []float64{1, 2, 5, 10} {
+		if span/(step*m) <= float64(n) {
+			step *= m
+			break
+		}
+	}
+	var ticks []float64
+	first := math.Ceil(lo / step)
+	for i := 0.0; (first+i)*step <= hi+step*1e-9; i++ {
+		// Multiplying rather than accumulating keeps 0 and round values exact
+		ticks = append(ticks, (first+i)*step)
+	}
+	return ticks
+}
+
+// formatNumber renders axis and table values compactly
+func formatNumber(v float64) string {
+	a := math.Abs(v)
+	switch {
+	case math.IsNaN(v):
+		return "–"
+	case a >= 1e6:
+		return fmt.Sprintf("%.1fM", v/1e6)
+	case a >= 1e4:
+		return fmt.Sprintf("%.1fk", v/1e3)
+	case a >= 100 || v == math.Trunc(v):
+		return fmt.Sprintf("%.0f", v)
+	}
+	return fmt.Sprintf("%.3g", v)
+}
@@ -0,0 +1,352 @@
+package report
+
+import (
+	"bufio"
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"io"
+	"math"
+	"sort"
+	"strings"
+
+	"snakeai/internal/env"
+	"snakeai/internal/logging"
+)
+
+// Report is a comparison of run groups, laid out as sections that render
+// to HTML or Markdown
+type Report struct {
+	Title    string
+	Sections []Section
+}
+
+// Section is a titled part of the report
+type Section struct {
+	Title  string
+	Text   string
+	Tables []Table
+	Charts []Chart
+}
+
+// Table is a header row and data rows of preformatted cells
+type Table struct {
+	Header []string
+	Rows   [][]string
+}
+
+// Chart is a titled standalone SVG document
+type Chart struct {
+	Title string
+	SVG   string
+}
+
+// Build compares groups; the first group is the baseline for significance
+// tests on the final value of metric
+func Build(groups []*Group, metric string) (*Report, error) {
+	m, ok := Metrics[metric]
+	if !ok {
+		return nil, fmt.Errorf("unknown metric %q (want one of %s)", metric, strings.Join(MetricNames(), ", "))
+	}
+	if len(groups) == 0 {
+		return nil, fmt.Errorf("no runs to report")
+	}
+	r := &Report{Title: "Training run report"}
+	r.Sections = append(r.Sections,
+		overview(groups),
+		curves(groups),
+		deaths(groups),
+		benchmarks(groups),
+		hyperparameters(groups),
+		significance(groups, metric, m))
+	return r, nil
+}
+
+// overview tabulates each group's final results
+func overview(groups []*Group) Section {
+	t := Table{Header: []string{"Group", "Runs", "Generations", "Best fitness", "Mean fitness", "Robust score", "Benchmark ticks", "Benchmark fruits"}}
+	for _, g := range groups {
+		row := []string{g.Name, fmt.Sprint(len(g.Runs)), fmt.Sprint(g.Generations())}
+		for _, name := range []string{"best_fitness", "mean_fitness", "robust_score", "benchmark_ticks", "benchmark_fruits"} {
+			row = append(row, formatMeanStd(g.Finals(Metrics[name])))
+		}
+		t.Rows = append(t.Rows, row)
+	}
+	return Section{
+		Title:  "Overview",
+		Text:   "Final values of each run, as mean ± standard deviation across the runs of a group.",
+		Tables: []Table{t},
+	}
+}
+
+// curves plots learning curves averaged across each group's runs
+func curves(groups []*Group) Section {
+	s := Section{Title: "Learning curves", Text: "Each line is the mean across the group's runs at every logged generation."}
+	for _, c := range []struct{ title, metric string }{
+		{"Best fitness", "best_fitness"},
+		{"Mean fitness", "mean_fitness"},
+		{"Mean fruits", "mean_fruits"},
+		{"Robust score", "robust_score"},
+	} {
+		s.Charts = append(s.Charts, Chart{c.title, lineChart(c.title, groupSeries(groups, Metrics[c.metric]))})
+	}
+	return s
+}
+
+// deaths draws each group's share of deaths by reason over generations
+func deaths(groups []*Group) Section {
+	s := Section{Title: "Death reasons", Text: "Share of episodes ending in each way, averaged across the group's runs."}
+	reasons := []env.DeathReason{env.DeathWall, env.DeathSelf, env.DeathStall, env.DeathTimeout}
+	for _, g := range groups {
+		var x []float64
+		var layers []series
+		for _, reason := range reasons {
+			name := reason.String()
+			gens, share := g.Curve(func(s logging.GenerationSummary) (float64, bool) {
+				total := 0
+				for _, n := range s.DeathCounts {
+					total += n
+				}
+				if total == 0 {
+					return 0, false
+				}
+				return float64(s.DeathCounts[name]) / float64(total), true
+			})
+			x = gens
+			layers = append(layers, series{name: name, color: deathColors[name], x: gens, y: share})
+		}
+		title := "Deaths: " + g.Name
+		s.Charts = append(s.Charts, Chart{title, stackedArea(title, x, layers)})
+	}
+	return s
+}
+
+// benchmarks plots benchmark results in the generations that ran them
+func benchmarks(groups []*Group) Section {
+	s := Section{Title: "Benchmark trajectories"}
+	ticks := groupSeries(groups, Metrics["benchmark_ticks"])
+	found := false
+	for _, t := range ticks {
+		found = found || len(t.x) > 0
+	}
+	if !found {
+		s.Text = "No run logged benchmark results."
+		return s
+	}
+	s.Text = "Mean results on the fixed benchmark seeds, averaged across the group's runs."
+	s.Charts = []Chart{
+		{"Benchmark ticks", lineChart("Benchmark ticks", ticks)},
+		{"Benchmark fruits", lineChart("Benchmark fruits", groupSeries(groups, Metrics["benchmark_fruits"]))},
+	}
+	return s
+}
+
+// groupSeries returns one averaged curve per group
+func groupSeries(groups []*Group, m Metric) []series {
+	ss := make([]series, len(groups))
+	for i, g := range groups {
+		x, y := g.Curve(m)
+		ss[i] = series{name: g.Name, color: groupColor(i), x: x, y: y}
+	}
+	return ss
+}
+
+// hyperparameters lists the config keys whose values differ between runs,
+// from the snapshots cmd/train saves beside its logs. Logging settings are
+// left out since they do not affect training.
+func hyperparameters(groups []*Group) Section {
+	s := Section{Title: "Hyperparameter differences"}
+	var names []string
+	var configs []map[string]string
+	keys := make(map[string]bool)
+	for _, g := range groups {
+		for _, r := range g.Runs {
+			if r.Config == nil {
+				continue
+			}
+			name := g.Name
+			if len(g.Runs) > 1 {
+				name += "/" + r.Name
+			}
+			names = append(names, name)
+			configs = append(configs, r.Config)
+			for k := range r.Config {
+				if !strings.HasPrefix(k, "logging.") {
+					keys[k] = true
+				}
+			}
+		}
+	}
+	if len(configs) < 2 {
+		s.Text = "Fewer than two runs have a config snapshot (`<log>.config.yaml`), so there is nothing to compare."
+		return s
+	}
+
+	t := Table{Header: append([]string{"Key"}, names...)}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+	for _, k := range sorted {
+		row := []string{k}
+		differs := false
+		for i, c := range configs {
+			v, ok := c[k]
+			if !ok {
+				v = "–"
+			}
+			row = append(row, v)
+			differs = differs || (i > 0 && v != row[1])
+		}
+		if differs {
+			t.Rows = append(t.Rows, row)
+		}
+	}
+	if len(t.Rows) == 0 {
+		s.Text = fmt.Sprintf("All %d runs with a config snapshot used identical settings.", len(configs))
+		return s
+	}
+	s.Text = fmt.Sprintf("Settings that differ between the %d runs with a config snapshot.", len(configs))
+	s.Tables = []Table{t}
+	return s
+}
+
+// significance compares each group's final metric against the baseline
+func significance(groups []*Group, metric string, m Metric) Section {
+	s := Section{
+		Title: "Final " + metric,
+		Text: fmt.Sprintf("Two-sided Mann-Whitney U tests of each group's final %s against the baseline %q, one value per run. "+
+			"A12 is the probability that a run of the group beats a baseline run (0.5 = no effect).", metric, groups[0].Name),
+	}
+	base := groups[0].Finals(m)
+	t := Table{Header: []string{"Group", "n", "Mean ± std", "Median", "U", "p", "A12", "Test"}}
+	for i, g := range groups {
+		xs := g.Finals(m)
+		row := []string{g.Name, fmt.Sprint(len(xs)), formatMeanStd(xs), formatNumber(median(xs))}
+		if i == 0 {
+			row = append(row, "baseline", "", "", "")
+		} else if len(xs) == 0 || len(base) == 0 {
+			row = append(row, "–", "–", "–", "no data")
+		} else {
+			mw := MannWhitneyU(xs, base)
+			test := "normal approx."
+			if mw.Exact {
+				test = "exact"
+			}
+			row = append(row, formatNumber(mw.U), formatP(mw.P), fmt.Sprintf("%.2f", mw.A12), test)
+		}
+		t.Rows = append(t.Rows, row)
+	}
+	s.Tables = []Table{t}
+	return s
+}
+
+func formatMeanStd(xs []float64) string {
+	if len(xs) == 0 {
+		return "–"
+	}
+	mean, std := meanStd(xs)
+	if len(xs) == 1 {
+		return formatNumber(mean)
+	}
+	return formatNumber(mean) + " ± " + formatNumber(std)
+}
+
+func formatP(p float64) string {
+	switch {
+	case math.IsNaN(p):
+		return "–"
+	case p < 0.001:
+		return "<0.001"
+	}
+	return fmt.Sprintf("%.3f", p)
+}
+
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", sans-serif; color: #1f2328; max-width: 960px; margin: 2em auto; padding: 0 1em; }
+h1 { font-size: 1.6em; } h2 { font-size: 1.25em; border-bottom: 1px solid #d0d7de; padding-bottom: .3em; margin-top: 2em; }
+table { border-collapse: collapse; margin: 1em 0; font-size: .9em; }
+th, td { border: 1px solid #d0d7de; padding: 4px 10px; text-align: left; }
+th { background: #f6f8fa; }
+td { font-variant-numeric: tabular-nums; }
+svg { display: block; max-width: 100%; height: auto; margin: 1em 0; border: 1px solid #d0d7de; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{range .Sections}}<h2>{{.Title}}</h2>
+{{if .Text}}<p>{{.Text}}</p>{{end}}
+{{range .Tables}}<table>
+<tr>{{range .Header}}<th>{{.}}</th>{{end}}</tr>
+{{range .Rows}}<tr>{{range .}}<td>{{.}}</td>{{end}}</tr>
+{{end}}</table>
+{{end}}{{range .Charts}}{{.SVG}}
+{{end}}{{end}}</body>
+</html>
+`))
+
+// WriteHTML renders the report as a single HTML page with inline SVG charts
+func (r *Report) WriteHTML(w io.Writer) error {
+	type chart struct{ SVG template.HTML }
+	type section struct {
+		Title, Text string
+		Tables      []Table
+		Charts      []chart
+	}
+	data := struct {
+		Title    string
+		Sections []section
+	}{Title: r.Title}
+	for _, s := range r.Sections {
+		sec := section{Title: s.Title, Text: s.Text, Tables: s.Tables}
+		for _, c := range s.Charts {
+			// Charts are generated here with every label escaped
+			sec.Charts = append(sec.Charts, chart{template.HTML(c.SVG)})
+		}
+		data.Sections = append(data.Sections, sec)
+	}
+	return htmlTemplate.Execute(w, data)
+}
+
+// WriteMarkdown renders the report as Markdown with charts embedded as
+// data URI images, so the file stands alone
+func (r *Report) WriteMarkdown(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "# %s\n", r.Title)
+	for _, s := range r.Sections {
+		fmt.Fprintf(bw, "\n## %s\n\n", s.Title)
+		if s.Text != "" {
+			fmt.Fprintf(bw, "%s\n\n", s.Text)
+		}
+		for _, t := range s.Tables {
+			writeMarkdownRow(bw, t.Header)
+			seps := make([]string, len(t.Header))
+			for i := range seps {
+				seps[i] = "---"
+			}
+			writeMarkdownRow(bw, seps)
+			for _, row := range t.Rows {
+				writeMarkdownRow(bw, row)
+			}
+			bw.WriteString("\n")
+		}
+		for _, c := range s.Charts {
+			fmt.Fprintf(bw, "![%s](data:image/svg+xml;base64,%s)\n\n", c.Title, base64.StdEncoding.EncodeToString([]byte(c.SVG)))
+		}
+	}
+	return bw.Flush()
+}
+
+func writeMarkdownRow(w *bufio.Writer, cells []string) {
+	w.WriteString("|")
+	for _, c := range cells {
+		fmt.Fprintf(w, " %s |", strings.ReplaceAll(c, "|", `\|`))
+	}
+	w.WriteString("\n")
+}
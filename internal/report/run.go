@@ -0,0 +1,244 @@
+// Package report compares training runs from their JSONL logs: learning
+// curves, death reasons, benchmark trajectories, hyperparameter differences
+// and significance tests on final performance, rendered as a self-contained
+// HTML or Markdown document.
+package report
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"snakeai/internal/logging"
+)
+
+// Run is one training run: its generation summaries and, if a snapshot was
+// saved beside the log, its flattened configuration
+type Run struct {
+	Name      string
+	Path      string
+	Summaries []logging.GenerationSummary
+	Config    map[string]string // dotted key -> value, nil without a snapshot
+}
+
+// Group is a set of runs of the same variant, typically different seeds
+type Group struct {
+	Name string
+	Runs []*Run
+}
+
+// LoadGroup loads a JSONL log, or every JSONL log below a directory, as one
+// group named name
+func LoadGroup(name, path string) (*Group, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	paths := []string{path}
+	if info.IsDir() {
+		paths = nil
+		err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err == nil && !d.IsDir() && filepath.Ext(p) == ".jsonl" {
+				paths = append(paths, p)
+			}
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(paths) == 0 {
+			return nil, fmt.Errorf("%s: no .jsonl logs found", path)
+		}
+	}
+	sort.Strings(paths)
+
+	g := &Group{Name: name}
+	for _, p := range paths {
+		run, err := loadRun(p)
+		if err != nil {
+			return nil, err
+		}
+		// Name runs by their path inside the group directory, dropping the
+		// file name -logdir gives every log
+		run.Name = strings.TrimSuffix(filepath.Base(p), ".jsonl")
+		if info.IsDir() {
+			if rel, err := filepath.Rel(path, p); err == nil {
+				run.Name = strings.TrimSuffix(rel, ".jsonl")
+				if dir, file := filepath.Split(run.Name); file == "run" && dir != "" {
+					run.Name = filepath.Clean(dir)
+				}
+			}
+		}
+		g.Runs = append(g.Runs, run)
+	}
+	return g, nil
+}
+
+// loadRun reads a JSONL log and its config snapshot, if any
+func loadRun(path string) (*Run, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	run := &Run{Path: path}
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; sc.Scan(); line++ {
+		if len(strings.TrimSpace(sc.Text())) == 0 {
+			continue
+		}
+		var s logging.GenerationSummary
+		if err := json.Unmarshal(sc.Bytes(), &s); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		run.Summaries = append(run.Summaries, s)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if len(run.Summaries) == 0 {
+		return nil, fmt.Errorf("%s: empty log", path)
+	}
+
+	data, err := os.ReadFile(logging.ConfigSnapshotPath(path))
+	if err == nil {
+		var tree map[string]any
+		if err := yaml.Unmarshal(data, &tree); err != nil {
+			return nil, fmt.Errorf("%s: %w", logging.ConfigSnapshotPath(path), err)
+		}
+		run.Config = make(map[string]string)
+		flatten("", tree, run.Config)
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+	return run, nil
+}
+
+// flatten turns nested YAML maps into dotted keys. Lists are kept whole as
+// one value, since their elements rarely mean anything on their own.
+func flatten(prefix string, v any, out map[string]string) {
+	m, ok := v.(map[string]any)
+	if !ok {
+		if list, ok := v.([]any); ok {
+			b, _ := json.Marshal(list)
+			out[prefix] = string(b)
+			return
+		}
+		out[prefix] = fmt.Sprint(v)
+		return
+	}
+	for k, child := range m {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		flatten(key, child, out)
+	}
+}
+
+// Metric extracts a value from a summary; ok is false when the summary does
+// not carry it
+type Metric func(s logging.GenerationSummary) (v float64, ok bool)
+
+// Metrics are the per-generation values a report can compare runs on
+var Metrics = map[string]Metric{
+	"best_fitness":     func(s logging.GenerationSummary) (float64, bool) { return s.BestFitness, true },
+	"mean_fitness":     func(s logging.GenerationSummary) (float64, bool) { return s.MeanFitness, true },
+	"best_fruits":      func(s logging.GenerationSummary) (float64, bool) { return float64(s.BestFruits), true },
+	"mean_fruits":      func(s logging.GenerationSummary) (float64, bool) { return s.MeanFruits, true },
+	"mean_ticks":       func(s logging.GenerationSummary) (float64, bool) { return s.MeanTicks, true },
+	"robust_score":     func(s logging.GenerationSummary) (float64, bool) { return optional(s.RobustScore) },
+	"benchmark_ticks":  func(s logging.GenerationSummary) (float64, bool) { return optional(s.BenchmarkTicks) },
+	"benchmark_fruits": benchmarkFruits,
+}
+
+// benchmarkFruits also reads logs written before a logged zero was kept,
+// which omit zero benchmark fruits
+func benchmarkFruits(s logging.GenerationSummary) (float64, bool) {
+	if s.BenchmarkFruits == nil && s.BenchmarkTicks != nil {
+		return 0, true
+	}
+	return optional(s.BenchmarkFruits)
+}
+
+// optional unpacks an optional summary value
+func optional(v *float64) (float64, bool) {
+	if v == nil {
+		return 0, false
+	}
+	return *v, true
+}
+
+// MetricNames lists the keys of Metrics in sorted order
+func MetricNames() []string {
+	names := make([]string, 0, len(Metrics))
+	for name := range Metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Final returns the last value of a metric in the run
+func (r *Run) Final(m Metric) (float64, bool) {
+	for i := len(r.Summaries) - 1; i >= 0; i-- {
+		if v, ok := m(r.Summaries[i]); ok {
+			return v, true
+		}
+	}
+	return 0, false
+}
+
+// Finals returns the final value of a metric for every run that has it
+func (g *Group) Finals(m Metric) []float64 {
+	var out []float64
+	for _, r := range g.Runs {
+		if v, ok := r.Final(m); ok {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// Generations returns the largest generation logged by any run
+func (g *Group) Generations() int {
+	n := 0
+	for _, r := range g.Runs {
+		n = max(n, r.Summaries[len(r.Summaries)-1].Generation)
+	}
+	return n
+}
+
+// Curve averages a metric across the group's runs at every logged
+// generation, skipping runs that lack the value there
+func (g *Group) Curve(m Metric) (gens, values []float64) {
+	sum := make(map[int]float64)
+	count := make(map[int]int)
+	for _, r := range g.Runs {
+		for _, s := range r.Summaries {
+			if v, ok := m(s); ok {
+				sum[s.Generation] += v
+				count[s.Generation]++
+			}
+		}
+	}
+	keys := make([]int, 0, len(sum))
+	for gen := range sum {
+		keys = append(keys, gen)
+	}
+	sort.Ints(keys)
+	for _, gen := range keys {
+		gens = append(gens, float64(gen))
+		values = append(values, sum[gen]/float64(count[gen]))
+	}
+	return gens, values
+}
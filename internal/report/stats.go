@@ -0,0 +1,160 @@
+package report
+
+import (
+	"math"
+	"sort"
+)
+
+// exactLimit is the largest sample size per side for which Mann-Whitney p
+// values are computed from the exact distribution of U
+const exactLimit = 20
+
+// MannWhitney is the result of a two-sided Mann-Whitney U test
+type MannWhitney struct {
+	U     float64 // U statistic of the first sample
+	P     float64 // two-sided p value
+	A12   float64 // probability that a value from the first sample beats one from the second (ties count half)
+	Exact bool    // P comes from the exact distribution rather than the normal approximation
+}
+
+// MannWhitneyU tests whether a and b come from the same distribution. Small
+// samples without ties use the exact distribution of U; otherwise the
+// normal approximation with tie and continuity corrections is used.
+func MannWhitneyU(a, b []float64) MannWhitney {
+	n1, n2 := len(a), len(b)
+	if n1 == 0 || n2 == 0 {
+		return MannWhitney{P: math.NaN(), A12: math.NaN()}
+	}
+
+	// Rank the pooled sample, averaging ranks across ties
+	type obs struct {
+		v     float64
+		first bool
+	}
+	pooled := make([]obs, 0, n1+n2)
+	for _, v := range a {
+		pooled = append(pooled, obs{v, true})
+	}
+	for _, v := range b {
+		pooled = append(pooled, obs{v, false})
+	}
+	sort.Slice(pooled, func(i, j int) bool { return pooled[i].v < pooled[j].v })
+
+	var rankSum, tieTerm float64
+	ties := false
+	for i := 0; i < len(pooled); {
+		j := i
+		for j < len(pooled) && pooled[j].v == pooled[i].v {
+			j++
+		}
+		rank := float64(i+j+1) / 2 // mean of ranks i+1..j
+		for k := i; k < j; k++ {
+			if pooled[k].first {
+				rankSum += rank
+			}
+		}
+		if t := float64(j - i); t > 1 {
+			ties = true
+			tieTerm += t*t*t - t
+		}
+		i = j
+	}
+
+	f1, f2 := float64(n1), float64(n2)
+	u := rankSum - f1*(f1+1)/2
+	res := MannWhitney{U: u, A12: u / (f1 * f2)}
+
+	if !ties && n1 <= exactLimit && n2 <= exactLimit {
+		res.P = exactP(n1, n2, int(math.Round(u)))
+		res.Exact = true
+		return res
+	}
+
+	n := f1 + f2
+	mean := f1 * f2 / 2
+	variance := f1 * f2 / 12 * ((n + 1) - tieTerm/(n*(n-1)))
+	if variance <= 0 {
+		// Every value is tied: no evidence of a difference
+		res.P = 1
+		return res
+	}
+	z := (math.Abs(u-mean) - 0.5) / math.Sqrt(variance)
+	res.P = math.Min(1, math.Erfc(math.Max(z, 0)/math.Sqrt2))
+	return res
+}
+
+// exactP returns the two-sided p value of U = u for samples of n1 and n2
+// from the exact null distribution, counted by the standard recursion over
+// which sample holds the largest value
+func exactP(n1, n2, u int) float64 {
+	// Counts of arrangements by U for samples of i and j, kept for the
+	// previous and current i
+	prev := make([][]float64, n2+1)
+	cur := make([][]float64, n2+1)
+	for i := 0; i <= n1; i++ {
+		for j := 0; j <= n2; j++ {
+			c := make([]float64, i*j+1)
+			if i == 0 || j == 0 {
+				c[0] = 1
+			} else {
+				// Largest value in the first sample: it beats all j others
+				for k, v := range prev[j] {
+					c[k+j] += v
+				}
+				// Largest value in the second sample
+				for k, v := range cur[j-1] {
+					c[k] += v
+				}
+			}
+			cur[j] = c
+		}
+		prev, cur = cur, make([][]float64, n2+1)
+	}
+	counts := prev[n2]
+
+	var total, lower, upper float64
+	for k, v := range counts {
+		total += v
+		if k <= u {
+			lower += v
+		}
+		if k >= u {
+			upper += v
+		}
+	}
+	return math.Min(1, 2*math.Min(lower, upper)/total)
+}
+
+// meanStd returns the mean and sample standard deviation of xs
+func meanStd(xs []float64) (float64, float64) {
+	if len(xs) == 0 {
+		return math.NaN(), math.NaN()
+	}
+	var sum float64
+	for _, x := range xs {
+		sum += x
+	}
+	mean := sum / float64(len(xs))
+	if len(xs) < 2 {
+		return mean, 0
+	}
+	var ss float64
+	for _, x := range xs {
+		ss += (x - mean) * (x - mean)
+	}
+	return mean, math.Sqrt(ss / float64(len(xs)-1))
+}
+
+// median returns the median of xs
+func median(xs []float64) float64 {
+	if len(xs) == 0 {
+		return math.NaN()
+	}
+	s := append([]float64(nil), xs...)
+	sort.Float64s(s)
+	m := len(s) / 2
+	if len(s)%2 == 1 {
+		return s[m]
+	}
+	return (s[m-1] + s[m]) / 2
+}
@@ -0,0 +1,65 @@
+package report
+
+import (
+	"math"
+	"testing"
+)
+
+func TestExactP(t *testing.T) {
+	cases := []struct {
+		n1, n2, u int
+		want      float64
+	}{
+		{3, 3, 0, 2.0 / 20},  // most extreme of C(6,3) arrangements, both tails
+		{3, 3, 9, 2.0 / 20},  // the other tail
+		{3, 3, 4, 1},         // middle of the distribution caps at 1
+		{4, 4, 0, 2.0 / 70},  // C(8,4)
+		{5, 5, 2, 8.0 / 252}, // U <= 2 in 1+1+2 of C(10,5) arrangements
+		{2, 5, 1, 4.0 / 21},  // unequal sizes: U <= 1 in 2 of C(7,2)
+	}
+	for _, c := range cases {
+		if got := exactP(c.n1, c.n2, c.u); math.Abs(got-c.want) > 1e-12 {
+			t.Errorf("exactP(%d, %d, %d) = %v, want %v", c.n1, c.n2, c.u, got, c.want)
+		}
+	}
+}
+
+func TestMannWhitneyUExact(t *testing.T) {
+	res := MannWhitneyU([]float64{1, 2, 3}, []float64{4, 5, 6})
+	if !res.Exact || res.U != 0 || res.A12 != 0 || math.Abs(res.P-0.1) > 1e-12 {
+		t.Errorf("got %+v, want exact U 0, A12 0, p 0.1", res)
+	}
+	res = MannWhitneyU([]float64{4, 5, 6}, []float64{1, 2, 3})
+	if res.U != 9 || res.A12 != 1 || math.Abs(res.P-0.1) > 1e-12 {
+		t.Errorf("swapped samples: got %+v, want U 9, A12 1, p 0.1", res)
+	}
+}
+
+func TestMannWhitneyUNormal(t *testing.T) {
+	// Ties force the normal approximation. Ranks of a are 1, 2, 4, 4 so
+	// U = 1; with tie correction the variance is 16/12 * (9 - 24/56) and
+	// z = (8 - 1 - 0.5) / sqrt(variance) = 1.9227, p = 0.05452
+	res := MannWhitneyU([]float64{1, 2, 3, 3}, []float64{3, 4, 5, 6})
+	if res.Exact || res.U != 1 || math.Abs(res.P-0.05452) > 5e-5 {
+		t.Errorf("got %+v, want approximate U 1, p 0.05452", res)
+	}
+
+	// Samples beyond the exact limit: U = 0 with mean 220.5 and standard
+	// deviation sqrt(441 * 43 / 12) = 39.75, so z = 5.5346 and p = 3.12e-8
+	a, b := make([]float64, 21), make([]float64, 21)
+	for i := range a {
+		a[i], b[i] = float64(i), float64(i+21)
+	}
+	res = MannWhitneyU(a, b)
+	if res.Exact || res.U != 0 || math.Abs(res.P-3.12e-8) > 0.01e-8 {
+		t.Errorf("got %+v, want approximate U 0, p 3.12e-8", res)
+	}
+
+	res = MannWhitneyU([]float64{2, 2, 2}, []float64{2, 2})
+	if res.Exact || res.P != 1 || res.A12 != 0.5 {
+		t.Errorf("all tied: got %+v, want p 1, A12 0.5", res)
+	}
+	if res = MannWhitneyU(nil, []float64{1}); !math.IsNaN(res.P) {
+		t.Errorf("empty sample: got p %v, want NaN", res.P)
+	}
+}
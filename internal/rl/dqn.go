@@ -0,0 +1,120 @@
+package rl
+
+import (
+	"snakeai/internal/env"
+	"snakeai/internal/eval"
+	"snakeai/internal/nn"
+)
+
+// iterateDQN plays episodes epsilon-greedily, storing transitions in the
+// replay buffer and taking a minibatch step every TrainEvery env steps. The
+// policy network's outputs are the Q-values of the three actions, so its
+// argmax is the greedy policy that play and the benchmarks run.
+func (t *Trainer) iterateDQN(n int) Update {
+	var u Update
+	rnd := eval.Randomization(t.cfg.Env.Randomize)
+	updates := 0
+
+	for ep := 0; ep < n; ep++ {
+		seed := uint32(t.cfg.Seed) + uint32(t.episodes)
+		t.episodes++
+		game := eval.NewGame(t.cfg.Env, rnd, seed)
+
+		for game.Alive {
+			copy(t.obsBuf, t.features.Extract(game))
+			action := t.policy.Forward(t.obsBuf)
+			if t.rng.Float64() < t.epsilon() {
+				action = t.rng.Intn(3)
+			}
+
+			res := game.Step(env.Action(action))
+			var next []float32
+			if !res.Done {
+				next = t.features.Extract(game)
+			}
+			t.replay.Add(t.obsBuf, action, res.Reward, next, res.Done)
+			t.envSteps++
+			u.Steps++
+
+			if t.envSteps >= int64(t.cfg.RL.LearningStarts) && t.envSteps%int64(t.cfg.RL.TrainEvery) == 0 {
+				u.ValueLoss += t.updateDQN()
+				updates++
+			}
+			if t.envSteps%int64(t.cfg.RL.TargetUpdate) == 0 {
+				t.target.SetWeights(t.policy.Weights)
+			}
+		}
+
+		stats := game.Stats(seed)
+		t.evaluator.Score(&stats)
+		u.Episodes = append(u.Episodes, stats)
+	}
+
+	if updates > 0 {
+		u.ValueLoss /= float64(updates)
+	}
+	u.Epsilon = t.epsilon()
+	return u
+}
+
+// updateDQN takes one gradient step on a replay minibatch and returns the
+// mean Huber TD loss
+func (t *Trainer) updateDQN() float64 {
+	t.replay.Sample(t.sampleIdx, t.rng)
+	gamma := t.cfg.RL.Gamma
+	scale := 1 / float64(len(t.sampleIdx))
+	loss := 0.0
+
+	for _, i := range t.sampleIdx {
+		target := t.replay.rewards[i]
+		if !t.replay.dones[i] {
+			next := t.replay.Next(i)
+			var q float32
+			if t.cfg.RL.DoubleDQN {
+				// Online network picks the action, target network values it
+				a := t.policy.Forward(next)
+				t.target.Forward(next)
+				q = t.target.Outputs()[a]
+			} else {
+				q = t.target.Outputs()[t.target.Forward(next)]
+			}
+			target += gamma * float64(q)
+		}
+
+		obs := t.replay.Observation(i)
+		action := t.replay.actions[i]
+		t.policy.Forward(obs)
+		diff := float64(t.policy.Outputs()[action]) - target
+
+		// Huber loss: quadratic within [-1, 1], linear outside
+		grad := diff
+		if diff > 1 {
+			grad = 1
+			loss += diff - 0.5
+		} else if diff < -1 {
+			grad = -1
+			loss += -diff - 0.5
+		} else {
+			loss += 0.5 * diff * diff
+		}
+
+		clear(t.dLogits)
+		t.dLogits[action] = float32(grad * scale)
+		t.policy.Backward(obs, t.dLogits, t.pGrad)
+	}
+
+	nn.ClipGradNorm(t.pGrad, t.cfg.RL.MaxGradNorm)
+	t.policyOpt.Step(t.policy.Weights, t.pGrad)
+	clear(t.pGrad)
+	return loss * scale
+}
+
+// epsilon returns the exploration rate, annealed linearly over env steps
+func (t *Trainer) epsilon() float64 {
+	start, end := t.cfg.RL.EpsilonStart, t.cfg.RL.EpsilonEnd
+	frac := float64(t.envSteps) / float64(t.cfg.RL.EpsilonDecay)
+	if frac > 1 {
+		frac = 1
+	}
+	return start + frac*(end-start)
+}
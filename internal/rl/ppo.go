@@ -0,0 +1,78 @@
+package rl
+
+import (
+	"math"
+)
+
+// updatePPO runs several epochs of clipped-surrogate minibatch updates on
+// one batch, with GAE advantages computed from the rollout value estimates
+func (t *Trainer) updatePPO(b *Batch) Update {
+	n := b.Len()
+	if n == 0 {
+		return Update{}
+	}
+
+	adv, returns := gae(b.Rewards, b.Values, b.Dones, t.cfg.RL.Gamma, t.cfg.RL.GAELambda)
+	normalize(adv)
+
+	eps := t.cfg.RL.ClipEps
+	mbSize := t.cfg.RL.MinibatchSize
+	if mbSize > n {
+		mbSize = n
+	}
+
+	var u Update
+	samples := 0
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+
+	for epoch := 0; epoch < t.cfg.RL.Epochs; epoch++ {
+		t.rng.Shuffle(n, func(i, j int) { order[i], order[j] = order[j], order[i] })
+
+		for start := 0; start < n; start += mbSize {
+			end := start + mbSize
+			if end > n {
+				end = n
+			}
+			scale := 1 / float64(end-start)
+
+			for _, i := range order[start:end] {
+				obs := b.Observation(i)
+				action := b.Actions[i]
+				t.policyProbs(obs)
+				logp := logProb(t.probs[action])
+				ratio := math.Exp(logp - b.LogProbs[i])
+
+				// The clipped objective has zero gradient once the ratio
+				// has moved past the clip range in the advantage direction
+				clipped := (adv[i] > 0 && ratio > 1+eps) || (adv[i] < 0 && ratio < 1-eps)
+				coef := ratio * adv[i]
+				if clipped {
+					coef = 0
+					u.ClipFrac++
+				}
+
+				u.PolicyLoss -= math.Min(ratio*adv[i], clip(ratio, 1-eps, 1+eps)*adv[i])
+				u.KL += b.LogProbs[i] - logp
+				u.Entropy += t.policyBackward(obs, action, coef, scale)
+				u.ValueLoss += 0.5 * t.valueBackward(obs, returns[i], scale)
+				samples++
+			}
+			t.step()
+		}
+	}
+
+	m := float64(samples)
+	u.PolicyLoss /= m
+	u.ValueLoss /= m
+	u.Entropy /= m
+	u.KL /= m
+	u.ClipFrac /= m
+	return u
+}
+
+func clip(x, lo, hi float64) float64 {
+	return math.Max(lo, math.Min(hi, x))
+}
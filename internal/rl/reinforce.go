@@ -0,0 +1,30 @@
+package rl
+
+// updateREINFORCE applies one Monte Carlo policy-gradient step. The value
+// network's estimate at collection time is subtracted from the return as a
+// baseline, and the value network is regressed onto the returns.
+func (t *Trainer) updateREINFORCE(b *Batch) Update {
+	n := b.Len()
+	if n == 0 {
+		return Update{}
+	}
+
+	returns := discountedReturns(b.Rewards, b.Dones, t.cfg.RL.Gamma)
+	adv := make([]float64, n)
+	for i := range adv {
+		adv[i] = returns[i] - b.Values[i]
+	}
+	normalize(adv)
+
+	var u Update
+	scale := 1 / float64(n)
+	for i := 0; i < n; i++ {
+		obs := b.Observation(i)
+		t.policyProbs(obs)
+		u.PolicyLoss -= logProb(t.probs[b.Actions[i]]) * adv[i] * scale
+		u.Entropy += t.policyBackward(obs, b.Actions[i], adv[i], scale) * scale
+		u.ValueLoss += 0.5 * t.valueBackward(obs, returns[i], scale) * scale
+	}
+	t.step()
+	return u
+}
@@ -0,0 +1,69 @@
+package rl
+
+import (
+	"math/rand"
+)
+
+// ReplayBuffer is a fixed-capacity ring of transitions for off-policy
+// learning. Once full, the oldest transitions are overwritten.
+type ReplayBuffer struct {
+	obsDim  int
+	obs     []float32
+	next    []float32
+	actions []int
+	rewards []float64
+	dones   []bool
+	size    int
+	pos     int
+}
+
+// NewReplayBuffer allocates a buffer holding up to capacity transitions
+func NewReplayBuffer(capacity, obsDim int) *ReplayBuffer {
+	return &ReplayBuffer{
+		obsDim:  obsDim,
+		obs:     make([]float32, capacity*obsDim),
+		next:    make([]float32, capacity*obsDim),
+		actions: make([]int, capacity),
+		rewards: make([]float64, capacity),
+		dones:   make([]bool, capacity),
+	}
+}
+
+// Add stores a transition. next is ignored for terminal transitions.
+func (r *ReplayBuffer) Add(obs []float32, action int, reward float64, next []float32, done bool) {
+	i := r.pos
+	copy(r.obs[i*r.obsDim:(i+1)*r.obsDim], obs)
+	if !done {
+		copy(r.next[i*r.obsDim:(i+1)*r.obsDim], next)
+	}
+	r.actions[i] = action
+	r.rewards[i] = reward
+	r.dones[i] = done
+
+	r.pos = (r.pos + 1) % len(r.actions)
+	if r.size < len(r.actions) {
+		r.size++
+	}
+}
+
+// Len returns the number of stored transitions
+func (r *ReplayBuffer) Len() int {
+	return r.size
+}
+
+// Sample fills idx with uniformly drawn transition indices
+func (r *ReplayBuffer) Sample(idx []int, rng *rand.Rand) {
+	for k := range idx {
+		idx[k] = rng.Intn(r.size)
+	}
+}
+
+// Observation returns the observation of transition i
+func (r *ReplayBuffer) Observation(i int) []float32 {
+	return r.obs[i*r.obsDim : (i+1)*r.obsDim]
+}
+
+// Next returns the observation following transition i
+func (r *ReplayBuffer) Next(i int) []float32 {
+	return r.next[i*r.obsDim : (i+1)*r.obsDim]
+}
@@ -0,0 +1,134 @@
+package rl
+
+import (
+	"math"
+	"math/rand"
+
+	"snakeai/internal/env"
+	"snakeai/internal/eval"
+)
+
+// Batch holds the transitions of whole episodes played by one policy
+type Batch struct {
+	ObsDim   int
+	Obs      []float32 // Len() * ObsDim observations
+	Actions  []int
+	LogProbs []float64 // log pi(action) under the rollout policy
+	Values   []float64 // value estimates at collection time
+	Rewards  []float64
+	Dones    []bool // episode ended after this transition
+	Episodes []env.EpisodeStats
+}
+
+// Len returns the number of transitions
+func (b *Batch) Len() int {
+	return len(b.Actions)
+}
+
+// Observation returns the observation of transition i
+func (b *Batch) Observation(i int) []float32 {
+	return b.Obs[i*b.ObsDim : (i+1)*b.ObsDim]
+}
+
+// collect plays n episodes, sampling actions from the policy. Seeds follow
+// on from the config seed so runs are reproducible.
+func (t *Trainer) collect(n int) *Batch {
+	b := &Batch{ObsDim: t.cfg.ObsDim()}
+	rnd := eval.Randomization(t.cfg.Env.Randomize)
+
+	for ep := 0; ep < n; ep++ {
+		seed := uint32(t.cfg.Seed) + uint32(t.episodes)
+		t.episodes++
+		game := eval.NewGame(t.cfg.Env, rnd, seed)
+
+		for game.Alive {
+			obs := t.features.Extract(game)
+			t.policyProbs(obs)
+			action := sample(t.probs, t.rng)
+			t.value.Forward(obs)
+
+			b.Obs = append(b.Obs, obs...)
+			b.Actions = append(b.Actions, action)
+			b.LogProbs = append(b.LogProbs, logProb(t.probs[action]))
+			b.Values = append(b.Values, float64(t.value.Outputs()[0]))
+
+			res := game.Step(env.Action(action))
+			b.Rewards = append(b.Rewards, res.Reward)
+			b.Dones = append(b.Dones, res.Done)
+		}
+
+		stats := game.Stats(seed)
+		t.evaluator.Score(&stats)
+		b.Episodes = append(b.Episodes, stats)
+	}
+
+	t.envSteps += int64(b.Len())
+	return b
+}
+
+// sample draws an index from a categorical distribution
+func sample(probs []float64, rng *rand.Rand) int {
+	r := rng.Float64()
+	for i, p := range probs {
+		r -= p
+		if r < 0 {
+			return i
+		}
+	}
+	return len(probs) - 1
+}
+
+// discountedReturns computes the discounted reward-to-go of every
+// transition, restarting at episode boundaries
+func discountedReturns(rewards []float64, dones []bool, gamma float64) []float64 {
+	returns := make([]float64, len(rewards))
+	g := 0.0
+	for i := len(rewards) - 1; i >= 0; i-- {
+		if dones[i] {
+			g = 0
+		}
+		g = rewards[i] + gamma*g
+		returns[i] = g
+	}
+	return returns
+}
+
+// gae computes generalised advantage estimates and the matching value
+// targets. Episodes always run to completion, so the value after a terminal
+// transition is zero.
+func gae(rewards, values []float64, dones []bool, gamma, lambda float64) (adv, returns []float64) {
+	adv = make([]float64, len(rewards))
+	returns = make([]float64, len(rewards))
+	next, a := 0.0, 0.0
+	for i := len(rewards) - 1; i >= 0; i-- {
+		if dones[i] {
+			next, a = 0, 0
+		}
+		delta := rewards[i] + gamma*next - values[i]
+		a = delta + gamma*lambda*a
+		adv[i] = a
+		returns[i] = a + values[i]
+		next = values[i]
+	}
+	return adv, returns
+}
+
+// normalize rescales x to zero mean and unit variance in place
+func normalize(x []float64) {
+	if len(x) == 0 {
+		return
+	}
+	mean := 0.0
+	for _, v := range x {
+		mean += v
+	}
+	mean /= float64(len(x))
+	variance := 0.0
+	for _, v := range x {
+		variance += (v - mean) * (v - mean)
+	}
+	std := math.Sqrt(variance/float64(len(x))) + 1e-8
+	for i := range x {
+		x[i] = (x[i] - mean) / std
+	}
+}
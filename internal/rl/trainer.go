@@ -0,0 +1,190 @@
+// Package rl trains the snake policy network by gradient descent on per-tick
+// rewards, as an alternative to the genetic algorithm. The policy uses the
+// same MLP layout as GA genomes, so trained weights are saved and played back
+// as ordinary champions.
+package rl
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"snakeai/internal/config"
+	"snakeai/internal/env"
+	"snakeai/internal/eval"
+	"snakeai/internal/nn"
+)
+
+// Algorithms lists the supported gradient-based algorithms
+var Algorithms = []string{"reinforce", "ppo", "dqn"}
+
+// Trainer runs policy-gradient training with a learned value baseline, or
+// DQN with the policy network as the Q-network
+type Trainer struct {
+	cfg       *config.Config
+	algo      string
+	evaluator *eval.Evaluator
+	features  *env.FeatureExtractor
+	rng       *rand.Rand
+
+	policy    *nn.MLP // action logits, same layout as a GA genome
+	value     *nn.MLP // state value estimate (single output)
+	policyOpt *nn.Adam
+	valueOpt  *nn.Adam
+
+	// DQN only
+	target *nn.MLP // periodically synced copy of the Q-network
+	replay *ReplayBuffer
+
+	episodes int   // episodes played, used to derive rollout seeds
+	envSteps int64 // environment steps consumed by training
+
+	// Scratch buffers reused across updates
+	probs     []float64
+	dLogits   []float32
+	dValue    []float32
+	pGrad     []float32
+	vGrad     []float32
+	obsBuf    []float32
+	sampleIdx []int
+}
+
+// Update summarises one training iteration
+type Update struct {
+	Episodes   []env.EpisodeStats // scored rollout episodes
+	Steps      int                // transitions collected
+	PolicyLoss float64
+	ValueLoss  float64 // value baseline loss, or mean TD loss for DQN
+	Entropy    float64
+	KL         float64 // approximate KL to the rollout policy (PPO only)
+	ClipFrac   float64 // fraction of clipped ratios (PPO only)
+	Epsilon    float64 // exploration rate at the end of the iteration (DQN only)
+}
+
+// NewTrainer creates a trainer for algo. The evaluator scores rollout
+// episodes with the configured fitness so they log like GA generations.
+func NewTrainer(cfg *config.Config, algo string, evaluator *eval.Evaluator) (*Trainer, error) {
+	known := false
+	for _, a := range Algorithms {
+		if a == algo {
+			known = true
+		}
+	}
+	if !known {
+		return nil, fmt.Errorf("rl: unknown algorithm %q", algo)
+	}
+
+	rng := rand.New(rand.NewSource(cfg.Seed))
+	policy := nn.NewMLP(cfg.ObsDim(), cfg.NN.Hidden1, cfg.NN.Hidden2, 3)
+	policy.SetWeights(nn.RandomGenome(policy.GenomeSize(), rng))
+	value := nn.NewMLP(cfg.ObsDim(), cfg.NN.Hidden1, cfg.NN.Hidden2, 1)
+	value.SetWeights(nn.RandomGenome(value.GenomeSize(), rng))
+
+	t := &Trainer{
+		cfg:       cfg,
+		algo:      algo,
+		evaluator: evaluator,
+		features:  env.NewFeatureExtractor(cfg.Track.Obs),
+		rng:       rng,
+		policy:    policy,
+		value:     value,
+		policyOpt: nn.NewAdam(policy.GenomeSize(), cfg.RL.LearningRate),
+		valueOpt:  nn.NewAdam(value.GenomeSize(), cfg.RL.ValueLR),
+		probs:     make([]float64, 3),
+		dLogits:   make([]float32, 3),
+		dValue:    make([]float32, 1),
+		pGrad:     make([]float32, policy.GenomeSize()),
+		vGrad:     make([]float32, value.GenomeSize()),
+	}
+	if algo == "dqn" {
+		t.target = nn.NewMLP(cfg.ObsDim(), cfg.NN.Hidden1, cfg.NN.Hidden2, 3)
+		t.target.SetWeights(policy.Weights)
+		t.replay = NewReplayBuffer(cfg.RL.ReplaySize, cfg.ObsDim())
+		t.obsBuf = make([]float32, cfg.ObsDim())
+		t.sampleIdx = make([]int, cfg.RL.ReplayBatch)
+	}
+	return t, nil
+}
+
+// Iterate collects a batch of episodes with the current policy and applies
+// one round of updates (for DQN, updates are interleaved with the episodes)
+func (t *Trainer) Iterate() Update {
+	if t.algo == "dqn" {
+		return t.iterateDQN(t.cfg.RL.EpisodesPerIter)
+	}
+
+	batch := t.collect(t.cfg.RL.EpisodesPerIter)
+
+	var u Update
+	if t.algo == "ppo" {
+		u = t.updatePPO(batch)
+	} else {
+		u = t.updateREINFORCE(batch)
+	}
+	u.Episodes = batch.Episodes
+	u.Steps = batch.Len()
+	return u
+}
+
+// Policy returns a copy of the policy weights in genome layout
+func (t *Trainer) Policy() []float32 {
+	return nn.CloneGenome(t.policy.Weights)
+}
+
+// EnvSteps returns the number of environment steps consumed by training
+func (t *Trainer) EnvSteps() int64 {
+	return t.envSteps
+}
+
+// policyProbs runs the policy on obs and fills t.probs. The activations stay
+// cached for a following policyBackward on the same observation.
+func (t *Trainer) policyProbs(obs []float32) {
+	t.policy.Forward(obs)
+	nn.Softmax(t.policy.Outputs(), t.probs)
+}
+
+// policyBackward accumulates the gradient of
+// scale*(-coef*log pi(action) - entropyCoef*H(pi)) for the last policyProbs
+// call and returns the policy entropy
+func (t *Trainer) policyBackward(obs []float32, action int, coef, scale float64) float64 {
+	entropy := 0.0
+	for _, p := range t.probs {
+		entropy -= p * logProb(p)
+	}
+	c := t.cfg.RL.EntropyCoef
+	for k, p := range t.probs {
+		d := coef * p
+		if k == action {
+			d -= coef
+		}
+		d += c * p * (logProb(p) + entropy)
+		t.dLogits[k] = float32(scale * d)
+	}
+	t.policy.Backward(obs, t.dLogits, t.pGrad)
+	return entropy
+}
+
+// valueBackward accumulates the gradient of scale*0.5*(V(obs)-target)^2 and
+// returns the squared error
+func (t *Trainer) valueBackward(obs []float32, target, scale float64) float64 {
+	t.value.Forward(obs)
+	diff := float64(t.value.Outputs()[0]) - target
+	t.dValue[0] = float32(scale * diff)
+	t.value.Backward(obs, t.dValue, t.vGrad)
+	return diff * diff
+}
+
+// step clips and applies the accumulated gradients, then clears them
+func (t *Trainer) step() {
+	nn.ClipGradNorm(t.pGrad, t.cfg.RL.MaxGradNorm)
+	nn.ClipGradNorm(t.vGrad, t.cfg.RL.MaxGradNorm)
+	t.policyOpt.Step(t.policy.Weights, t.pGrad)
+	t.valueOpt.Step(t.value.Weights, t.vGrad)
+	clear(t.pGrad)
+	clear(t.vGrad)
+}
+
+// logProb is a log that stays finite for probabilities that underflow to 0
+func logProb(p float64) float64 {
+	return math.Log(math.Max(p, 1e-12))
+}